@@ -76,13 +76,37 @@ func Backend(conf *logical.BackendConfig) *backend {
 
 		PathsSpecial: &logical.Paths{
 			Unauthenticated: []string{
-				"cert/*",
+				// Not cert/*, so that escrowed keys under
+				// cert/:serial/private-key remain authenticated.
+				"cert/+",
+				"cert/+/raw",
+				"cert/+/raw/pem",
+				"cert/+/both",
+				"cert/+/fullchain/pem",
+				"cert/+/revoked",
+				"cert/+/status",
 				"ca/pem",
+				"ca/info",
+				"ca/key-info",
 				"ca_chain",
+				"ca_chain/json",
+				"ca_chain/p7b",
+				"ca_chain/p7b/pem",
+				"ca_chain/verify",
 				"ca",
+				"crl/combined",
+				"crl/combined/pem",
 				"crl/delta",
+				"crl/delta/json",
+				"crl/delta/metadata",
 				"crl/delta/pem",
+				"crl/delta/with-issuer",
+				"crl/json",
+				"crl/with-issuer",
+				"crl/metadata",
 				"crl/pem",
+				"crl/pkcs7",
+				"crl/pkcs7/pem",
 				"crl",
 				"issuer/+/crl/der",
 				"issuer/+/crl/pem",
@@ -93,9 +117,10 @@ func Backend(conf *logical.BackendConfig) *backend {
 				"issuer/+/pem",
 				"issuer/+/der",
 				"issuer/+/json",
+				"issuer/+/info",
 				"issuers/", // LIST operations append a '/' to the requested path
 				"ocsp",     // OCSP POST
-				"ocsp/*",   // OCSP GET
+				"ocsp/*",   // OCSP GET and response verification
 
 				// ACME paths are added below
 			},
@@ -107,6 +132,9 @@ func Backend(conf *logical.BackendConfig) *backend {
 				clusterConfigPath,
 				"crls/",
 				"certs/",
+				escrowedKeyPrefix,
+				ocspOnlyRevocationPrefix,
+				certIssuancePrefix,
 				acmePathPrefix,
 			},
 
@@ -119,6 +147,7 @@ func Backend(conf *logical.BackendConfig) *backend {
 				legacyCertBundlePath,
 				legacyCertBundleBackupPath,
 				keyPrefix,
+				escrowedKeyPrefix,
 			},
 		},
 
@@ -135,24 +164,35 @@ func Backend(conf *logical.BackendConfig) *backend {
 			pathConfigCRL(&b),
 			pathConfigURLs(&b),
 			pathConfigCluster(&b),
+			pathConfigIssuance(&b),
 			pathSignVerbatim(&b),
 			pathSign(&b),
+			pathSignPublicKey(&b),
 			pathIssue(&b),
 			pathRotateCRL(&b),
 			pathRotateDeltaCRL(&b),
+			pathCRLAudit(&b),
 			pathRevoke(&b),
 			pathRevokeWithKey(&b),
+			pathRevokeByPublicKey(&b),
+			pathHoldCert(&b),
+			pathReleaseCert(&b),
 			pathListCertsRevoked(&b),
+			pathListCertsRevokedDetailed(&b),
 			pathTidy(&b),
 			pathTidyCancel(&b),
 			pathTidyStatus(&b),
 			pathConfigAutoTidy(&b),
+			pathReindex(&b),
+			pathMigrateLegacy(&b),
 
 			// Issuer APIs
 			pathListIssuers(&b),
 			pathGetIssuer(&b),
 			pathGetUnauthedIssuer(&b),
+			pathGetIssuerInfo(&b),
 			pathGetIssuerCRL(&b),
+			pathGetIssuerRevocationConfig(&b),
 			pathImportIssuer(&b),
 			pathIssuerIssue(&b),
 			pathIssuerSign(&b),
@@ -176,15 +216,39 @@ func Backend(conf *logical.BackendConfig) *backend {
 
 			// Fetch APIs have been lowered to favor the newer issuer API endpoints
 			pathFetchCA(&b),
+			pathFetchCAInfo(&b),
+			pathFetchCAKeyInfo(&b),
 			pathFetchCAChain(&b),
+			pathFetchCAChainJSON(&b),
+			pathFetchCAChainP7B(&b),
+			pathFetchCAChainVerify(&b),
 			pathFetchCRL(&b),
+			pathFetchCombinedCRL(&b),
+			pathFetchCRLMetadata(&b),
+			pathFetchCRLJSON(&b),
+			pathFetchCRLWithIssuer(&b),
 			pathFetchCRLViaCertPath(&b),
 			pathFetchValidRaw(&b),
+			pathFetchValidBoth(&b),
+			pathFetchCertFullChain(&b),
 			pathFetchValid(&b),
+			pathFetchEscrowedKey(&b),
+			pathFetchRevocationStatus(&b),
+			pathFetchCertStatus(&b),
+			pathFetchRevocationEntry(&b),
 			pathFetchListCerts(&b),
+			pathFetchListCertNames(&b),
+			pathFetchListExpiredCerts(&b),
 			pathFetchListCertsDetailed(&b),
+			pathFetchCertCount(&b),
+			pathFetchLatestCert(&b),
+			pathFetchFindCerts(&b),
+			pathFetchCertBatch(&b),
+			pathFetchCTExport(&b),
+			pathRenewExpiringCerts(&b),
 
 			// OCSP APIs
+			pathOcspVerify(&b),
 			buildPathOcspGet(&b),
 			buildPathOcspPost(&b),
 
@@ -304,9 +368,15 @@ type backend struct {
 	pkiStorageVersion atomic.Value
 	crlBuilder        *crlBuilder
 
+	// Cached copy of config/issuance, consulted on every issuance.
+	issuanceConfig atomic.Pointer[issuanceConfigEntry]
+
 	// Write lock around issuers and keys.
 	issuersLock sync.RWMutex
 
+	// Last time a raw CA fetch logged an expiry warning, per issuer ID.
+	caExpiryWarningLogged sync.Map
+
 	// Context around ACME operations
 	acmeState       *acmeState
 	acmeAccountLock sync.RWMutex // (Write) Locked on Tidy, (Read) Locked on Account Creation
@@ -391,6 +461,10 @@ func (b *backend) initialize(ctx context.Context, _ *logical.InitializationReque
 		return err
 	}
 
+	if err := b.reloadIssuanceConfig(sc); err != nil {
+		return err
+	}
+
 	err = b.acmeState.Initialize(b, sc)
 	if err != nil {
 		return err
@@ -508,9 +582,32 @@ func (b *backend) invalidate(ctx context.Context, key string) {
 		b.acmeState.markConfigDirty()
 	case key == storageIssuerConfig:
 		b.crlBuilder.invalidateCRLBuildTime()
+	case key == issuanceConfigPath:
+		if err := b.reloadIssuanceConfig(b.makeStorageContext(ctx, b.storage)); err != nil {
+			b.Logger().Error("failed to reload issuance configuration", "error", err)
+		}
 	}
 }
 
+func (b *backend) reloadIssuanceConfig(sc *storageContext) error {
+	config, err := sc.getIssuanceConfig()
+	if err != nil {
+		return err
+	}
+
+	b.issuanceConfig.Store(config)
+	return nil
+}
+
+// getIssuanceConfig returns the cached issuance configuration, with all
+// limits disabled when none was loaded.
+func (b *backend) getIssuanceConfig() *issuanceConfigEntry {
+	if config := b.issuanceConfig.Load(); config != nil {
+		return config
+	}
+	return &issuanceConfigEntry{}
+}
+
 func (b *backend) periodicFunc(ctx context.Context, request *logical.Request) error {
 	sc := b.makeStorageContext(ctx, request.Storage)
 
@@ -576,16 +673,19 @@ func (b *backend) periodicFunc(ctx context.Context, request *logical.Request) er
 			return err
 		}
 
-		if !config.Enabled || config.Interval <= 0*time.Second {
+		if !config.Enabled || (len(config.Schedule) == 0 && config.Interval <= 0*time.Second) {
 			return nil
 		}
 
 		// Check if we should run another tidy...
 		now := time.Now()
 		b.tidyStatusLock.RLock()
-		nextOp := b.lastTidy.Add(config.Interval)
+		nextOp, err := config.nextAutoTidy(b.lastTidy)
 		b.tidyStatusLock.RUnlock()
-		if now.Before(nextOp) {
+		if err != nil {
+			return err
+		}
+		if nextOp.IsZero() || now.Before(nextOp) {
 			return nil
 		}
 
@@ -12,8 +12,11 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -24,6 +27,7 @@ import (
 	"math/big"
 	mathrand "math/rand"
 	"net"
+	"net/http"
 	"net/url"
 	"reflect"
 	"sort"
@@ -51,6 +55,7 @@ import (
 	"github.com/openbao/openbao/builtin/credential/userpass"
 	logicaltest "github.com/openbao/openbao/helper/testhelpers/logical"
 	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/sdk/v2/framework"
 	"github.com/openbao/openbao/sdk/v2/helper/certutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	"github.com/openbao/openbao/vault"
@@ -3344,6 +3349,107 @@ func TestBackend_OID_SANs(t *testing.T) {
 	}
 }
 
+func TestBackend_DirectoryNameSANs(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"ttl":         "40h",
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allowed_domains":             []string{"example.com"},
+		"allow_bare_domains":          true,
+		"allowed_directory_name_sans": []string{"CN=*,O=Example,C=US"},
+	})
+	require.NoError(t, err)
+
+	// Invalid DNs are rejected on the role.
+	_, err = CBWrite(b, s, "roles/bad", map[string]interface{}{
+		"allowed_directory_name_sans": []string{"not a dn"},
+	})
+	require.Error(t, err)
+
+	// Invalid DN syntax is rejected.
+	_, err = CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name":         "example.com",
+		"directory_name_sans": []string{"CN=Jane Doe,O=Example,"},
+	})
+	require.Error(t, err)
+
+	// DNs not matching the allowlist are rejected.
+	_, err = CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name":         "example.com",
+		"directory_name_sans": []string{"CN=Jane Doe,O=Other,C=US"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by this role")
+
+	resp, err = CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name":         "example.com",
+		"directory_name_sans": []string{"CN=Jane Doe+UID=jdoe,O=Example,C=US"},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, []string{"example.com"}, cert.DNSNames)
+
+	var sanExt *pkix.Extension
+	for i, ext := range cert.Extensions {
+		if ext.Id.Equal(asn1.ObjectIdentifier(oidExtensionSubjectAltName)) {
+			sanExt = &cert.Extensions[i]
+		}
+	}
+	require.NotNil(t, sanExt, "expected SAN extension")
+
+	var names []asn1.RawValue
+	rest, err := asn1.Unmarshal(sanExt.Value, &names)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+
+	var directoryNames []pkix.RDNSequence
+	for _, name := range names {
+		if name.Class != asn1.ClassContextSpecific || name.Tag != 4 {
+			continue
+		}
+		require.True(t, name.IsCompound)
+
+		var rdns pkix.RDNSequence
+		rest, err := asn1.Unmarshal(name.Bytes, &rdns)
+		require.NoError(t, err)
+		require.Empty(t, rest)
+		directoryNames = append(directoryNames, rdns)
+	}
+	require.Len(t, directoryNames, 1)
+
+	rdns := directoryNames[0]
+	require.Len(t, rdns, 3)
+	require.Len(t, rdns[0], 1)
+	require.True(t, rdns[0][0].Type.Equal(asn1.ObjectIdentifier{2, 5, 4, 6}))
+	require.Equal(t, "US", rdns[0][0].Value)
+	require.Len(t, rdns[1], 1)
+	require.True(t, rdns[1][0].Type.Equal(asn1.ObjectIdentifier{2, 5, 4, 10}))
+	require.Equal(t, "Example", rdns[1][0].Value)
+	require.Len(t, rdns[2], 2)
+	require.True(t, rdns[2][0].Type.Equal(asn1.ObjectIdentifier{2, 5, 4, 3}))
+	require.Equal(t, "Jane Doe", rdns[2][0].Value)
+	require.True(t, rdns[2][1].Type.Equal(asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}))
+	require.Equal(t, "jdoe", rdns[2][1].Value)
+
+	// Roles without an allowlist don't permit directory name SANs.
+	_, err = CBWrite(b, s, "roles/none", map[string]interface{}{
+		"allowed_domains":    []string{"example.com"},
+		"allow_bare_domains": true,
+	})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "issue/none", map[string]interface{}{
+		"common_name":         "example.com",
+		"directory_name_sans": []string{"CN=Jane Doe,O=Example,C=US"},
+	})
+	require.Error(t, err)
+}
+
 func TestBackend_AllowedSerialNumbers(t *testing.T) {
 	t.Parallel()
 	b, s := CreateBackendWithStorage(t)
@@ -3882,6 +3988,7 @@ func TestReadWriteDeleteRoles(t *testing.T) {
 		"allow_subdomains":                   false,
 		"allow_wildcard_certificates":        true,
 		"allowed_other_sans":                 []interface{}{},
+		"allowed_directory_name_sans":        []interface{}{},
 		"allowed_uri_sans":                   []interface{}{},
 		"basic_constraints_valid_for_non_ca": false,
 		"key_usage":                          []interface{}{"DigitalSignature", "KeyAgreement", "KeyEncipherment"},
@@ -3903,8 +4010,23 @@ func TestReadWriteDeleteRoles(t *testing.T) {
 		"organization":                       []interface{}{},
 		"province":                           []interface{}{},
 		"street_address":                     []interface{}{},
+		"subject_dn_order":                   []interface{}{},
 		"code_signing_flag":                  false,
 		"issuer_ref":                         "default",
+		"allowed_issuers":                    []interface{}{},
+		"ra_namespaces":                      []interface{}{},
+		"ra_extension_oid":                   "",
+		"require_ra_credential":              false,
+		"issuer_selection":                   "role",
+		"ttl_issuer_fraction":                json.Number("0"),
+		"smime_capabilities":                 []interface{}{},
+		"idn_handling":                       "preserve",
+		"allow_public_key_signing":           false,
+		"escrow_private_key":                 false,
+		"enforce_cabf_ttl":                   false,
+		"cabf_ttl_behavior":                  "truncate",
+		"allowed_san_types":                  []interface{}{},
+		"ocsp_only_revocation":               false,
 		"cn_validations":                     []interface{}{"email", "hostname"},
 		"allowed_user_ids":                   []interface{}{},
 	}
@@ -4267,6 +4389,7 @@ func TestBackend_RevokePlusTidy_Intermediate(t *testing.T) {
 			"acme_account_revoked_count":            json.Number("0"),
 			"acme_account_deleted_count":            json.Number("0"),
 			"total_acme_account_count":              json.Number("0"),
+			"next_tidy_run":                         nil,
 		}
 		// Let's copy the times from the response so that we can use deep.Equal()
 		timeStarted, ok := tidyStatus.Data["time_started"]
@@ -7212,12 +7335,24 @@ func TestProperAuthing(t *testing.T) {
 	eabKid := "13b80844-e60d-42d2-b7e9-152a8e834b90"
 	paths := map[string]pathAuthChecker{
 		"ca_chain":                               shouldBeUnauthedReadList,
+		"ca_chain/json":                          shouldBeUnauthedReadList,
+		"ca_chain/p7b":                           shouldBeUnauthedReadList,
+		"ca_chain/p7b/pem":                       shouldBeUnauthedReadList,
+		"ca_chain/verify":                        shouldBeUnauthedReadList,
 		"cert/ca_chain":                          shouldBeUnauthedReadList,
 		"ca":                                     shouldBeUnauthedReadList,
 		"ca/pem":                                 shouldBeUnauthedReadList,
+		"ca/info":                                shouldBeUnauthedReadList,
+		"ca/key-info":                            shouldBeUnauthedReadList,
 		"cert/" + serial:                         shouldBeUnauthedReadList,
 		"cert/" + serial + "/raw":                shouldBeUnauthedReadList,
 		"cert/" + serial + "/raw/pem":            shouldBeUnauthedReadList,
+		"cert/" + serial + "/both":               shouldBeUnauthedReadList,
+		"cert/" + serial + "/fullchain/pem":      shouldBeUnauthedReadList,
+		"cert/" + serial + "/private-key":        shouldBeAuthed,
+		"cert/" + serial + "/revoked":            shouldBeUnauthedReadList,
+		"cert/" + serial + "/revocation":         shouldBeAuthed,
+		"cert/" + serial + "/status":             shouldBeUnauthedReadList,
 		"cert/crl":                               shouldBeUnauthedReadList,
 		"cert/crl/raw":                           shouldBeUnauthedReadList,
 		"cert/crl/raw/pem":                       shouldBeUnauthedReadList,
@@ -7225,22 +7360,44 @@ func TestProperAuthing(t *testing.T) {
 		"cert/delta-crl/raw":                     shouldBeUnauthedReadList,
 		"cert/delta-crl/raw/pem":                 shouldBeUnauthedReadList,
 		"certs":                                  shouldBeAuthed,
+		"certs/batch":                            shouldBeAuthed,
+		"certs/count":                            shouldBeAuthed,
+		"certs/ct-export":                        shouldBeAuthed,
 		"certs/detailed":                         shouldBeAuthed,
+		"certs/find":                             shouldBeAuthed,
+		"certs/latest":                           shouldBeAuthed,
+		"certs/names":                            shouldBeAuthed,
+		"certs/expired":                          shouldBeAuthed,
+		"certs/renew-expiring":                   shouldBeAuthed,
 		"certs/revoked":                          shouldBeAuthed,
+		"certs/revoked/detailed":                 shouldBeAuthed,
 		"config/acme":                            shouldBeAuthed,
 		"config/auto-tidy":                       shouldBeAuthed,
 		"config/ca":                              shouldBeAuthed,
 		"config/cluster":                         shouldBeAuthed,
+		"config/issuance":                        shouldBeAuthed,
 		"config/crl":                             shouldBeAuthed,
 		"config/issuers":                         shouldBeAuthed,
 		"config/keys":                            shouldBeAuthed,
 		"config/urls":                            shouldBeAuthed,
 		"crl":                                    shouldBeUnauthedReadList,
+		"crl/audit":                              shouldBeAuthed,
+		"crl/combined":                           shouldBeUnauthedReadList,
+		"crl/combined/pem":                       shouldBeUnauthedReadList,
+		"crl/json":                               shouldBeUnauthedReadList,
+		"crl/metadata":                           shouldBeUnauthedReadList,
 		"crl/pem":                                shouldBeUnauthedReadList,
 		"crl/delta":                              shouldBeUnauthedReadList,
+		"crl/delta/json":                         shouldBeUnauthedReadList,
+		"crl/delta/metadata":                     shouldBeUnauthedReadList,
 		"crl/delta/pem":                          shouldBeUnauthedReadList,
+		"crl/delta/with-issuer":                  shouldBeUnauthedReadList,
+		"crl/pkcs7":                              shouldBeUnauthedReadList,
+		"crl/pkcs7/pem":                          shouldBeUnauthedReadList,
 		"crl/rotate":                             shouldBeAuthed,
 		"crl/rotate-delta":                       shouldBeAuthed,
+		"crl/with-issuer":                        shouldBeUnauthedReadList,
+		"hold":                                   shouldBeAuthed,
 		"intermediate/cross-sign":                shouldBeAuthed,
 		"intermediate/generate/exported":         shouldBeAuthed,
 		"intermediate/generate/internal":         shouldBeAuthed,
@@ -7250,10 +7407,12 @@ func TestProperAuthing(t *testing.T) {
 		"issue/test":                             shouldBeAuthed,
 		"issuer/default":                         shouldBeAuthed,
 		"issuer/default/der":                     shouldBeUnauthedReadList,
+		"issuer/default/info":                    shouldBeUnauthedReadList,
 		"issuer/default/json":                    shouldBeUnauthedReadList,
 		"issuer/default/pem":                     shouldBeUnauthedReadList,
 		"issuer/default/crl":                     shouldBeUnauthedReadList,
 		"issuer/default/crl/pem":                 shouldBeUnauthedReadList,
+		"issuer/default/revocation-config":       shouldBeAuthed,
 		"issuer/default/crl/der":                 shouldBeUnauthedReadList,
 		"issuer/default/crl/delta":               shouldBeUnauthedReadList,
 		"issuer/default/crl/delta/der":           shouldBeUnauthedReadList,
@@ -7284,10 +7443,15 @@ func TestProperAuthing(t *testing.T) {
 		"keys/generate/exported":                 shouldBeAuthed,
 		"keys/generate/kms":                      shouldBeAuthed,
 		"keys/import":                            shouldBeAuthed,
+		"legacy/migrate":                         shouldBeAuthed,
 		"ocsp":                                   shouldBeUnauthedWriteOnly,
 		"ocsp/dGVzdAo=":                          shouldBeUnauthedReadList,
+		"ocsp/verify":                            shouldBeUnauthedWriteOnly,
+		"reindex":                                shouldBeAuthed,
+		"release":                                shouldBeAuthed,
 		"revoke":                                 shouldBeAuthed,
 		"revoke-with-key":                        shouldBeAuthed,
+		"revoke/by-public-key":                   shouldBeAuthed,
 		"roles/test":                             shouldBeAuthed,
 		"roles":                                  shouldBeAuthed,
 		"root":                                   shouldBeAuthed,
@@ -7305,6 +7469,7 @@ func TestProperAuthing(t *testing.T) {
 		"sign-verbatim":                          shouldBeAuthed,
 		"sign-verbatim/test":                     shouldBeAuthed,
 		"sign/test":                              shouldBeAuthed,
+		"sign-public-key/test":                   shouldBeAuthed,
 		"tidy":                                   shouldBeAuthed,
 		"tidy-cancel":                            shouldBeAuthed,
 		"tidy-status":                            shouldBeAuthed,
@@ -7732,6 +7897,1614 @@ func TestPaginatedListing(t *testing.T) {
 	require.Equal(t, resp.Data["keys"], all_ids[3:5])
 }
 
+func TestStructuredWarnings(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name":         "Root R1",
+		"ttl":                 "8h",
+		"structured_warnings": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.NotEmpty(t, resp.Warnings)
+	structured := resp.Data["structured_warnings"].([]structuredWarning)
+	require.Len(t, structured, len(resp.Warnings))
+	require.Equal(t, warningCodeAIAMissing, structured[0].Code)
+	require.Equal(t, resp.Warnings[0], structured[0].Message)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"max_ttl":        "1h",
+		"key_type":       "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	// Without the option, only plain-text warnings are returned.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"ttl":         "2h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.NotEmpty(t, resp.Warnings)
+	require.NotContains(t, resp.Data, "structured_warnings")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name":         "example.com",
+		"ttl":                 "2h",
+		"key_type":            "rsa",
+		"structured_warnings": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issue/example"), logical.UpdateOperation), resp, true)
+	structured = resp.Data["structured_warnings"].([]structuredWarning)
+	require.Len(t, structured, 2)
+	require.Equal(t, warningCodeTTLTruncated, structured[0].Code)
+	require.Equal(t, "ttl", structured[0].Field)
+	require.Equal(t, warningCodeKeyParamsIgnored, structured[1].Code)
+	require.Equal(t, "key_type", structured[1].Field)
+	require.Equal(t, resp.Warnings[1], structured[1].Message)
+
+	// Codes are attached where warnings are emitted rather than derived from
+	// their text; other warnings get the generic code.
+	resp = &logical.Response{}
+	addCodedWarning(resp, newWarning(warningCodeTTLTruncated, "ttl", "reworded TTL warning"))
+	resp.AddWarning("uncoded warning")
+	resp = addStructuredWarnings(resp, &framework.FieldData{
+		Raw:    map[string]interface{}{structuredWarningsParam: true},
+		Schema: addStructuredWarningsField(map[string]*framework.FieldSchema{}),
+	})
+	require.Equal(t, []structuredWarning{
+		newWarning(warningCodeTTLTruncated, "ttl", "reworded TTL warning"),
+		newWarning(warningCodeGeneric, "", "uncoded warning"),
+	}, resp.Data[structuredWarningsParam])
+}
+
+func TestReindex(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "Root R1",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	var serial string
+	for i := 0; i < 3; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		serial = resp.Data["serial_number"].(string)
+	}
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serial,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	// Import a second root behind the backend's back.
+	otherB, otherS := CreateBackendWithStorage(t)
+	resp, err = CBWrite(otherB, otherS, "root/generate/exported", map[string]interface{}{
+		"common_name": "Root R2",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "issuers/import/bundle", map[string]interface{}{
+		"pem_bundle": resp.Data["certificate"].(string) + "\n" + resp.Data["private_key"].(string),
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "reindex", nil)
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("reindex"), logical.UpdateOperation), resp, true)
+	require.Equal(t, 2, resp.Data["issuers"])
+	require.Equal(t, 2, resp.Data["keys"])
+	// Three leaves plus the generated root; imported issuers are not
+	// stored under certs/.
+	require.Equal(t, 4, resp.Data["certs"])
+	require.Equal(t, 1, resp.Data["revoked_certs"])
+}
+
+func TestBackend_IssuerOverride(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	var issuerIds []string
+	for _, name := range []string{"root-a", "root-b", "root-c"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name,
+			"issuer_name": name,
+			"key_type":    "ec",
+			"ttl":         "8h",
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		issuerIds = append(issuerIds, string(resp.Data["issuer_id"].(issuerID)))
+	}
+
+	resp, err := CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name":  true,
+		"key_type":        "ec",
+		"ttl":             "1h",
+		"issuer_ref":      "root-a",
+		"allowed_issuers": []string{"root-b"},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	// Without an override, the role's issuer is used.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issue/example"), logical.UpdateOperation), resp, true)
+	require.Equal(t, issuerIds[0], resp.Data["issuer_id"])
+
+	// An allowlisted issuer may be selected by name or identifier.
+	for _, ref := range []string{"root-b", issuerIds[1]} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "example.com",
+			"issuer_ref":  ref,
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		require.Equal(t, issuerIds[1], resp.Data["issuer_id"])
+		cert := parseCert(t, resp.Data["certificate"].(string))
+		require.Equal(t, "root-b", cert.Issuer.CommonName)
+	}
+
+	// The role's own issuer is always allowed.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"issuer_ref":  "root-a",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, issuerIds[0], resp.Data["issuer_id"])
+
+	// Issuers outside the allowlist are rejected.
+	_, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"issuer_ref":  "root-c",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by this role")
+
+	_, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"issuer_ref":  "missing",
+	})
+	require.Error(t, err)
+
+	// The same applies to signing.
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "example.com"},
+	}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"csr":        csrPem,
+		"issuer_ref": "root-b",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("sign/example"), logical.UpdateOperation), resp, true)
+	require.Equal(t, issuerIds[1], resp.Data["issuer_id"])
+
+	_, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"csr":        csrPem,
+		"issuer_ref": "root-c",
+	})
+	require.Error(t, err)
+
+	// A wildcard allows any issuer.
+	resp, err = CBPatch(b, s, "roles/example", map[string]interface{}{
+		"allowed_issuers": []string{"*"},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"issuer_ref":  "root-c",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, issuerIds[2], resp.Data["issuer_id"])
+}
+
+func TestBackend_IssuerSelectionBySubject(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	// Two issuers share a subject, as after a rotation; the first is the
+	// mount's default.
+	issuerIds := map[string]string{}
+	for _, root := range []struct{ name, cn string }{
+		{"old", "shared root"},
+		{"new", "shared root"},
+		{"other", "other root"},
+	} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": root.cn,
+			"issuer_name": root.name,
+			"key_type":    "ec",
+			"ttl":         "8h",
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		issuerIds[root.name] = string(resp.Data["issuer_id"].(issuerID))
+	}
+
+	resp, err := CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name":   true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+		"issuer_ref":       "other",
+		"allowed_issuers":  []string{"old", "new"},
+		"issuer_selection": "subject",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = CBWrite(b, s, "roles/bad", map[string]interface{}{
+		"issuer_selection": "csr",
+	})
+	require.Error(t, err)
+
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "example.com"},
+	}, "ec", 256)
+
+	sign := func(data map[string]interface{}) string {
+		t.Helper()
+		resp, err := CBWrite(b, s, "sign/example", data)
+		requireSuccessNonNilResponse(t, resp, err)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("sign/example"), logical.UpdateOperation), resp, true)
+		return resp.Data["issuer_id"].(string)
+	}
+
+	// Without a subject, the role's issuer is used.
+	require.Equal(t, issuerIds["other"], sign(map[string]interface{}{"csr": csrPem}))
+
+	// Among issuers sharing a subject, the default one is preferred.
+	require.Equal(t, issuerIds["old"], sign(map[string]interface{}{
+		"csr":            csrPem,
+		"issuer_subject": "CN=shared root",
+	}))
+
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{"default": "new"})
+	require.NoError(t, err)
+	require.Equal(t, issuerIds["new"], sign(map[string]interface{}{
+		"csr":            csrPem,
+		"issuer_subject": "cn=Shared Root",
+	}))
+
+	// The intended issuer may be carried in the CSR's requested authority
+	// key identifier extension.
+	nameDer, err := asn1.Marshal(pkix.Name{CommonName: "shared root"}.ToRDNSequence())
+	require.NoError(t, err)
+	generalNames, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: nameDer})
+	require.NoError(t, err)
+	akiDer, err := asn1.Marshal(struct{ CertIssuer asn1.RawValue }{
+		asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: generalNames},
+	})
+	require.NoError(t, err)
+	_, _, akiCsrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject:         pkix.Name{CommonName: "example.com"},
+		ExtraExtensions: []pkix.Extension{{Id: akOid, Value: akiDer}},
+	}, "ec", 256)
+	require.Equal(t, issuerIds["new"], sign(map[string]interface{}{"csr": akiCsrPem}))
+
+	// Only issuers permitted by the role are candidates.
+	resp, err = CBPatch(b, s, "roles/example", map[string]interface{}{
+		"allowed_issuers": []string{"old"},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, issuerIds["old"], sign(map[string]interface{}{"csr": akiCsrPem}))
+
+	_, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"csr":            csrPem,
+		"issuer_subject": "CN=unknown root",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no issuer permitted by this role")
+
+	// An explicit issuer_ref still takes precedence.
+	require.Equal(t, issuerIds["old"], sign(map[string]interface{}{
+		"csr":        akiCsrPem,
+		"issuer_ref": "old",
+	}))
+
+	// issuer_subject requires subject-based selection on the role.
+	resp, err = CBPatch(b, s, "roles/example", map[string]interface{}{
+		"issuer_selection": "role",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	_, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"csr":            csrPem,
+		"issuer_subject": "CN=shared root",
+	})
+	require.Error(t, err)
+	require.Equal(t, issuerIds["other"], sign(map[string]interface{}{"csr": akiCsrPem}))
+}
+
+func TestBackend_TTLIssuerFraction(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	for _, fraction := range []float64{-0.5, 1.5} {
+		_, err = CBWrite(b, s, "roles/bad", map[string]interface{}{
+			"ttl_issuer_fraction": fraction,
+		})
+		require.Error(t, err)
+	}
+	_, err = CBWrite(b, s, "roles/bad", map[string]interface{}{
+		"ttl":                 "1h",
+		"ttl_issuer_fraction": 0.5,
+	})
+	require.Error(t, err)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name":      true,
+		"key_type":            "ec",
+		"ttl_issuer_fraction": 0.5,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, 0.5, resp.Data["ttl_issuer_fraction"])
+
+	// The default TTL is half of the issuer's remaining validity.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	expected := time.Now().Add(time.Until(rootCert.NotAfter) / 2)
+	require.WithinDuration(t, expected, cert.NotAfter, time.Minute)
+
+	// Explicit TTLs still take precedence...
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"ttl":         "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.WithinDuration(t, time.Now().Add(time.Hour), cert.NotAfter, time.Minute)
+
+	// ...but are capped at the issuer's expiration rather than rejected.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+		"ttl":         "20h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, rootCert.NotAfter, cert.NotAfter)
+}
+
+func TestBackend_SMIMECapabilities(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	for _, bad := range []map[string]interface{}{
+		{"smime_capabilities": "2.16.840.1.101.3.4.1.42"},
+		{"email_protection_flag": true, "smime_capabilities": "not-an-oid"},
+		{"email_protection_flag": true, "smime_capabilities": "1.2.840.113549.3.2;INTEGER:abc"},
+		{"email_protection_flag": true, "smime_capabilities": "1.2.840.113549.3.2;DER:zz"},
+		{"email_protection_flag": true, "smime_capabilities": "1.2.840.113549.3.2;STRING:abc"},
+	} {
+		_, err = CBWrite(b, s, "roles/bad", bad)
+		require.Error(t, err, "expected role %v to be rejected", bad)
+	}
+
+	resp, err = CBWrite(b, s, "roles/smime", map[string]interface{}{
+		"allow_any_name":        true,
+		"key_type":              "ec",
+		"ttl":                   "1h",
+		"email_protection_flag": true,
+		"server_flag":           false,
+		"client_flag":           false,
+		"smime_capabilities": []string{
+			"2.16.840.1.101.3.4.1.42",
+			"1.2.840.113549.3.2;INTEGER:128",
+			"1.2.840.113549.1.9.16.3.5;DER:0500",
+		},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "issue/smime", map[string]interface{}{
+		"common_name": "user@example.com",
+		"alt_names":   "user@example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, []string{"user@example.com"}, cert.EmailAddresses)
+	require.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection}, cert.ExtKeyUsage)
+
+	var found bool
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidExtensionSMIMECapabilities) {
+			continue
+		}
+		found = true
+		require.False(t, ext.Critical)
+
+		var capabilities []smimeCapability
+		rest, err := asn1.Unmarshal(ext.Value, &capabilities)
+		require.NoError(t, err)
+		require.Empty(t, rest)
+		require.Len(t, capabilities, 3)
+
+		require.Equal(t, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}, capabilities[0].CapabilityID)
+		require.Empty(t, capabilities[0].Parameters.FullBytes)
+
+		require.Equal(t, asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 2}, capabilities[1].CapabilityID)
+		var keySize int
+		_, err = asn1.Unmarshal(capabilities[1].Parameters.FullBytes, &keySize)
+		require.NoError(t, err)
+		require.Equal(t, 128, keySize)
+
+		require.Equal(t, []byte{0x05, 0x00}, capabilities[2].Parameters.FullBytes)
+	}
+	require.True(t, found, "expected SMIMECapabilities extension")
+
+	// Roles without capabilities don't emit the extension.
+	resp, err = CBPatch(b, s, "roles/smime", map[string]interface{}{
+		"smime_capabilities": []string{},
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "issue/smime", map[string]interface{}{
+		"common_name": "user@example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	for _, ext := range cert.Extensions {
+		require.False(t, ext.Id.Equal(oidExtensionSMIMECapabilities))
+	}
+}
+
+func TestBackend_RenewExpiringCerts(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "roles/other", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	issueWith := func(role string, commonName string, data map[string]interface{}) string {
+		t.Helper()
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		data["common_name"] = commonName
+		resp, err := CBWrite(b, s, "issue/"+role, data)
+		requireSuccessNonNilResponse(t, resp, err)
+		return resp.Data["serial_number"].(string)
+	}
+	issue := func(commonName string, data map[string]interface{}) string {
+		t.Helper()
+		return issueWith("example", commonName, data)
+	}
+
+	expiring := issue("a.example.com", map[string]interface{}{"alt_names": "www.a.example.com"})
+	disallowed := issue("b.other.com", nil)
+	revoked := issue("d.example.com", nil)
+	longLived := issue("c.example.com", map[string]interface{}{"ttl": "5h"})
+
+	// Certificates of other roles sharing the issuer aren't candidates, nor
+	// are ones issued before the mount recorded their role.
+	otherRole := issueWith("other", "e.example.com", nil)
+	legacy := issue("f.example.com", nil)
+	require.NoError(t, s.Delete(context.Background(), certIssuancePrefix+normalizeSerial(legacy)))
+
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	require.NoError(t, err)
+
+	// Tighten the role's policy so one of the certificates may no longer
+	// be issued.
+	resp, err = CBPatch(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name":   false,
+		"allowed_domains":  []string{"example.com"},
+		"allow_subdomains": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "certs/renew-expiring", map[string]interface{}{
+		"role":            "example",
+		"expiring_within": "2h",
+		"dry_run":         true,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/renew-expiring"), logical.UpdateOperation), resp, true)
+	require.Equal(t, map[string]interface{}{expiring: ""}, resp.Data["renewed"])
+	require.Contains(t, resp.Data["skipped"], disallowed)
+	require.Len(t, resp.Data["skipped"], 1)
+	require.NotContains(t, resp.Data, "certificates")
+	require.Equal(t, false, resp.Data["has_more"])
+	require.Empty(t, resp.Data["next_after"])
+
+	listed, err := CBList(b, s, "certs")
+	require.NoError(t, err)
+	require.Len(t, listed.Data["keys"], 7)
+
+	// Batches resume after the last certificate handled.
+	handled := map[string]bool{}
+	resp, err = CBWrite(b, s, "certs/renew-expiring", map[string]interface{}{
+		"role":            "example",
+		"expiring_within": "2h",
+		"dry_run":         true,
+		"limit":           1,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, true, resp.Data["has_more"])
+	require.Len(t, resp.Data["renewed"], 1-len(resp.Data["skipped"].(map[string]interface{})))
+	for serial := range resp.Data["renewed"].(map[string]interface{}) {
+		handled[serial] = true
+	}
+	for serial := range resp.Data["skipped"].(map[string]interface{}) {
+		handled[serial] = true
+	}
+
+	resp, err = CBWrite(b, s, "certs/renew-expiring", map[string]interface{}{
+		"role":            "example",
+		"expiring_within": "2h",
+		"dry_run":         true,
+		"limit":           1,
+		"after":           resp.Data["next_after"],
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, false, resp.Data["has_more"])
+	for serial := range resp.Data["renewed"].(map[string]interface{}) {
+		handled[serial] = true
+	}
+	for serial := range resp.Data["skipped"].(map[string]interface{}) {
+		handled[serial] = true
+	}
+	require.Equal(t, map[string]bool{expiring: true, disallowed: true}, handled)
+
+	resp, err = CBWrite(b, s, "certs/renew-expiring", map[string]interface{}{
+		"role":            "example",
+		"expiring_within": "2h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/renew-expiring"), logical.UpdateOperation), resp, true)
+	renewed := resp.Data["renewed"].(map[string]interface{})
+	require.Len(t, renewed, 1)
+	require.Contains(t, resp.Data["skipped"], disallowed)
+	newSerial := renewed[expiring].(string)
+	require.NotEmpty(t, newSerial)
+	require.NotContains(t, renewed, longLived)
+	require.NotContains(t, renewed, otherRole)
+	require.NotContains(t, renewed, legacy)
+
+	issued := resp.Data["certificates"].(map[string]interface{})[newSerial].(map[string]interface{})
+	require.NotEmpty(t, issued["private_key"])
+	cert := parseCert(t, issued["certificate"].(string))
+	require.Equal(t, "a.example.com", cert.Subject.CommonName)
+	require.Equal(t, []string{"a.example.com", "www.a.example.com"}, cert.DNSNames)
+
+	// The replacement is stored like any other issued certificate.
+	resp, err = CBRead(b, s, "cert/"+newSerial)
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = CBWrite(b, s, "certs/renew-expiring", map[string]interface{}{
+		"role": "missing",
+	})
+	require.Error(t, err)
+}
+
+func TestBackend_IDNHandling(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		singleScript bool
+		expected     string
+		expectErr    bool
+	}{
+		{name: "bücher.example", expected: "xn--bcher-kva.example"},
+		{name: "www.münchen.de", expected: "www.xn--mnchen-3ya.de"},
+		{name: "例え.jp", expected: "xn--r8jz45g.jp"},
+		{name: "例え.jp", singleScript: true, expected: "xn--r8jz45g.jp"},
+		{name: "*.bücher.example", expected: "*.xn--bcher-kva.example"},
+		{name: "xn--bcher-kva.example", expected: "xn--bcher-kva.example"},
+		{name: "example.com", singleScript: true, expected: "example.com"},
+		// Cyrillic "а" (U+0430) posing as the Latin "a".
+		{name: "pаypal.com", expected: "xn--pypal-4ve.com"},
+		{name: "pаypal.com", singleScript: true, expectErr: true},
+		{name: "xn--pypal-4ve.com", singleScript: true, expectErr: true},
+	} {
+		converted, err := toALabels(tc.name, tc.singleScript)
+		if tc.expectErr {
+			require.Error(t, err, "expected %q to be rejected", tc.name)
+			continue
+		}
+		require.NoError(t, err, "converting %q", tc.name)
+		require.Equal(t, tc.expected, converted, "converting %q", tc.name)
+	}
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = CBWrite(b, s, "roles/bad", map[string]interface{}{
+		"idn_handling": "u_label",
+	})
+	require.Error(t, err)
+
+	roleData := map[string]interface{}{
+		"allowed_domains":    []string{"bücher.example"},
+		"allow_bare_domains": true,
+		"allow_subdomains":   true,
+		"key_type":           "ec",
+		"ttl":                "1h",
+	}
+
+	// By default, the common name is kept as requested while DNS SANs are
+	// converted, so the Unicode allowed domain can't match the SAN.
+	resp, err = CBWrite(b, s, "roles/preserve", roleData)
+	requireSuccessNonNilResponse(t, resp, err)
+	_, err = CBWrite(b, s, "issue/preserve", map[string]interface{}{
+		"common_name": "shop.bücher.example",
+	})
+	require.Error(t, err)
+
+	roleData["idn_handling"] = "a_label"
+	resp, err = CBWrite(b, s, "roles/alabel", roleData)
+	requireSuccessNonNilResponse(t, resp, err)
+
+	for _, commonName := range []string{"shop.bücher.example", "shop.xn--bcher-kva.example"} {
+		resp, err = CBWrite(b, s, "issue/alabel", map[string]interface{}{
+			"common_name": commonName,
+			"alt_names":   "user@bücher.example",
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		cert := parseCert(t, resp.Data["certificate"].(string))
+		require.Equal(t, "shop.xn--bcher-kva.example", cert.Subject.CommonName)
+		require.Equal(t, []string{"shop.xn--bcher-kva.example"}, cert.DNSNames)
+		require.Equal(t, []string{"user@xn--bcher-kva.example"}, cert.EmailAddresses)
+	}
+
+	_, err = CBWrite(b, s, "issue/alabel", map[string]interface{}{
+		"common_name": "shop.münchen.de",
+	})
+	require.Error(t, err)
+
+	// Mixed-script confusables are only rejected when configured.
+	roleData["allowed_domains"] = []string{"com"}
+	resp, err = CBWrite(b, s, "roles/alabel", roleData)
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "issue/alabel", map[string]interface{}{
+		"common_name": "pаypal.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, "xn--pypal-4ve.com", parseCert(t, resp.Data["certificate"].(string)).Subject.CommonName)
+
+	roleData["idn_handling"] = "a_label_single_script"
+	resp, err = CBWrite(b, s, "roles/alabel", roleData)
+	requireSuccessNonNilResponse(t, resp, err)
+	_, err = CBWrite(b, s, "issue/alabel", map[string]interface{}{
+		"common_name": "pаypal.com",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mixes scripts")
+}
+
+func TestBackend_IssuanceLimits(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/limited", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"allow_ip_sans":    true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "config/issuance")
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("config/issuance"), logical.ReadOperation), resp, true)
+	require.Equal(t, 0, resp.Data["max_validated_sans"])
+
+	_, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_validated_sans": -1})
+	require.Error(t, err)
+
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_validated_sans": 3})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("config/issuance"), logical.UpdateOperation), resp, true)
+
+	// The common name counts towards the limit along with the IP SANs.
+	resp, err = CBWrite(b, s, "issue/limited", map[string]interface{}{
+		"common_name": "one.example.com",
+		"alt_names":   "two.example.com",
+		"ip_sans":     "127.0.0.1",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/limited")
+
+	_, err = CBWrite(b, s, "issue/limited", map[string]interface{}{
+		"common_name": "one.example.com",
+		"alt_names":   "two.example.com,three.example.com",
+		"ip_sans":     "127.0.0.1",
+	})
+	require.ErrorContains(t, err, "exceeding the mount's max_validated_sans of 3")
+
+	resp, err = CBRead(b, s, "config/issuance")
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	require.Equal(t, 3, resp.Data["max_validated_sans"])
+
+	_, err = CBWrite(b, s, "config/issuance", map[string]interface{}{
+		"max_validated_sans": 0,
+	})
+	require.NoError(t, err)
+	resp, err = CBWrite(b, s, "issue/limited", map[string]interface{}{
+		"common_name": "one.example.com",
+		"alt_names":   "two.example.com,three.example.com",
+		"ip_sans":     "127.0.0.1",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/limited")
+}
+
+func TestBackend_SignPublicKey(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/keys", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	keyPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyDer}))
+
+	request := map[string]interface{}{
+		"public_key":  keyPem,
+		"common_name": "app.example.com",
+		"alt_names":   "api.example.com",
+	}
+
+	// Roles must opt into signing keys without proof of possession.
+	_, err = CBWrite(b, s, "sign-public-key/keys", request)
+	require.ErrorContains(t, err, "allow_public_key_signing")
+
+	_, err = CBPatch(b, s, "roles/keys", map[string]interface{}{"allow_public_key_signing": true})
+	require.NoError(t, err)
+
+	resp, err = CBWrite(b, s, "sign-public-key/keys", request)
+	requireSuccessNonNilResponse(t, resp, err, "sign-public-key/keys")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("sign-public-key/keys"), logical.UpdateOperation), resp, true)
+	require.NotContains(t, resp.Data, "private_key")
+
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, keyDer, cert.RawSubjectPublicKeyInfo)
+	require.Equal(t, "app.example.com", cert.Subject.CommonName)
+	require.ElementsMatch(t, []string{"app.example.com", "api.example.com"}, cert.DNSNames)
+
+	// The certificate is stored as with any other issuance.
+	resp, err = CBRead(b, s, "cert/"+resp.Data["serial_number"].(string))
+	requireSuccessNonNilResponse(t, resp, err, "cert")
+
+	// Names and keys are validated against the role.
+	_, err = CBWrite(b, s, "sign-public-key/keys", map[string]interface{}{
+		"public_key":  keyPem,
+		"common_name": "app.example.org",
+	})
+	require.ErrorContains(t, err, "not allowed by this role")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaDer, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "sign-public-key/keys", map[string]interface{}{
+		"public_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDer})),
+		"common_name": "app.example.com",
+	})
+	require.ErrorContains(t, err, "role requires keys of type ec")
+
+	_, err = CBWrite(b, s, "sign-public-key/keys", map[string]interface{}{
+		"public_key":  "not a key",
+		"common_name": "app.example.com",
+	})
+	require.ErrorContains(t, err, "PUBLIC KEY")
+}
+
+func TestBackend_EscrowPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/escrow", map[string]interface{}{
+		"allowed_domains":    "example.com",
+		"allow_subdomains":   true,
+		"key_type":           "ec",
+		"ttl":                "1h",
+		"escrow_private_key": true,
+		"no_store":           true,
+	})
+	require.ErrorContains(t, err, "no_store")
+
+	resp, err = CBWrite(b, s, "roles/escrow", map[string]interface{}{
+		"allowed_domains":    "example.com",
+		"allow_subdomains":   true,
+		"key_type":           "ec",
+		"ttl":                "1h",
+		"escrow_private_key": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/escrow")
+	require.NotEmpty(t, resp.Warnings)
+
+	resp, err = CBWrite(b, s, "issue/escrow", map[string]interface{}{
+		"common_name": "app.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/escrow")
+	serial := resp.Data["serial_number"].(string)
+	privateKey := resp.Data["private_key"].(string)
+
+	resp, err = CBRead(b, s, "cert/"+serial+"/private-key")
+	requireSuccessNonNilResponse(t, resp, err, "cert/private-key")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial+"/private-key"), logical.ReadOperation), resp, true)
+	require.Equal(t, serial, resp.Data["serial_number"])
+	require.Equal(t, privateKey, resp.Data["private_key"])
+	require.Equal(t, "ec", resp.Data["private_key_type"])
+	require.NotEmpty(t, resp.Warnings)
+
+	// Keys the mount didn't generate are never escrowed.
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "app.example.com"},
+	}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/escrow", map[string]interface{}{
+		"csr": csrPem,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign/escrow")
+	resp, err = CBRead(b, s, "cert/"+resp.Data["serial_number"].(string)+"/private-key")
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// Tidy purges the escrowed key along with its expired certificate.
+	resp, err = CBWrite(b, s, "issue/escrow", map[string]interface{}{
+		"common_name": "short.example.com",
+		"ttl":         "2s",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/escrow")
+	shortSerial := resp.Data["serial_number"].(string)
+	time.Sleep(3 * time.Second)
+
+	_, err = CBWrite(b, s, "tidy", map[string]interface{}{
+		"tidy_cert_store": true,
+		"safety_buffer":   "1s",
+	})
+	require.NoError(t, err)
+
+	for {
+		time.Sleep(125 * time.Millisecond)
+
+		resp, err = CBRead(b, s, "tidy-status")
+		requireSuccessNonNilResponse(t, resp, err, "tidy-status")
+		state := resp.Data["state"].(string)
+		if state == "Finished" {
+			break
+		}
+		require.NotEqual(t, "Error", state, "tidy failed: %v", resp.Data)
+	}
+
+	resp, err = CBRead(b, s, "cert/"+shortSerial+"/private-key")
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = CBRead(b, s, "cert/"+serial+"/private-key")
+	requireSuccessNonNilResponse(t, resp, err, "cert/private-key")
+}
+
+func TestBackend_ExplainPolicyDecisions(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/explained", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+		"max_ttl":          "2h",
+	})
+	require.NoError(t, err)
+
+	resp, err = CBWrite(b, s, "issue/explained", map[string]interface{}{
+		"common_name": "app.example.com",
+		"alt_names":   "api.example.com",
+		"ttl":         "4h",
+		"explain":     true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/explained")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issue/explained"), logical.UpdateOperation), resp, true)
+
+	decisions := resp.Data["policy_decisions"].([]map[string]interface{})
+	find := func(check string, value string) map[string]interface{} {
+		t.Helper()
+		for _, decision := range decisions {
+			if decision["check"] == check && decision["value"] == value {
+				return decision
+			}
+		}
+		t.Fatalf("no %s decision for %q in %v", check, value, decisions)
+		return nil
+	}
+	require.Equal(t, true, find("key_type", "ec")["passed"])
+	require.Equal(t, "allowed_domains", find("common_name", "app.example.com")["role_field"])
+	require.Equal(t, true, find("dns_san", "api.example.com")["passed"])
+	ttl := find("ttl", "4h0m0s")
+	require.Equal(t, "max_ttl", ttl["role_field"])
+	require.Equal(t, "clamped to 2h0m0s", ttl["detail"])
+
+	// Without explain, no trace is returned.
+	resp, err = CBWrite(b, s, "issue/explained", map[string]interface{}{
+		"common_name": "app.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/explained")
+	require.NotContains(t, resp.Data, "policy_decisions")
+
+	// Rejected requests return the trace up to the failing check.
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "app.example.com"},
+		DNSNames: []string{"app.example.com", "app.example.org", "api.example.com"},
+	}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/explained", map[string]interface{}{
+		"csr":     csrPem,
+		"explain": true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.Data[logical.HTTPStatusCode])
+
+	var body struct {
+		Errors          []string         `json:"errors"`
+		PolicyDecisions []policyDecision `json:"policy_decisions"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body))
+	require.Len(t, body.Errors, 1)
+	require.Contains(t, body.Errors[0], "app.example.org not allowed by this role")
+
+	last := body.PolicyDecisions[len(body.PolicyDecisions)-1]
+	require.Equal(t, policyDecision{Check: "dns_san", RoleField: "allowed_domains", Value: "app.example.org", Passed: false}, last)
+	for _, decision := range body.PolicyDecisions[:len(body.PolicyDecisions)-1] {
+		require.True(t, decision.Passed, "%v", decision)
+		require.NotEqual(t, "api.example.com", decision.Value)
+	}
+
+	_, err = CBWrite(b, s, "sign/explained", map[string]interface{}{
+		"csr": csrPem,
+	})
+	require.ErrorContains(t, err, "app.example.org not allowed by this role")
+}
+
+func TestBackend_EnforceCABFTTL(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	// Let leaf certificates outlive the short-lived root, so only the role
+	// constrains their validity.
+	resp, err = CBPatch(b, s, "issuer/default", map[string]interface{}{
+		"leaf_not_after_behavior": "permit",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/default")
+
+	_, err = CBWrite(b, s, "roles/public", map[string]interface{}{
+		"allowed_domains":   "example.com",
+		"allow_subdomains":  true,
+		"key_type":          "ec",
+		"max_ttl":           "20000h",
+		"enforce_cabf_ttl":  true,
+		"cabf_ttl_behavior": "never",
+	})
+	require.ErrorContains(t, err, "invalid cabf_ttl_behavior")
+
+	_, err = CBWrite(b, s, "roles/public", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"max_ttl":          "20000h",
+		"enforce_cabf_ttl": true,
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "config/issuance")
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	require.Equal(t, defaultCABFMaxValidity.String(), resp.Data["cabf_max_validity"])
+
+	// Requests beyond the baseline are truncated to it, with a warning.
+	resp, err = CBWrite(b, s, "issue/public", map[string]interface{}{
+		"common_name": "www.example.com",
+		"ttl":         "10000h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/public")
+	require.NotEmpty(t, resp.Warnings)
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.LessOrEqual(t, cert.NotAfter.Sub(cert.NotBefore), defaultCABFMaxValidity)
+	require.Greater(t, cert.NotAfter.Sub(cert.NotBefore), defaultCABFMaxValidity-time.Minute)
+
+	// Requests within the baseline are unaffected.
+	resp, err = CBWrite(b, s, "issue/public", map[string]interface{}{
+		"common_name": "www.example.com",
+		"ttl":         "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/public")
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.Less(t, cert.NotAfter.Sub(cert.NotBefore), 2*time.Hour)
+
+	// The limit follows the mount's configuration.
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{
+		"cabf_max_validity": "2h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	require.Equal(t, "2h0m0s", resp.Data["cabf_max_validity"])
+
+	resp, err = CBWrite(b, s, "issue/public", map[string]interface{}{
+		"common_name": "www.example.com",
+		"ttl":         "5h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/public")
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.LessOrEqual(t, cert.NotAfter.Sub(cert.NotBefore), 2*time.Hour)
+	require.Greater(t, cert.NotAfter.Sub(cert.NotBefore), 2*time.Hour-time.Minute)
+
+	// In err mode, such requests are rejected instead.
+	resp, err = CBPatch(b, s, "roles/public", map[string]interface{}{
+		"cabf_ttl_behavior": "err",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/public")
+	_, err = CBWrite(b, s, "issue/public", map[string]interface{}{
+		"common_name": "www.example.com",
+		"ttl":         "5h",
+	})
+	require.ErrorContains(t, err, "CA/Browser Forum maximum")
+
+	// Clearing the configuration restores the baseline.
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{
+		"cabf_max_validity": "",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	require.Equal(t, defaultCABFMaxValidity.String(), resp.Data["cabf_max_validity"])
+	resp, err = CBWrite(b, s, "issue/public", map[string]interface{}{
+		"common_name": "www.example.com",
+		"ttl":         "5h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/public")
+}
+
+func TestBackend_AllowedSANTypes(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	roleData := map[string]interface{}{
+		"allowed_domains":    "example.com",
+		"allow_subdomains":   true,
+		"allow_ip_sans":      true,
+		"allowed_uri_sans":   "spiffe://*",
+		"allowed_other_sans": "*",
+		"key_type":           "ec",
+		"ttl":                "1h",
+		"allowed_san_types":  "dns,bogus",
+	}
+	_, err = CBWrite(b, s, "roles/typed", roleData)
+	require.ErrorContains(t, err, "invalid allowed_san_types")
+
+	roleData["allowed_san_types"] = "DNS,upn"
+	resp, err = CBWrite(b, s, "roles/typed", roleData)
+	requireSuccessNonNilResponse(t, resp, err, "roles/typed")
+	require.Equal(t, []string{"dns", "upn"}, resp.Data["allowed_san_types"])
+
+	resp, err = CBWrite(b, s, "issue/typed", map[string]interface{}{
+		"common_name": "a.example.com",
+		"alt_names":   "b.example.com",
+		"other_sans":  upnOID + ";utf8:user@example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/typed")
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.ElementsMatch(t, []string{"a.example.com", "b.example.com"}, cert.DNSNames)
+
+	// SANs of other types are rejected, even though the role's other
+	// restrictions permit them.
+	for name, request := range map[string]map[string]interface{}{
+		"ip":        {"ip_sans": "127.0.0.1"},
+		"uri":       {"uri_sans": "spiffe://example.com/app"},
+		"othername": {"other_sans": "1.2.3.4;utf8:value"},
+		"email":     {"alt_names": "user@example.com"},
+	} {
+		request["common_name"] = "a.example.com"
+		_, err = CBWrite(b, s, "issue/typed", request)
+		require.ErrorContains(t, err, name+" subject alternative names are not allowed")
+	}
+
+	// So are those requested through a CSR.
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject:     pkix.Name{CommonName: "a.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}, "ec", 256)
+	_, err = CBWrite(b, s, "sign/typed", map[string]interface{}{
+		"csr": csrPem,
+	})
+	require.ErrorContains(t, err, "ip subject alternative names are not allowed")
+
+	// The common name isn't copied into the SANs when its type is disallowed.
+	resp, err = CBPatch(b, s, "roles/typed", map[string]interface{}{
+		"allowed_san_types": "ip",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/typed")
+	resp, err = CBWrite(b, s, "issue/typed", map[string]interface{}{
+		"common_name": "a.example.com",
+		"ip_sans":     "127.0.0.1",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/typed")
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, "a.example.com", cert.Subject.CommonName)
+	require.Empty(t, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 1)
+}
+
+func TestBackend_IssueChainFingerprints(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	requireFingerprints := func(resp *logical.Response, der bool) {
+		t.Helper()
+
+		chain := resp.Data["ca_chain"].([]string)
+		fingerprints := resp.Data["ca_chain_fingerprints"].([]string)
+		require.Len(t, fingerprints, len(chain))
+		for index, encoded := range chain {
+			var cert *x509.Certificate
+			if der {
+				raw, err := base64.StdEncoding.DecodeString(encoded)
+				require.NoError(t, err)
+				cert, err = x509.ParseCertificate(raw)
+				require.NoError(t, err)
+			} else {
+				cert = parseCert(t, encoded)
+			}
+			sum := sha256.Sum256(cert.Raw)
+			require.Equal(t, certutil.GetHexFormatted(sum[:], ":"), fingerprints[index])
+		}
+	}
+
+	for _, format := range []string{"pem", "pem_bundle", "der"} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "test.example.com",
+			"format":      format,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example "+format)
+		requireFingerprints(resp, format == "der")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issue/example"), logical.UpdateOperation), resp, true)
+	}
+
+	_, _, csr := generateCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "sign.example.com"}}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"csr": csr,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign/example")
+	requireFingerprints(resp, false)
+
+	// With the self-signed root removed, no chain is returned, and so no
+	// fingerprints either.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name":             "test.example.com",
+		"remove_roots_from_chain": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	require.NotContains(t, resp.Data, "ca_chain")
+	require.NotContains(t, resp.Data, "ca_chain_fingerprints")
+}
+
+func TestBackend_SubjectDNOrder(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	roleData := map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"ttl":              "1h",
+		"key_type":         "ec",
+		"country":          "US",
+		"organization":     "Example",
+		"ou":               "Engineering",
+	}
+	_, err = CBWrite(b, s, "roles/default-order", roleData)
+	require.NoError(t, err)
+
+	roleData["subject_dn_order"] = "cn,ou, O"
+	_, err = CBWrite(b, s, "roles/cn-first", roleData)
+	require.NoError(t, err)
+
+	oidCN := asn1.ObjectIdentifier{2, 5, 4, 3}
+	oidC := asn1.ObjectIdentifier{2, 5, 4, 6}
+	oidO := asn1.ObjectIdentifier{2, 5, 4, 10}
+	oidOU := asn1.ObjectIdentifier{2, 5, 4, 11}
+
+	rdnTypes := func(cert *x509.Certificate) []asn1.ObjectIdentifier {
+		var subject pkix.RDNSequence
+		rest, err := asn1.Unmarshal(cert.RawSubject, &subject)
+		require.NoError(t, err)
+		require.Empty(t, rest)
+
+		var types []asn1.ObjectIdentifier
+		for _, rdn := range subject {
+			require.Len(t, rdn, 1)
+			types = append(types, rdn[0].Type)
+		}
+		return types
+	}
+
+	resp, err := CBWrite(b, s, "issue/default-order", map[string]interface{}{
+		"common_name": "leaf.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/default-order")
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, []asn1.ObjectIdentifier{oidC, oidO, oidOU, oidCN}, rdnTypes(cert))
+
+	resp, err = CBWrite(b, s, "issue/cn-first", map[string]interface{}{
+		"common_name": "leaf.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/cn-first")
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, []asn1.ObjectIdentifier{oidCN, oidOU, oidO, oidC}, rdnTypes(cert))
+	require.Equal(t, "leaf.example.com", cert.Subject.CommonName)
+
+	// Signing a CSR applies the same ordering.
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "csr.example.com"},
+	}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/cn-first", map[string]interface{}{
+		"csr": csrPem,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign/cn-first")
+	cert = parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, []asn1.ObjectIdentifier{oidCN, oidOU, oidO, oidC}, rdnTypes(cert))
+
+	// Unknown and repeated attribute types are rejected.
+	roleData["subject_dn_order"] = "CN,BOGUS"
+	_, err = CBWrite(b, s, "roles/bad", roleData)
+	require.Error(t, err)
+
+	roleData["subject_dn_order"] = "CN,O,cn"
+	_, err = CBWrite(b, s, "roles/bad", roleData)
+	require.Error(t, err)
+}
+
+func TestBackend_IssuanceMetadata(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootIssuerId := resp.Data["issuer_id"].(issuerID).String()
+
+	_, err = CBWrite(b, s, "roles/testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	resp, err = CBWrite(b, s, "issue/testing", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/testing")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issue/testing"), logical.UpdateOperation), resp, true)
+
+	metadata, ok := resp.Data["metadata"].(map[string]interface{})
+	require.True(t, ok, "expected metadata map in response: %#v", resp.Data)
+	require.Equal(t, resp.Data["serial_number"], metadata["serial_number"])
+	require.Equal(t, resp.Data["expiration"], metadata["expiration"])
+	require.Equal(t, "CN=root example.com", metadata["issuing_ca_subject"])
+	require.Equal(t, "testing", metadata["role_name"])
+	require.Equal(t, rootIssuerId, metadata["issuer_id"])
+
+	issuedAt, err := time.Parse(time.RFC3339, metadata["issuance_time_rfc3339"].(string))
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), issuedAt, time.Minute)
+
+	// Role-less sign-verbatim requests don't have a role name.
+	_, _, csr := generateCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "verbatim.example.com"}}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign-verbatim", map[string]interface{}{
+		"csr": csr,
+		"ttl": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign-verbatim")
+	metadata = resp.Data["metadata"].(map[string]interface{})
+	require.NotContains(t, metadata, "role_name")
+	require.Equal(t, rootIssuerId, metadata["issuer_id"])
+
+	resp, err = CBWrite(b, s, "sign-verbatim/testing", map[string]interface{}{
+		"csr": csr,
+		"ttl": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign-verbatim/testing")
+	metadata = resp.Data["metadata"].(map[string]interface{})
+	require.Equal(t, "testing", metadata["role_name"])
+}
+
+func TestBackend_RACredentials(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	const raOid = "1.3.6.1.4.1.55555.1.1"
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	// RA settings require an extension OID.
+	_, err = CBWrite(b, s, "roles/ra", map[string]interface{}{
+		"allow_any_name": true,
+		"ra_namespaces":  "team-a.example.com",
+	})
+	require.Error(t, err)
+
+	// RA credentials must be stored to be verified.
+	_, err = CBWrite(b, s, "roles/ra", map[string]interface{}{
+		"allow_any_name":   true,
+		"no_store":         true,
+		"ra_namespaces":    "team-a.example.com",
+		"ra_extension_oid": raOid,
+	})
+	require.Error(t, err)
+
+	_, err = CBWrite(b, s, "roles/ra", map[string]interface{}{
+		"allow_any_name":   true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+		"ra_namespaces":    "team-a.example.com",
+		"ra_extension_oid": raOid,
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/scoped", map[string]interface{}{
+		"allow_any_name":        true,
+		"key_type":              "ec",
+		"ttl":                   "1h",
+		"require_ra_credential": true,
+		"ra_extension_oid":      raOid,
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "roles/scoped")
+	requireSuccessNonNilResponse(t, resp, err, "roles/scoped")
+	require.Equal(t, true, resp.Data["require_ra_credential"])
+	require.Equal(t, raOid, resp.Data["ra_extension_oid"])
+
+	// Issue the RA credential; it carries the namespaces extension.
+	resp, err = CBWrite(b, s, "issue/ra", map[string]interface{}{
+		"common_name": "ra.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/ra")
+	raCert := parseCert(t, resp.Data["certificate"].(string))
+	raSerial := resp.Data["serial_number"].(string)
+
+	namespaces, err := fetchRANamespaces(b.makeStorageContext(context.Background(), s), &logical.Request{
+		Connection: &logical.Connection{ConnState: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{raCert}}},
+	}, &roleEntry{RAExtensionOID: raOid})
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a.example.com"}, namespaces)
+
+	issueScopedWith := func(peer *x509.Certificate, data map[string]interface{}) (*logical.Response, error) {
+		req := &logical.Request{
+			Operation:  logical.UpdateOperation,
+			Path:       "issue/scoped",
+			Storage:    s,
+			MountPoint: "pki/",
+			Data:       data,
+		}
+		if peer != nil {
+			req.Connection = &logical.Connection{ConnState: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{peer}}}
+		}
+		return b.HandleRequest(context.Background(), req)
+	}
+	issueScoped := func(peer *x509.Certificate, commonName string) (*logical.Response, error) {
+		return issueScopedWith(peer, map[string]interface{}{"common_name": commonName})
+	}
+
+	// Without a credential, the scoped role refuses to issue.
+	resp, err = issueScoped(nil, "host.team-a.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	resp, err = issueScoped(raCert, "host.team-a.example.com")
+	requireSuccessNonNilResponse(t, resp, err, "issue/scoped")
+	scopedCert := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = issueScoped(raCert, "host.team-b.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "not within the namespaces")
+
+	// Namespaces only scope DNS names; other SANs are refused.
+	resp, err = issueScopedWith(raCert, map[string]interface{}{
+		"common_name": "host.team-a.example.com",
+		"ip_sans":     "10.0.0.1",
+	})
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "only carry DNS Subject Alternative Names")
+
+	// A certificate without the extension isn't an RA credential.
+	resp, err = issueScoped(scopedCert, "host.team-a.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	// Nor is one whose extension was copied from a CSR by sign-verbatim.
+	namespacesValue, err := asn1.Marshal([]asn1.RawValue{{
+		Class: asn1.ClassUniversal,
+		Tag:   asn1.TagUTF8String,
+		Bytes: []byte("example.com"),
+	}})
+	require.NoError(t, err)
+	forgedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "forged.example.com"},
+		ExtraExtensions: []pkix.Extension{{
+			Id:    asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1, 1},
+			Value: namespacesValue,
+		}},
+	}, forgedKey)
+	require.NoError(t, err)
+	resp, err = CBWrite(b, s, "sign-verbatim", map[string]interface{}{
+		"csr": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+		"ttl": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign-verbatim")
+	forgedCert := parseCert(t, resp.Data["certificate"].(string))
+	var copied bool
+	for _, ext := range forgedCert.Extensions {
+		copied = copied || ext.Id.String() == raOid
+	}
+	require.True(t, copied, "expected sign-verbatim to copy the RA extension")
+
+	resp, err = issueScoped(forgedCert, "host.team-a.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "not issued as an RA credential")
+
+	// Nor is a foreign certificate reusing the RA credential's serial.
+	foreignDer, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    raCert.SerialNumber,
+		Subject:         raCert.Subject,
+		NotBefore:       raCert.NotBefore,
+		NotAfter:        raCert.NotAfter,
+		ExtraExtensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1, 1}, Value: namespacesValue}},
+	}, &x509.Certificate{Subject: pkix.Name{CommonName: "root example.com"}}, forgedKey.Public(), forgedKey)
+	require.NoError(t, err)
+	foreignCert, err := x509.ParseCertificate(foreignDer)
+	require.NoError(t, err)
+	resp, err = issueScoped(foreignCert, "host.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "not issued by this mount")
+
+	// Once revoked, the RA credential is no longer accepted.
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": raSerial,
+	})
+	require.NoError(t, err)
+	resp, err = issueScoped(raCert, "host.team-a.example.com")
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "revoked")
+}
+
 var (
 	initTest  sync.Once
 	rsaCAKey  string
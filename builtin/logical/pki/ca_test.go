@@ -537,9 +537,7 @@ func runSteps(t *testing.T, rootB, intB *backend, client *api.Client, rootName,
 					t.Fatal("expected a non-zero revocation time")
 				}
 			default:
-				if resp != nil {
-					t.Fatalf("expected nil response, got %#v", *resp)
-				}
+				requireCertNotFound(t, resp, err)
 			}
 		}
 
@@ -59,6 +59,7 @@ func getGenerationParams(sc *storageContext, data *framework.FieldData) (exporte
 		EnforceHostnames:          false,
 		AllowedURISANs:            []string{"*"},
 		AllowedOtherSANs:          []string{"*"},
+		AllowedDirectoryNameSANs:  []string{"*"},
 		AllowedSerialNumbers:      []string{"*"},
 		AllowedUserIDs:            []string{"*"},
 		OU:                        data.Get("ou").([]string),
@@ -224,6 +225,7 @@ func buildSignVerbatimRole(data *framework.FieldData, role *roleEntry) *roleEntr
 		UseCSRCommonName:          true,
 		UseCSRSANs:                true,
 		AllowedOtherSANs:          []string{"*"},
+		AllowedDirectoryNameSANs:  []string{"*"},
 		AllowedSerialNumbers:      []string{"*"},
 		AllowedURISANs:            []string{"*"},
 		AllowedUserIDs:            []string{"*"},
@@ -255,6 +257,8 @@ func buildSignVerbatimRole(data *framework.FieldData, role *roleEntry) *roleEntr
 		}
 		entry.NoStore = role.NoStore
 		entry.Issuer = role.Issuer
+		entry.TTLIssuerFraction = role.TTLIssuerFraction
+		entry.Name = role.Name
 		if _, ok := data.GetOk("basic_constraints_valid_for_non_ca"); !ok {
 			entry.BasicConstraintsValidForNonCA = role.BasicConstraintsValidForNonCA
 		}
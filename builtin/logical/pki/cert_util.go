@@ -21,10 +21,12 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/openbao/openbao/sdk/v2/framework"
 	"github.com/openbao/openbao/sdk/v2/helper/certutil"
@@ -40,6 +42,10 @@ type inputBundle struct {
 	role    *roleEntry
 	req     *logical.Request
 	apiData *framework.FieldData
+
+	// trace, when set, records the role policy checks performed on the
+	// request.
+	trace *policyTrace
 }
 
 var (
@@ -400,11 +406,42 @@ func validateWildcardDomain(name string) (string, string, error) {
 	return wildcardLabel, reducedName, nil
 }
 
+// requestedSANCount returns the number of Subject Alternative Names of the
+// request to be validated against the role: the given DNS names and email
+// addresses along with the requested IP, URI and other SANs.
+func requestedSANCount(data *inputBundle, csr *x509.CertificateRequest, dnsNames, emailAddresses []string) int {
+	count := len(dnsNames) + len(emailAddresses)
+
+	if csr != nil && data.role.UseCSRSANs {
+		count += len(csr.IPAddresses) + len(csr.URIs)
+	} else {
+		for _, field := range []string{"ip_sans", "uri_sans"} {
+			if raw, ok := data.apiData.GetOk(field); ok {
+				count += len(raw.([]string))
+			}
+		}
+	}
+
+	if raw, ok := data.apiData.GetOk("other_sans"); ok {
+		count += len(raw.([]string))
+	}
+
+	return count
+}
+
 // Given a set of requested names for a certificate, verifies that all of them
 // match the various toggles set in the role for controlling issuance.
 // If one does not pass, it is returned in the string argument.
 func validateNames(b *backend, data *inputBundle, names []string) string {
 	for _, name := range names {
+		// Depending on the role, validate internationalized names in their
+		// A-label form.
+		normalized, err := normalizeIDNName(data.role, name)
+		if err != nil {
+			return name
+		}
+		name = normalized
+
 		// Previously, reducedName was called sanitizedName but this made
 		// little sense under the previous interpretation of wildcards,
 		// leading to two bugs in this implementation. We presently call it
@@ -586,6 +623,12 @@ func validateNames(b *backend, data *inputBundle, names []string) string {
 					}
 				}
 
+				if normalizesIDNs(data.role) {
+					if normalized, err := toALabels(currDomain, false); err == nil {
+						currDomain = normalized
+					}
+				}
+
 				// First, allow an exact match of the base domain if that role flag
 				// is enabled
 				if data.role.AllowBareDomains &&
@@ -683,6 +726,103 @@ func parseOtherSANs(others []string) (map[string][]string, error) {
 	return result, nil
 }
 
+// directoryNameAttributeOIDs maps the attribute type short names accepted in
+// directoryName SANs to their OIDs; any other type must be given as a dotted
+// OID.
+var directoryNameAttributeOIDs = map[string]asn1.ObjectIdentifier{
+	"CN":           {2, 5, 4, 3},
+	"SERIALNUMBER": {2, 5, 4, 5},
+	"C":            {2, 5, 4, 6},
+	"L":            {2, 5, 4, 7},
+	"ST":           {2, 5, 4, 8},
+	"STREET":       {2, 5, 4, 9},
+	"O":            {2, 5, 4, 10},
+	"OU":           {2, 5, 4, 11},
+	"POSTALCODE":   {2, 5, 4, 17},
+	"UID":          {0, 9, 2342, 19200300, 100, 1, 1},
+	"DC":           {0, 9, 2342, 19200300, 100, 1, 25},
+}
+
+// parseSubjectDNOrder converts a role's subject_dn_order into the attribute
+// type OIDs to order the subject by, rejecting unknown or repeated types.
+func parseSubjectDNOrder(order []string) ([]asn1.ObjectIdentifier, error) {
+	var result []asn1.ObjectIdentifier
+	seen := make(map[string]bool, len(order))
+	for _, attr := range order {
+		name := strings.ToUpper(strings.TrimSpace(attr))
+		oid, ok := directoryNameAttributeOIDs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown attribute type %q in subject_dn_order", attr)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("attribute type %q is repeated in subject_dn_order", attr)
+		}
+		seen[name] = true
+		result = append(result, oid)
+	}
+
+	return result, nil
+}
+
+// parseDirectoryNameSAN parses an RFC 4514 DN string into the RDN sequence
+// to encode as a directoryName SAN. RFC 4514 strings list the most specific
+// RDN first, which is the reverse of the encoded order.
+func parseDirectoryNameSAN(dn string) (pkix.RDNSequence, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory name SAN %q: %w", dn, err)
+	}
+	if len(parsed.RDNs) == 0 {
+		return nil, fmt.Errorf("invalid directory name SAN %q: empty DN", dn)
+	}
+
+	result := make(pkix.RDNSequence, 0, len(parsed.RDNs))
+	for i := len(parsed.RDNs) - 1; i >= 0; i-- {
+		var set pkix.RelativeDistinguishedNameSET
+		for _, attr := range parsed.RDNs[i].Attributes {
+			oid, ok := directoryNameAttributeOIDs[strings.ToUpper(attr.Type)]
+			if !ok {
+				oid, err = stringToOid(attr.Type)
+				if err != nil {
+					return nil, fmt.Errorf("invalid directory name SAN %q: unknown attribute type %q", dn, attr.Type)
+				}
+			}
+			set = append(set, pkix.AttributeTypeAndValue{Type: oid, Value: attr.Value})
+		}
+		result = append(result, set)
+	}
+
+	return result, nil
+}
+
+// validateDirectoryNameSANs checks if the requested directory names are
+// allowed by the role, returning the first disallowed one. Allowed values
+// are globs matched against the normalized form of the requested DN.
+func validateDirectoryNameSANs(data *inputBundle, requested []pkix.RDNSequence) string {
+	if len(data.role.AllowedDirectoryNameSANs) == 1 && data.role.AllowedDirectoryNameSANs[0] == "*" {
+		// Anything is allowed
+		return ""
+	}
+
+	for _, name := range requested {
+		normalized := name.String()
+
+		valid := false
+		for _, allowed := range data.role.AllowedDirectoryNameSANs {
+			if glob.Glob(allowed, normalized) {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return normalized
+		}
+	}
+
+	return ""
+}
+
 // Returns bool stating whether the given UserId is Valid
 func validateUserId(data *inputBundle, userId string) bool {
 	allowedList := data.role.AllowedUserIDs
@@ -737,7 +877,7 @@ func generateCert(sc *storageContext,
 	input *inputBundle,
 	caSign *certutil.CAInfoBundle,
 	isCA bool,
-	randomSource io.Reader) (*certutil.ParsedCertBundle, []string, error,
+	randomSource io.Reader) (*certutil.ParsedCertBundle, []structuredWarning, error,
 ) {
 	ctx := sc.Context
 	b := sc.Backend
@@ -749,6 +889,7 @@ func generateCert(sc *storageContext,
 	if input.role.KeyType == "rsa" && input.role.KeyBits < 2048 {
 		return nil, nil, errutil.UserError{Err: "RSA keys < 2048 bits are unsafe and not supported"}
 	}
+	input.trace.recordWithDetail("key_type", "key_type", input.role.KeyType, true, "key generated by the mount")
 
 	data, warnings, err := generateCreationBundle(b, input, caSign, nil)
 	if err != nil {
@@ -803,7 +944,7 @@ func generateCert(sc *storageContext,
 				uris = &certutil.URLEntries{}
 
 				msg := "When generating root CA, found global AIA configuration with issuer_id template unsuitable for root generation. This AIA configuration has been ignored. To include AIA on this root CA, set the global AIA configuration to not include issuer_id and instead to refer to a static issuer name."
-				warnings = append(warnings, msg)
+				warnings = append(warnings, newWarning(warningCodeAIATemplateIgnored, "", msg))
 			}
 
 			data.Params.URLs = uris
@@ -826,7 +967,7 @@ func generateCert(sc *storageContext,
 
 // N.B.: This is only meant to be used for generating intermediate CAs.
 // It skips some sanity checks.
-func generateIntermediateCSR(sc *storageContext, input *inputBundle, randomSource io.Reader) (*certutil.ParsedCSRBundle, []string, error) {
+func generateIntermediateCSR(sc *storageContext, input *inputBundle, randomSource io.Reader) (*certutil.ParsedCSRBundle, []structuredWarning, error) {
 	b := sc.Backend
 
 	creation, warnings, err := generateCreationBundle(b, input, nil, nil)
@@ -850,7 +991,7 @@ func signCert(b *backend,
 	data *inputBundle,
 	caSign *certutil.CAInfoBundle,
 	isCA bool,
-	useCSRValues bool) (*certutil.ParsedCertBundle, []string, error,
+	useCSRValues bool) (*certutil.ParsedCertBundle, []structuredWarning, error,
 ) {
 	if data.role == nil {
 		return nil, nil, errutil.InternalError{Err: "no role found in data bundle"}
@@ -874,6 +1015,122 @@ func signCert(b *backend,
 		return nil, nil, errutil.UserError{Err: "Refusing to sign CSR with empty PublicKey. This usually means the SubjectPublicKeyInfo field has an OID not recognized by Go, such as 1.2.840.113549.1.1.10 for rsaPSS."}
 	}
 
+	err = validateSigningKey(data, csr)
+	data.trace.record("key_type", "key_type", csr.PublicKeyAlgorithm.String(), err == nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creation, warnings, err := generateCreationBundle(b, data, caSign, csr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if creation.Params == nil {
+		return nil, nil, errutil.InternalError{Err: "nil parameters received from parameter bundle generation"}
+	}
+
+	creation.Params.IsCA = isCA
+	creation.Params.UseCSRValues = useCSRValues
+
+	if isCA {
+		creation.Params.PermittedDNSDomains = data.apiData.Get("permitted_dns_domains").([]string)
+
+		if rawKeyUsageValue, ok := data.apiData.GetOk("key_usage"); ok {
+			creation.Params.KeyUsage = x509.KeyUsage(parseKeyUsages(rawKeyUsageValue.([]string)))
+		}
+
+		if rawExtKeyUsagesValue, ok := data.apiData.GetOk("ext_key_usage"); ok {
+			creation.Params.ExtKeyUsage = parseExtKeyUsagesValue(0, rawExtKeyUsagesValue.([]string))
+		}
+
+		if rawExtKeyUsageOidsValue, ok := data.apiData.GetOk("ext_key_usage_oids"); ok {
+			creation.Params.ExtKeyUsage = parseExtKeyUsagesValue(0, rawExtKeyUsageOidsValue.([]string))
+		}
+	} else {
+		for _, ext := range csr.Extensions {
+			if ext.Id.Equal(certutil.ExtensionBasicConstraintsOID) && !data.role.BasicConstraintsValidForNonCA {
+				warnings = append(warnings, newWarning(warningCodeCSRBasicConstraintsIgnored, "csr", "specified CSR contained a Basic Constraints extension that was ignored during issuance"))
+			}
+		}
+	}
+
+	parsedBundle, err := certutil.SignCertificate(creation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsedBundle, warnings, nil
+}
+
+// signPublicKey issues a certificate for the PEM-encoded public key of the
+// request, validating it and the requested subject and SANs against the role
+// as signCert does. No proof of possession of the private key is verified.
+func signPublicKey(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle) (*certutil.ParsedCertBundle, []structuredWarning, error) {
+	if data.role == nil {
+		return nil, nil, errutil.InternalError{Err: "no role found in data bundle"}
+	}
+
+	keyPem := data.apiData.Get("public_key").(string)
+	if keyPem == "" {
+		return nil, nil, errutil.UserError{Err: "\"public_key\" is empty"}
+	}
+
+	pemBlock, _ := pem.Decode([]byte(keyPem))
+	if pemBlock == nil || pemBlock.Type != "PUBLIC KEY" {
+		return nil, nil, errutil.UserError{Err: "public_key must be a PEM-encoded PUBLIC KEY block"}
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(pemBlock.Bytes)
+	if err != nil {
+		return nil, nil, errutil.UserError{Err: fmt.Sprintf("public key could not be parsed: %v", err)}
+	}
+
+	// Describe the key as a CSR would, so that the role's key requirements
+	// apply just as on the sign path.
+	request := &x509.CertificateRequest{PublicKey: publicKey}
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		request.PublicKeyAlgorithm = x509.RSA
+	case *ecdsa.PublicKey:
+		request.PublicKeyAlgorithm = x509.ECDSA
+	case ed25519.PublicKey:
+		request.PublicKeyAlgorithm = x509.Ed25519
+	default:
+		return nil, nil, errutil.UserError{Err: fmt.Sprintf("unsupported public key type %T", publicKey)}
+	}
+
+	err = validateSigningKey(data, request)
+	data.trace.record("key_type", "key_type", request.PublicKeyAlgorithm.String(), err == nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creation, warnings, err := generateCreationBundle(b, data, caSign, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if creation.Params == nil {
+		return nil, nil, errutil.InternalError{Err: "nil parameters received from parameter bundle generation"}
+	}
+
+	creation.PublicKey = publicKey
+	creation.Params.IsCA = false
+
+	parsedBundle, err := certutil.SignCertificate(creation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsedBundle, warnings, nil
+}
+
+// validateSigningKey verifies that the public key of the request to sign
+// matches the role's key type and size requirements. For roles accepting any
+// key type, the role's KeyBits and SignatureBits are updated to the defaults
+// of the request's key type.
+func validateSigningKey(data *inputBundle, csr *x509.CertificateRequest) error {
+	var err error
+
 	// This switch validates that the CSR key type matches the role and sets
 	// the value in the actualKeyType/actualKeyBits values.
 	actualKeyType := ""
@@ -883,14 +1140,14 @@ func signCert(b *backend,
 	case "rsa":
 		// Verify that the key matches the role type
 		if csr.PublicKeyAlgorithm != x509.RSA {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"role requires keys of type %s",
 				data.role.KeyType)}
 		}
 
 		pubKey, ok := csr.PublicKey.(*rsa.PublicKey)
 		if !ok {
-			return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+			return errutil.UserError{Err: "could not parse CSR's public key"}
 		}
 
 		actualKeyType = "rsa"
@@ -898,13 +1155,13 @@ func signCert(b *backend,
 	case "ec":
 		// Verify that the key matches the role type
 		if csr.PublicKeyAlgorithm != x509.ECDSA {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"role requires keys of type %s",
 				data.role.KeyType)}
 		}
 		pubKey, ok := csr.PublicKey.(*ecdsa.PublicKey)
 		if !ok {
-			return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+			return errutil.UserError{Err: "could not parse CSR's public key"}
 		}
 
 		actualKeyType = "ec"
@@ -912,14 +1169,14 @@ func signCert(b *backend,
 	case "ed25519":
 		// Verify that the key matches the role type
 		if csr.PublicKeyAlgorithm != x509.Ed25519 {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"role requires keys of type %s",
 				data.role.KeyType)}
 		}
 
 		_, ok := csr.PublicKey.(ed25519.PublicKey)
 		if !ok {
-			return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+			return errutil.UserError{Err: "could not parse CSR's public key"}
 		}
 
 		actualKeyType = "ed25519"
@@ -931,10 +1188,10 @@ func signCert(b *backend,
 		case x509.RSA:
 			pubKey, ok := csr.PublicKey.(*rsa.PublicKey)
 			if !ok {
-				return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+				return errutil.UserError{Err: "could not parse CSR's public key"}
 			}
 			if pubKey.N.BitLen() < 2048 {
-				return nil, nil, errutil.UserError{Err: "RSA keys < 2048 bits are unsafe and not supported"}
+				return errutil.UserError{Err: "RSA keys < 2048 bits are unsafe and not supported"}
 			}
 
 			actualKeyType = "rsa"
@@ -942,7 +1199,7 @@ func signCert(b *backend,
 		case x509.ECDSA:
 			pubKey, ok := csr.PublicKey.(*ecdsa.PublicKey)
 			if !ok {
-				return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+				return errutil.UserError{Err: "could not parse CSR's public key"}
 			}
 
 			actualKeyType = "ec"
@@ -950,16 +1207,16 @@ func signCert(b *backend,
 		case x509.Ed25519:
 			_, ok := csr.PublicKey.(ed25519.PublicKey)
 			if !ok {
-				return nil, nil, errutil.UserError{Err: "could not parse CSR's public key"}
+				return errutil.UserError{Err: "could not parse CSR's public key"}
 			}
 
 			actualKeyType = "ed25519"
 			actualKeyBits = 0
 		default:
-			return nil, nil, errutil.UserError{Err: "Unknown key type in CSR: " + csr.PublicKeyAlgorithm.String()}
+			return errutil.UserError{Err: "Unknown key type in CSR: " + csr.PublicKeyAlgorithm.String()}
 		}
 	default:
-		return nil, nil, errutil.InternalError{Err: fmt.Sprintf("unsupported key type value: %s", data.role.KeyType)}
+		return errutil.InternalError{Err: fmt.Sprintf("unsupported key type value: %s", data.role.KeyType)}
 	}
 
 	// Before validating key lengths, update our KeyBits/SignatureBits based
@@ -978,7 +1235,7 @@ func signCert(b *backend,
 		// for signing operations
 		if data.role.KeyBits, data.role.SignatureBits, err = certutil.ValidateDefaultOrValueKeyTypeSignatureLength(
 			actualKeyType, 0, data.role.SignatureBits); err != nil {
-			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("unknown internal error updating default values: %v", err)}
+			return errutil.InternalError{Err: fmt.Sprintf("unknown internal error updating default values: %v", err)}
 		}
 
 		// We're using the KeyBits field as a minimum value below, and P-224 is safe
@@ -1000,64 +1257,26 @@ func signCert(b *backend,
 	// that we always validate both RSA and ECDSA key sizes.
 	if actualKeyType == "rsa" {
 		if actualKeyBits < data.role.KeyBits {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"role requires a minimum of a %d-bit key, but CSR's key is %d bits",
 				data.role.KeyBits, actualKeyBits)}
 		}
 
 		if actualKeyBits < 2048 {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"OpenBao requires a minimum of a 2048-bit key, but CSR's key is %d bits",
 				actualKeyBits)}
 		}
 	} else if actualKeyType == "ec" {
 		if actualKeyBits < data.role.KeyBits {
-			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+			return errutil.UserError{Err: fmt.Sprintf(
 				"role requires a minimum of a %d-bit key, but CSR's key is %d bits",
 				data.role.KeyBits,
 				actualKeyBits)}
 		}
 	}
 
-	creation, warnings, err := generateCreationBundle(b, data, caSign, csr)
-	if err != nil {
-		return nil, nil, err
-	}
-	if creation.Params == nil {
-		return nil, nil, errutil.InternalError{Err: "nil parameters received from parameter bundle generation"}
-	}
-
-	creation.Params.IsCA = isCA
-	creation.Params.UseCSRValues = useCSRValues
-
-	if isCA {
-		creation.Params.PermittedDNSDomains = data.apiData.Get("permitted_dns_domains").([]string)
-
-		if rawKeyUsageValue, ok := data.apiData.GetOk("key_usage"); ok {
-			creation.Params.KeyUsage = x509.KeyUsage(parseKeyUsages(rawKeyUsageValue.([]string)))
-		}
-
-		if rawExtKeyUsagesValue, ok := data.apiData.GetOk("ext_key_usage"); ok {
-			creation.Params.ExtKeyUsage = parseExtKeyUsagesValue(0, rawExtKeyUsagesValue.([]string))
-		}
-
-		if rawExtKeyUsageOidsValue, ok := data.apiData.GetOk("ext_key_usage_oids"); ok {
-			creation.Params.ExtKeyUsage = parseExtKeyUsagesValue(0, rawExtKeyUsageOidsValue.([]string))
-		}
-	} else {
-		for _, ext := range csr.Extensions {
-			if ext.Id.Equal(certutil.ExtensionBasicConstraintsOID) && !data.role.BasicConstraintsValidForNonCA {
-				warnings = append(warnings, "specified CSR contained a Basic Constraints extension that was ignored during issuance")
-			}
-		}
-	}
-
-	parsedBundle, err := certutil.SignCertificate(creation)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return parsedBundle, warnings, nil
+	return nil
 }
 
 // otherNameRaw describes a name related to a certificate which is not in one
@@ -1186,11 +1405,11 @@ func dedupCommonNames(cnRaw string) []string {
 // generateCreationBundle is a shared function that reads parameters supplied
 // from the various endpoints and generates a CreationParameters with the
 // parameters that can be used to issue or sign
-func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle, csr *x509.CertificateRequest) (*certutil.CreationBundle, []string, error) {
+func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle, csr *x509.CertificateRequest) (*certutil.CreationBundle, []structuredWarning, error) {
 	// Read in names -- CN, DNS and email addresses
 	var cn string
 	var ridSerialNumber string
-	var warnings []string
+	var warnings []structuredWarning
 	dnsNames := []string{}
 	emailAddresses := []string{}
 	{
@@ -1200,10 +1419,20 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		if cn == "" {
 			cn = data.apiData.Get("common_name").(string)
 			if cn == "" && data.role.RequireCN {
+				data.trace.record("common_name", "require_cn", "", false)
 				return nil, nil, errutil.UserError{Err: `the common_name field is required, or must be provided in a CSR with "use_csr_common_name" set to true, unless "require_cn" is set to false`}
 			}
 		}
 
+		if !strings.Contains(cn, "@") {
+			normalized, err := normalizeIDNName(data.role, cn)
+			if err != nil {
+				data.trace.record("common_name", "idn_handling", cn, false)
+				return nil, nil, errutil.UserError{Err: err.Error()}
+			}
+			cn = normalized
+		}
+
 		ridSerialNumber = data.apiData.Get("serial_number").(string)
 
 		// only take serial number from CSR if one was not supplied via API
@@ -1214,8 +1443,16 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		if csr != nil && data.role.UseCSRSANs {
 			dnsNames = csr.DNSNames
 			emailAddresses = csr.EmailAddresses
+			if err := checkSANType(data, sanTypeDNS, dnsNames); err != nil {
+				return nil, nil, err
+			}
+			if err := checkSANType(data, sanTypeEmail, emailAddresses); err != nil {
+				return nil, nil, err
+			}
 		}
 
+		// The common name is only copied into the SANs when the role
+		// permits SANs of its type.
 		if cn != "" && !data.apiData.Get("exclude_cn_from_sans").(bool) {
 			if strings.Contains(cn, "@") {
 				// Note: emails are not disallowed if the role's email protection
@@ -1223,8 +1460,10 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 				// informational purposes; it is up to the verifying party to
 				// ensure that email addresses in a subject alternate name can be
 				// used for the purpose for which they are presented
-				emailAddresses = append(emailAddresses, cn)
-			} else {
+				if allowsSANType(data.role, sanTypeEmail) {
+					emailAddresses = append(emailAddresses, cn)
+				}
+			} else if allowsSANType(data.role, sanTypeDNS) {
 				// Only add to dnsNames if it's actually a DNS name but convert
 				// idn first
 				p := idna.New(
@@ -1244,6 +1483,7 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		if csr == nil || !data.role.UseCSRSANs {
 			cnAltRaw, ok := data.apiData.GetOk("alt_names")
 			if ok {
+				dnsBefore, emailsBefore := len(dnsNames), len(emailAddresses)
 				cnAlt := dedupCommonNames(cnAltRaw.(string))
 				for _, v := range cnAlt {
 					if strings.Contains(v, "@") {
@@ -1264,13 +1504,31 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 						}
 					}
 				}
+				if err := checkSANType(data, sanTypeDNS, dnsNames[dnsBefore:]); err != nil {
+					return nil, nil, err
+				}
+				if err := checkSANType(data, sanTypeEmail, emailAddresses[emailsBefore:]); err != nil {
+					return nil, nil, err
+				}
 			}
 		}
 
+		normalizedEmails := make([]string, 0, len(emailAddresses))
+		for _, email := range emailAddresses {
+			normalized, err := normalizeIDNName(data.role, email)
+			if err != nil {
+				data.trace.record("email_san", "idn_handling", email, false)
+				return nil, nil, errutil.UserError{Err: err.Error()}
+			}
+			normalizedEmails = append(normalizedEmails, normalized)
+		}
+		emailAddresses = normalizedEmails
+
 		// Check the CN. This ensures that the CN is checked even if it's
 		// excluded from SANs.
 		if cn != "" {
 			badName := validateCommonName(b, data, cn)
+			data.trace.record("common_name", "allowed_domains", cn, len(badName) == 0)
 			if len(badName) != 0 {
 				return nil, nil, errutil.UserError{Err: fmt.Sprintf(
 					"common name %s not allowed by this role", badName)}
@@ -1279,20 +1537,30 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 
 		if ridSerialNumber != "" {
 			badName := validateSerialNumber(data, ridSerialNumber)
+			data.trace.record("serial_number", "allowed_serial_numbers", ridSerialNumber, len(badName) == 0)
 			if len(badName) != 0 {
 				return nil, nil, errutil.UserError{Err: fmt.Sprintf(
 					"serial_number %s not allowed by this role", badName)}
 			}
 		}
 
+		// Bound the work spent validating the request before doing so.
+		issuanceConfig := b.getIssuanceConfig()
+		if count := requestedSANCount(data, csr, dnsNames, emailAddresses); issuanceConfig.MaxValidatedSANs > 0 && count > issuanceConfig.MaxValidatedSANs {
+			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+				"request contains %d subject alternative names, exceeding the mount's max_validated_sans of %d", count, issuanceConfig.MaxValidatedSANs)}
+		}
+
 		// Check for bad email and/or DNS names
 		badName := validateNames(b, data, dnsNames)
+		data.trace.recordNames("dns_san", "allowed_domains", dnsNames, badName)
 		if len(badName) != 0 {
 			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
 				"subject alternate name %s not allowed by this role", badName)}
 		}
 
 		badName = validateNames(b, data, emailAddresses)
+		data.trace.recordNames("email_san", "allowed_domains", emailAddresses, badName)
 		if len(badName) != 0 {
 			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
 				"email address %s not allowed by this role", badName)}
@@ -1323,7 +1591,18 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		if err != nil {
 			return nil, nil, errutil.UserError{Err: fmt.Errorf("could not parse requested other SAN: %w", err).Error()}
 		}
+		oids := make([]string, 0, len(requested))
+		for oid := range requested {
+			oids = append(oids, oid)
+		}
+		sort.Strings(oids)
+		for _, oid := range oids {
+			if err := checkSANType(data, otherSANType(oid), []string{oid + ";" + strings.Join(requested[oid], ":")}); err != nil {
+				return nil, nil, err
+			}
+		}
 		badOID, badName, err := validateOtherSANs(data, requested)
+		data.trace.record("other_san", "allowed_other_sans", strings.Join(otherSANsInput, ","), err == nil && len(badName) == 0 && len(badOID) == 0)
 		switch {
 		case err != nil:
 			return nil, nil, errutil.UserError{Err: err.Error()}
@@ -1338,11 +1617,42 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		}
 	}
 
+	var directoryNames []pkix.RDNSequence
+	if dnsRaw, ok := data.apiData.GetOk("directory_name_sans"); ok {
+		if err := checkSANType(data, sanTypeDirectoryName, dnsRaw.([]string)); err != nil {
+			return nil, nil, err
+		}
+		for _, dn := range dnsRaw.([]string) {
+			parsed, err := parseDirectoryNameSAN(dn)
+			if err != nil {
+				return nil, nil, errutil.UserError{Err: err.Error()}
+			}
+			directoryNames = append(directoryNames, parsed)
+		}
+
+		badName := validateDirectoryNameSANs(data, directoryNames)
+		data.trace.record("directory_name_san", "allowed_directory_name_sans", strings.Join(dnsRaw.([]string), ";"), len(badName) == 0)
+		if len(badName) > 0 {
+			return nil, nil, errutil.UserError{Err: fmt.Sprintf(
+				"directory name SAN %s not allowed by this role", badName)}
+		}
+	}
+
 	// Get and verify any IP SANs
 	ipAddresses := []net.IP{}
 	{
 		if csr != nil && data.role.UseCSRSANs {
 			if len(csr.IPAddresses) > 0 {
+				requestedIPs := make([]string, 0, len(csr.IPAddresses))
+				for _, ip := range csr.IPAddresses {
+					requestedIPs = append(requestedIPs, ip.String())
+				}
+				if err := checkSANType(data, sanTypeIP, requestedIPs); err != nil {
+					return nil, nil, err
+				}
+				for _, ip := range csr.IPAddresses {
+					data.trace.record("ip_san", "allow_ip_sans", ip.String(), data.role.AllowIPSANs)
+				}
 				if !data.role.AllowIPSANs {
 					return nil, nil, errutil.UserError{Err: "IP Subject Alternative Names are not allowed in this role, but was provided some via CSR"}
 				}
@@ -1351,6 +1661,12 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		} else {
 			ipAlt := data.apiData.Get("ip_sans").([]string)
 			if len(ipAlt) > 0 {
+				if err := checkSANType(data, sanTypeIP, ipAlt); err != nil {
+					return nil, nil, err
+				}
+				for _, ip := range ipAlt {
+					data.trace.record("ip_san", "allow_ip_sans", ip, data.role.AllowIPSANs)
+				}
 				if !data.role.AllowIPSANs {
 					return nil, nil, errutil.UserError{Err: fmt.Sprintf(
 						"IP Subject Alternative Names are not allowed in this role, but was provided %s", ipAlt)}
@@ -1371,6 +1687,13 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 	{
 		if csr != nil && data.role.UseCSRSANs {
 			if len(csr.URIs) > 0 {
+				requestedURIs := make([]string, 0, len(csr.URIs))
+				for _, uri := range csr.URIs {
+					requestedURIs = append(requestedURIs, uri.String())
+				}
+				if err := checkSANType(data, sanTypeURI, requestedURIs); err != nil {
+					return nil, nil, err
+				}
 				if len(data.role.AllowedURISANs) == 0 {
 					return nil, nil, errutil.UserError{
 						Err: "URI Subject Alternative Names are not allowed in this role, but were provided via CSR",
@@ -1380,6 +1703,7 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 				// validate uri sans
 				for _, uri := range csr.URIs {
 					valid := validateURISAN(b, data, uri.String())
+					data.trace.record("uri_san", "allowed_uri_sans", uri.String(), valid)
 					if !valid {
 						return nil, nil, errutil.UserError{
 							Err: "URI Subject Alternative Names were provided via CSR which are not valid for this role",
@@ -1392,6 +1716,9 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		} else {
 			uriAlt := data.apiData.Get("uri_sans").([]string)
 			if len(uriAlt) > 0 {
+				if err := checkSANType(data, sanTypeURI, uriAlt); err != nil {
+					return nil, nil, err
+				}
 				if len(data.role.AllowedURISANs) == 0 {
 					return nil, nil, errutil.UserError{
 						Err: "URI Subject Alternative Names are not allowed in this role, but were provided via the API",
@@ -1400,6 +1727,7 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 
 				for _, uri := range uriAlt {
 					valid := validateURISAN(b, data, uri)
+					data.trace.record("uri_san", "allowed_uri_sans", uri, valid)
 					if !valid {
 						return nil, nil, errutil.UserError{
 							Err: "URI Subject Alternative Names were provided via the API which are not valid for this role",
@@ -1447,6 +1775,34 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 	}
 	warnings = append(warnings, ttlWarnings...)
 
+	// Roles enforcing CA/Browser Forum limits cap the certificate's whole
+	// validity period, regardless of the requested TTL or not_after.
+	if data.role.EnforceCABFTTL {
+		maxValidity := b.getIssuanceConfig().cabfMaxValidity()
+		start := notBefore
+		if start.IsZero() {
+			// Without an explicit Not Before, the certificate is backdated
+			// by the role's not_before_duration when it's created.
+			backdate := 30 * time.Second
+			if data.role.NotBeforeDuration > 0 {
+				backdate = data.role.NotBeforeDuration
+			}
+			start = time.Now().Add(-backdate)
+		}
+		validity := notAfter.Sub(start)
+		if validity > maxValidity {
+			if data.role.CABFTTLBehavior == cabfTTLBehaviorErr {
+				data.trace.recordWithDetail("validity", "enforce_cabf_ttl", validity.String(), false, fmt.Sprintf("exceeds the CA/Browser Forum maximum of %s", maxValidity))
+				return nil, warnings, errutil.UserError{Err: fmt.Sprintf("requested validity of %s exceeds the CA/Browser Forum maximum of %s enforced by the role", validity, maxValidity)}
+			}
+			notAfter = start.Add(maxValidity)
+			data.trace.recordWithDetail("validity", "enforce_cabf_ttl", validity.String(), true, fmt.Sprintf("truncated to the CA/Browser Forum maximum of %s", maxValidity))
+			warnings = append(warnings, newWarning(warningCodeTTLTruncated, "ttl", fmt.Sprintf("requested validity of %s exceeds the CA/Browser Forum maximum of %s enforced by the role, so the certificate's validity was truncated to it", validity, maxValidity)))
+		} else {
+			data.trace.record("validity", "enforce_cabf_ttl", validity.String(), true)
+		}
+	}
+
 	// Verify that notBefore is older than notAfter.
 	if notBefore.After(notAfter) {
 		return nil, nil, errutil.UserError{
@@ -1505,7 +1861,9 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		// Check for bad userIDs and add to the subject.
 		if len(rawUserIDs) > 0 {
 			for _, value := range rawUserIDs {
-				if !validateUserId(data, value) {
+				valid := validateUserId(data, value)
+				data.trace.record("user_id", "allowed_user_ids", value, valid)
+				if !valid {
 					return nil, nil, errutil.UserError{Err: fmt.Sprintf("user_id %v is not allowed by this role", value)}
 				}
 
@@ -1517,6 +1875,27 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		}
 	}
 
+	subjectDNOrder, err := parseSubjectDNOrder(data.role.SubjectDNOrder)
+	if err != nil {
+		return nil, nil, errutil.UserError{Err: err.Error()}
+	}
+
+	var extraExtensions []pkix.Extension
+	raExtension, err := buildRANamespacesExtension(data.role)
+	if err != nil {
+		return nil, nil, errutil.UserError{Err: err.Error()}
+	}
+	if raExtension != nil {
+		extraExtensions = append(extraExtensions, *raExtension)
+	}
+	smimeExtension, err := buildSMIMECapabilitiesExtension(data.role)
+	if err != nil {
+		return nil, nil, errutil.UserError{Err: err.Error()}
+	}
+	if smimeExtension != nil {
+		extraExtensions = append(extraExtensions, *smimeExtension)
+	}
+
 	creation := &certutil.CreationBundle{
 		Params: &certutil.CreationParameters{
 			Subject:                       subject,
@@ -1525,6 +1904,8 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 			IPAddresses:                   ipAddresses,
 			URIs:                          URIs,
 			OtherSANs:                     otherSANs,
+			DirectoryNames:                directoryNames,
+			SubjectDNOrder:                subjectDNOrder,
 			KeyType:                       data.role.KeyType,
 			KeyBits:                       data.role.KeyBits,
 			SignatureBits:                 data.role.SignatureBits,
@@ -1539,6 +1920,7 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 			NotBeforeDuration:             data.role.NotBeforeDuration,
 			ForceAppendCaChain:            caSign != nil,
 			SKID:                          skid,
+			ExtraExtensions:               extraExtensions,
 		},
 		SigningBundle: caSign,
 		CSR:           csr,
@@ -1602,8 +1984,8 @@ func getCertificateNotBefore(data *inputBundle) (time.Time, error) {
 
 // getCertificateNotAfter compute a certificate's NotAfter date based on the mount ttl, role, signing bundle and input
 // api data being sent. Returns a NotAfter time, a set of warnings or an error.
-func getCertificateNotAfter(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle) (time.Time, []string, error) {
-	var warnings []string
+func getCertificateNotAfter(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle) (time.Time, []structuredWarning, error) {
+	var warnings []structuredWarning
 	var maxTTL time.Duration
 	var notAfter time.Time
 	var err error
@@ -1620,6 +2002,15 @@ func getCertificateNotAfter(b *backend, data *inputBundle, caSign *certutil.CAIn
 		return time.Time{}, warnings, errutil.UserError{Err: "Either ttl or not_after should be provided. Both should not be provided in the same request."}
 	}
 
+	// Roles may derive their default TTL from the issuer's remaining
+	// validity; such roles cap requested TTLs at the issuer's expiration.
+	capAtIssuer := data.role.TTLIssuerFraction > 0 && caSign != nil
+	if ttl == 0 && notAfterAlt == "" && capAtIssuer {
+		if remaining := time.Until(caSign.Certificate.NotAfter); remaining > 0 {
+			ttl = time.Duration(float64(remaining) * data.role.TTLIssuerFraction)
+		}
+	}
+
 	if ttl == 0 && data.role.TTL > 0 {
 		ttl = data.role.TTL
 	}
@@ -1635,8 +2026,13 @@ func getCertificateNotAfter(b *backend, data *inputBundle, caSign *certutil.CAIn
 		maxTTL = b.System().MaxLeaseTTL()
 	}
 	if ttl > maxTTL {
-		warnings = append(warnings, fmt.Sprintf("TTL %q is longer than permitted maxTTL %q, so maxTTL is being used", ttl, maxTTL))
+		warnings = append(warnings, newWarning(warningCodeTTLTruncated, "ttl", fmt.Sprintf("TTL %q is longer than permitted maxTTL %q, so maxTTL is being used", ttl, maxTTL)))
+		if notAfterAlt == "" {
+			data.trace.recordWithDetail("ttl", "max_ttl", ttl.String(), true, fmt.Sprintf("clamped to %s", maxTTL))
+		}
 		ttl = maxTTL
+	} else if notAfterAlt == "" {
+		data.trace.record("ttl", "max_ttl", ttl.String(), true)
 	}
 
 	if notAfterAlt != "" {
@@ -1652,14 +2048,22 @@ func getCertificateNotAfter(b *backend, data *inputBundle, caSign *certutil.CAIn
 		// won't be valid past the lifetime of the CA certificate, and
 		// act accordingly. This is dependent based on the issuer's
 		// LeafNotAfterBehavior argument.
+		requested := notAfter.UTC().Format(time.RFC3339)
 		switch caSign.LeafNotAfterBehavior {
 		case certutil.PermitNotAfterBehavior:
-			// Explicitly do nothing.
+			data.trace.recordWithDetail("not_after", "issuer_ref", requested, true, "permitted beyond the issuer's expiration by its leaf_not_after_behavior")
 		case certutil.TruncateNotAfterBehavior:
 			notAfter = caSign.Certificate.NotAfter
+			data.trace.recordWithDetail("not_after", "issuer_ref", requested, true, "truncated to the issuer's expiration by its leaf_not_after_behavior")
 		case certutil.ErrNotAfterBehavior:
 			fallthrough
 		default:
+			if capAtIssuer {
+				notAfter = caSign.Certificate.NotAfter
+				data.trace.recordWithDetail("not_after", "ttl_issuer_fraction", requested, true, "capped at the issuer's expiration")
+				break
+			}
+			data.trace.recordWithDetail("not_after", "issuer_ref", requested, false, "beyond the issuer's expiration, which its leaf_not_after_behavior forbids")
 			return time.Time{}, warnings, errutil.UserError{Err: fmt.Sprintf(
 				"cannot satisfy request, as TTL would result in notAfter of %s that is beyond the expiration of the CA certificate at %s", notAfter.UTC().Format(time.RFC3339Nano), caSign.Certificate.NotAfter.UTC().Format(time.RFC3339Nano))}
 		}
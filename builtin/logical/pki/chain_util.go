@@ -1382,3 +1382,41 @@ func addParentChainsToEntry(
 		}
 	}
 }
+
+// verifyCAChainLinks checks that each certificate of a CA chain, ordered from
+// the issuer towards its root, names and is signed by the certificate after
+// it, returning a description of every broken link. The chain may stop short
+// of a self-signed root.
+func verifyCAChainLinks(chain []*x509.Certificate) []string {
+	problems := []string{}
+	describe := func(index int) string {
+		return fmt.Sprintf("certificate %d (%v)", index, chain[index].Subject)
+	}
+
+	for index := 0; index+1 < len(chain); index++ {
+		child, parent := chain[index], chain[index+1]
+
+		if bytes.Equal(child.RawIssuer, child.RawSubject) && child.CheckSignatureFrom(child) == nil {
+			problems = append(problems, fmt.Sprintf("%v is a self-signed root, but is followed by %v", describe(index), describe(index+1)))
+			continue
+		}
+
+		if !bytes.Equal(child.RawIssuer, parent.RawSubject) {
+			problem := fmt.Sprintf("%v names %v as its issuer, but is followed by %v", describe(index), child.Issuer, describe(index+1))
+			for other := range chain {
+				if other != index && other != index+1 && bytes.Equal(child.RawIssuer, chain[other].RawSubject) && child.CheckSignatureFrom(chain[other]) == nil {
+					problem += fmt.Sprintf("; its issuer is out of order, at position %d", other)
+					break
+				}
+			}
+			problems = append(problems, problem)
+			continue
+		}
+
+		if err := child.CheckSignatureFrom(parent); err != nil {
+			problems = append(problems, fmt.Sprintf("%v is not validly signed by %v: %v", describe(index), describe(index+1), err))
+		}
+	}
+
+	return problems
+}
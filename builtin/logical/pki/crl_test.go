@@ -4,10 +4,22 @@
 package pki
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -21,6 +33,7 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 )
 
 func TestBackend_CRL_EnableDisableRoot(t *testing.T) {
@@ -1582,3 +1595,1146 @@ func TestRevokeExpiredCert(t *testing.T) {
 	crl = getParsedCrlFromBackend(t, b, s, "crl")
 	requireSerialNumberInCRL(t, crl.TBSCertList, newLeafSerial)
 }
+
+func TestCrlPKCS7(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "Root R1",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	serial := resp.Data["serial_number"].(string)
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serial,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBRead(b, s, "crl")
+	requireSuccessNonNilResponse(t, resp, err)
+	crlDer := resp.Data["http_raw_body"].([]byte)
+
+	resp, err = CBRead(b, s, "crl/pkcs7")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, "application/pkcs7-mime", resp.Data["http_content_type"])
+	p7Der := resp.Data["http_raw_body"].([]byte)
+
+	// Parse back the envelope and ensure the embedded CRL is identical to
+	// the one served directly.
+	var contentInfo pkcs7ContentInfo
+	rest, err := asn1.Unmarshal(p7Der, &contentInfo)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.True(t, contentInfo.ContentType.Equal(oidPKCS7SignedData))
+
+	var signedData pkcs7CRLSignedData
+	rest, err = asn1.Unmarshal(contentInfo.Content.Bytes, &signedData)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.Equal(t, 1, signedData.Version)
+	require.Empty(t, signedData.SignerInfos)
+	require.Equal(t, 1, signedData.CRLs.Tag)
+	require.Equal(t, crlDer, signedData.CRLs.Bytes)
+
+	crl, err := x509.ParseRevocationList(signedData.CRLs.Bytes)
+	require.NoError(t, err)
+	require.Len(t, crl.RevokedCertificateEntries, 1)
+
+	// The envelope is deterministic.
+	resp, err = CBRead(b, s, "crl/pkcs7")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, p7Der, resp.Data["http_raw_body"].([]byte))
+
+	resp, err = CBRead(b, s, "crl/pkcs7/pem")
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("crl/pkcs7/pem"), logical.ReadOperation), resp, true)
+	require.Equal(t, "application/x-pem-file", resp.Data["http_content_type"])
+	block, rest := pem.Decode(resp.Data["http_raw_body"].([]byte))
+	require.NotNil(t, block)
+	require.Empty(t, rest)
+	require.Equal(t, "PKCS7", block.Type)
+	require.Equal(t, p7Der, block.Bytes)
+}
+
+func TestHoldAndReleaseCert(t *testing.T) {
+	t.Parallel()
+
+	b, s, testEnv := setupOcspEnv(t, "ec")
+	leafSerial := serialFromCert(testEnv.leafCertIssuer1)
+
+	requireReason := func(crl pkix.TBSCertificateList, serial string, reason int) {
+		t.Helper()
+		for _, entry := range crl.RevokedCertificates {
+			if serialFromBigInt(entry.SerialNumber) != serial {
+				continue
+			}
+			require.Len(t, entry.Extensions, 1)
+			require.True(t, entry.Extensions[0].Id.Equal(oidCRLReasonCode))
+			var got asn1.Enumerated
+			_, err := asn1.Unmarshal(entry.Extensions[0].Value, &got)
+			require.NoError(t, err)
+			require.Equal(t, asn1.Enumerated(reason), got)
+			return
+		}
+		t.Fatalf("serial %v not found on CRL", serial)
+	}
+
+	requireOcspStatus := func(status int, reason int) {
+		t.Helper()
+		resp, err := SendOcspRequest(t, b, s, "get", testEnv.leafCertIssuer1, testEnv.issuer1, crypto.SHA1)
+		requireSuccessNonNilResponse(t, resp, err)
+		ocspResp, err := ocsp.ParseResponse(resp.Data["http_raw_body"].([]byte), testEnv.issuer1)
+		require.NoError(t, err)
+		require.Equal(t, status, ocspResp.Status)
+		if status == ocsp.Revoked {
+			require.Equal(t, reason, ocspResp.RevocationReason)
+		}
+	}
+
+	// Releasing a certificate which isn't held fails.
+	_, err := CBWrite(b, s, "release", map[string]interface{}{"serial_number": leafSerial})
+	require.Error(t, err)
+
+	resp, err := CBWrite(b, s, "hold", map[string]interface{}{"serial_number": leafSerial})
+	requireSuccessNonNilResponse(t, resp, err, "hold")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("hold"), logical.UpdateOperation), resp, true)
+	require.Equal(t, "held", resp.Data["state"])
+
+	crl := getParsedCrlFromBackend(t, b, s, "issuer/"+testEnv.issuerId1.String()+"/crl/der").TBSCertList
+	requireReason(crl, leafSerial, ocsp.CertificateHold)
+	requireOcspStatus(ocsp.Revoked, ocsp.CertificateHold)
+
+	resp, err = CBWrite(b, s, "release", map[string]interface{}{"serial_number": leafSerial})
+	requireSuccessNonNilResponse(t, resp, err, "release")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("release"), logical.UpdateOperation), resp, true)
+	require.Equal(t, "released", resp.Data["state"])
+
+	crl = getParsedCrlFromBackend(t, b, s, "issuer/"+testEnv.issuerId1.String()+"/crl/der").TBSCertList
+	require.Empty(t, crl.RevokedCertificates)
+	requireOcspStatus(ocsp.Good, 0)
+
+	// Revoking a held certificate makes the revocation permanent; it can
+	// then neither be held nor released.
+	_, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": leafSerial})
+	require.NoError(t, err)
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": leafSerial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	require.Equal(t, "revoked", resp.Data["state"])
+	requireOcspStatus(ocsp.Revoked, ocsp.Unspecified)
+
+	crl = getParsedCrlFromBackend(t, b, s, "issuer/"+testEnv.issuerId1.String()+"/crl/der").TBSCertList
+	requireSerialNumberInCRL(t, crl, leafSerial)
+	require.Empty(t, crl.RevokedCertificates[0].Extensions)
+
+	_, err = CBWrite(b, s, "release", map[string]interface{}{"serial_number": leafSerial})
+	require.Error(t, err)
+	_, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": leafSerial})
+	require.Error(t, err)
+}
+
+func TestHoldAndReleaseCert_Delta(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+
+	resp, err = CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/local-testing")
+	leafSerial := resp.Data["serial_number"].(string)
+
+	_, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": leafSerial})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	crl := getParsedCrlFromBackend(t, b, s, "crl").TBSCertList
+	requireSerialNumberInCRL(t, crl, leafSerial)
+
+	resp, err = CBWrite(b, s, "release", map[string]interface{}{"serial_number": leafSerial})
+	requireSuccessNonNilResponse(t, resp, err, "release")
+
+	// Until the complete CRL is rebuilt, the delta CRL lists the release.
+	_, err = CBRead(b, s, "crl/rotate-delta")
+	require.NoError(t, err)
+	delta := getParsedCrlFromBackend(t, b, s, "crl/delta").TBSCertList
+	requireSerialNumberInCRL(t, delta, leafSerial)
+	require.Len(t, delta.RevokedCertificates[0].Extensions, 1)
+	var reason asn1.Enumerated
+	_, err = asn1.Unmarshal(delta.RevokedCertificates[0].Extensions[0].Value, &reason)
+	require.NoError(t, err)
+	require.Equal(t, asn1.Enumerated(ocsp.RemoveFromCRL), reason)
+
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	crl = getParsedCrlFromBackend(t, b, s, "crl").TBSCertList
+	require.Empty(t, crl.RevokedCertificates)
+	delta = getParsedCrlFromBackend(t, b, s, "crl/delta").TBSCertList
+	require.Empty(t, delta.RevokedCertificates)
+}
+
+func TestCombinedCRL(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+
+	issue := func(t *testing.T) string {
+		resp, err := CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+			"common_name": "example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/local-testing")
+		return resp.Data["serial_number"].(string)
+	}
+	readCombined := func(t *testing.T) *x509.RevocationList {
+		resp, err := CBRead(b, s, "crl/combined")
+		requireSuccessNonNilResponse(t, resp, err, "crl/combined")
+		require.Equal(t, "application/pkix-crl", resp.Data[logical.HTTPContentType])
+		crl, err := x509.ParseRevocationList(resp.Data[logical.HTTPRawBody].([]byte))
+		require.NoError(t, err)
+		require.NoError(t, crl.CheckSignatureFrom(rootCert))
+		return crl
+	}
+	serialsOf := func(crl *x509.RevocationList) []string {
+		var serials []string
+		for _, entry := range crl.RevokedCertificateEntries {
+			serials = append(serials, serialFromBigInt(entry.SerialNumber))
+		}
+		return serials
+	}
+
+	base, delta, held := issue(t), issue(t), issue(t)
+
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": base})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": held})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{base, held}, serialsOf(readCombined(t)))
+
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": delta})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "release", map[string]interface{}{"serial_number": held})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate-delta")
+	require.NoError(t, err)
+
+	// The combined CRL adds the delta's revocations and drops the released
+	// certificate, under the delta CRL's number.
+	resp, err = CBRead(b, s, "crl/delta")
+	requireSuccessNonNilResponse(t, resp, err, "crl/delta")
+	deltaCRL, err := x509.ParseRevocationList(resp.Data[logical.HTTPRawBody].([]byte))
+	require.NoError(t, err)
+
+	combined := readCombined(t)
+	require.ElementsMatch(t, []string{base, delta}, serialsOf(combined))
+	require.Equal(t, deltaCRL.Number, combined.Number)
+	for _, ext := range combined.Extensions {
+		require.NotEqual(t, "2.5.29.27", ext.Id.String(), "combined CRL must not be a delta CRL")
+	}
+
+	// It is built with the CRLs rather than signed on each fetch, so
+	// repeated fetches serve the same bytes.
+	require.Equal(t, combined.Raw, readCombined(t).Raw)
+
+	resp, err = CBRead(b, s, "crl/combined/pem")
+	requireSuccessNonNilResponse(t, resp, err, "crl/combined/pem")
+	require.Equal(t, "application/x-pem-file", resp.Data[logical.HTTPContentType])
+	block, rest := pem.Decode(resp.Data[logical.HTTPRawBody].([]byte))
+	require.NotNil(t, block)
+	require.Empty(t, rest)
+	require.Equal(t, "X509 CRL", block.Type)
+}
+
+func TestCRLMetadata(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resp, err = CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+			"common_name": "example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/local-testing")
+		_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": resp.Data["serial_number"]})
+		require.NoError(t, err)
+	}
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	// The metadata matches the header of the CRL served alongside it.
+	for path, isDelta := range map[string]bool{"crl": false, "crl/delta": true} {
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		crl, err := x509.ParseRevocationList(resp.Data[logical.HTTPRawBody].([]byte))
+		require.NoError(t, err)
+
+		resp, err = CBRead(b, s, path+"/metadata")
+		requireSuccessNonNilResponse(t, resp, err, path+"/metadata")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path+"/metadata"), logical.ReadOperation), resp, true)
+		require.Equal(t, crl.Number.Int64(), resp.Data["crl_number"], "reading %v", path)
+		require.Equal(t, crl.ThisUpdate, resp.Data["this_update"], "reading %v", path)
+		require.Equal(t, crl.NextUpdate, resp.Data["next_update"], "reading %v", path)
+		require.Equal(t, len(crl.RevokedCertificateEntries), resp.Data["revoked_count"], "reading %v", path)
+		require.Equal(t, isDelta, resp.Data["is_delta"], "reading %v", path)
+	}
+
+	resp, err = CBRead(b, s, "crl/metadata")
+	requireSuccessNonNilResponse(t, resp, err, "crl/metadata")
+	require.Equal(t, 2, resp.Data["revoked_count"])
+	require.True(t, resp.Data["next_update"].(time.Time).After(resp.Data["this_update"].(time.Time)))
+}
+
+func TestCRLJSON(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+
+	reasons := map[string]string{}
+	for i := 0; i < 5; i++ {
+		resp, err = CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+			"common_name": "example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/local-testing")
+		serial := resp.Data["serial_number"].(string)
+
+		if i%2 == 0 {
+			_, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+				"public_key": string(pem.EncodeToMemory(&pem.Block{
+					Type:  "PUBLIC KEY",
+					Bytes: parseCert(t, resp.Data["certificate"].(string)).RawSubjectPublicKeyInfo,
+				})),
+				"reason": "key_compromise",
+			})
+			reasons[serial] = "keyCompromise"
+		} else {
+			_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+			reasons[serial] = "unspecified"
+		}
+		require.NoError(t, err)
+	}
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "crl")
+	requireSuccessNonNilResponse(t, resp, err, "crl")
+	crl, err := x509.ParseRevocationList(resp.Data[logical.HTTPRawBody].([]byte))
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "crl/json")
+	requireSuccessNonNilResponse(t, resp, err, "crl/json")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("crl/json"), logical.ReadOperation), resp, true)
+	require.Equal(t, crl.Number.Int64(), resp.Data["crl_number"])
+	require.Equal(t, crl.ThisUpdate, resp.Data["this_update"])
+	require.Equal(t, crl.NextUpdate, resp.Data["next_update"])
+	require.Equal(t, "", resp.Data["next_after"])
+	require.Equal(t, false, resp.Data["has_more"])
+
+	revoked := resp.Data["revoked"].([]map[string]interface{})
+	require.Len(t, revoked, len(reasons))
+	var serials []string
+	for _, entry := range revoked {
+		serial := entry["serial"].(string)
+		require.Equal(t, reasons[serial], entry["reason"], "serial %v", serial)
+		require.False(t, entry["revocation_time"].(time.Time).IsZero(), "serial %v", serial)
+		serials = append(serials, serial)
+	}
+	require.True(t, sort.SliceIsSorted(serials, func(i, j int) bool {
+		return normalizeSerial(serials[i]) < normalizeSerial(serials[j])
+	}), "serials %v", serials)
+
+	// Paging with after and limit visits every entry once.
+	var paged []string
+	after := ""
+	for {
+		resp, err = CBReq(b, s, logical.ReadOperation, "crl/json", map[string]interface{}{
+			"after":               after,
+			"limit":               2,
+			"include_next_cursor": true,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "crl/json")
+		page := resp.Data["revoked"].([]map[string]interface{})
+		require.LessOrEqual(t, len(page), 2)
+		for _, entry := range page {
+			paged = append(paged, entry["serial"].(string))
+		}
+
+		after = resp.Data["next_cursor"].(string)
+		require.Equal(t, after != "", resp.Data["has_more"])
+		if after == "" {
+			break
+		}
+	}
+	require.Equal(t, serials, paged)
+
+	// The delta CRL is served the same way.
+	resp, err = CBRead(b, s, "crl/delta/json")
+	requireSuccessNonNilResponse(t, resp, err, "crl/delta/json")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("crl/delta/json"), logical.ReadOperation), resp, true)
+
+	_, err = CBReq(b, s, logical.ReadOperation, "crl/json", map[string]interface{}{
+		"after": "not-a-serial",
+	})
+	require.Error(t, err)
+}
+
+func TestCRLWithIssuer(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	rootIds := map[string]string{}
+	for _, name := range []string{"root-a", "root-b"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"key_name":    name,
+			"key_type":    "ec",
+			"ttl":         "8h",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		rootIds[name] = resp.Data["issuer_id"].(issuerID).String()
+	}
+
+	requireSignedBy := func(t *testing.T, path string, name string) {
+		t.Helper()
+		resp, err := CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ReadOperation), resp, true)
+		require.Equal(t, rootIds[name], resp.Data["issuer_id"], "reading %v", path)
+
+		der, err := base64.StdEncoding.DecodeString(resp.Data["crl"].(string))
+		require.NoError(t, err)
+		crl, err := x509.ParseRevocationList(der)
+		require.NoError(t, err)
+		issuerCert := parseCert(t, resp.Data["certificate"].(string))
+		require.NoError(t, crl.CheckSignatureFrom(issuerCert))
+
+		chain := resp.Data["ca_chain"].([]string)
+		require.NotEmpty(t, chain)
+		require.Equal(t, issuerCert.Raw, parseCert(t, chain[0]).Raw)
+	}
+
+	// The first root generated is the default issuer.
+	requireSignedBy(t, "crl/with-issuer", "root-a")
+
+	resp, err := CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": "root-b",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuers")
+	requireSignedBy(t, "crl/with-issuer", "root-b")
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	requireSignedBy(t, "crl/delta/with-issuer", "root-b")
+
+	// A default issuer which may not sign CRLs shares the CRL of an
+	// equivalent issuer, which is reported as its signer.
+	resp, err = CBWrite(b, s, "root/generate/existing", map[string]interface{}{
+		"common_name": "root-b example.com",
+		"issuer_name": "root-c",
+		"key_ref":     "root-b",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/existing")
+	_, err = CBPatch(b, s, "issuer/root-c", map[string]interface{}{
+		"usage": "read-only,issuing-certificates",
+	})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": "root-c",
+	})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	requireSignedBy(t, "crl/with-issuer", "root-b")
+	requireSignedBy(t, "crl/delta/with-issuer", "root-b")
+}
+
+func TestCRLBase64Format(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	for _, path := range []string{"crl", "crl/delta"} {
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		der := resp.Data[logical.HTTPRawBody].([]byte)
+
+		resp, err = CBReq(b, s, logical.ReadOperation, path, map[string]interface{}{"format": fetchFormatDER})
+		requireSuccessNonNilResponse(t, resp, err, path)
+		require.Equal(t, der, resp.Data[logical.HTTPRawBody], "reading %v", path)
+
+		resp, err = CBReq(b, s, logical.ReadOperation, path, map[string]interface{}{"format": fetchFormatBase64})
+		requireSuccessNonNilResponse(t, resp, err, path)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ReadOperation), resp, true)
+		require.NotContains(t, resp.Data, logical.HTTPRawBody)
+		decoded, err := base64.StdEncoding.DecodeString(resp.Data["crl"].(string))
+		require.NoError(t, err)
+		require.Equal(t, der, decoded, "reading %v", path)
+	}
+
+	for path, format := range map[string]string{"crl/pem": fetchFormatBase64, "crl/pkcs7": fetchFormatBase64, "crl": "pem"} {
+		resp, err = CBReq(b, s, logical.ReadOperation, path, map[string]interface{}{"format": format})
+		require.Error(t, err, "reading %v as %v", path, format)
+		require.True(t, resp.IsError(), "reading %v as %v", path, format)
+	}
+}
+
+func TestCRLGzipEncoding(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/crl")
+
+	read := func(t *testing.T, path string, headers map[string][]string) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+			Headers:    headers,
+		})
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		return resp
+	}
+
+	for _, path := range []string{"crl", "crl/pem", "crl/delta", "crl/delta/pem"} {
+		plain := read(t, path, nil)
+		require.Equal(t, http.StatusOK, plain.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.NotContains(t, plain.Headers, headerContentEncoding, "reading %v", path)
+
+		// Explicitly refused or wildcard codings leave the body unchanged.
+		for _, header := range []string{"gzip;q=0", "*", "br"} {
+			resp = read(t, path, map[string][]string{headerAcceptEncoding: {header}})
+			require.Equal(t, plain.Data[logical.HTTPRawBody], resp.Data[logical.HTTPRawBody], "reading %v with %v", path, header)
+			require.NotContains(t, resp.Headers, headerContentEncoding, "reading %v with %v", path, header)
+		}
+
+		resp = read(t, path, map[string][]string{headerAcceptEncoding: {"br, gzip;q=0.8"}})
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.Equal(t, []string{"gzip"}, resp.Headers[headerContentEncoding], "reading %v", path)
+		require.Equal(t, plain.Data[logical.HTTPContentType], resp.Data[logical.HTTPContentType], "reading %v", path)
+
+		reader, err := gzip.NewReader(bytes.NewReader(resp.Data[logical.HTTPRawBody].([]byte)))
+		require.NoError(t, err, "reading %v", path)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err, "reading %v", path)
+		require.Equal(t, plain.Data[logical.HTTPRawBody], body, "reading %v", path)
+
+		// The compressed representation has its own entity tag, which
+		// revalidates without a body.
+		etag := resp.Headers[headerETag][0]
+		require.NotEqual(t, plain.Headers[headerETag][0], etag, "reading %v", path)
+		resp = read(t, path, map[string][]string{
+			headerAcceptEncoding: {"gzip"},
+			headerIfNoneMatch:    {etag},
+		})
+		require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.NotContains(t, resp.Headers, headerContentEncoding, "reading %v", path)
+	}
+
+	// Certificates are never compressed.
+	resp = read(t, "ca", map[string][]string{headerAcceptEncoding: {"gzip"}})
+	require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+	require.NotContains(t, resp.Headers, headerContentEncoding)
+}
+
+func TestIssuerCRLByRef(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	roots := map[string]*x509.Certificate{}
+	for _, name := range []string{"root1", "root2"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"key_type":    "ec",
+			"ttl":         "8h",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		roots[name] = parseCert(t, resp.Data["certificate"].(string))
+	}
+
+	_, err := CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	resp, err := CBWrite(b, s, "issuer/root2/issue/local-testing", map[string]interface{}{
+		"common_name": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root2/issue/local-testing")
+	serial := resp.Data["serial_number"].(string)
+
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+	require.NoError(t, err)
+
+	// Each issuer's CRL is signed by it, and only the issuer of the revoked
+	// certificate lists it.
+	for name, listed := range map[string]bool{"root1": false, "root2": true} {
+		crl := getParsedCrlFromBackend(t, b, s, "issuer/"+name+"/crl/der")
+		require.NoError(t, roots[name].CheckCRLSignature(crl), "CRL of %v", name)
+		require.Equal(t, listed, requireSerialNumberInCRL(nil, crl.TBSCertList, serial), "CRL of %v", name)
+
+		resp, err = CBRead(b, s, "issuer/"+name+"/crl/pem")
+		requireSuccessNonNilResponse(t, resp, err, "issuer/"+name+"/crl/pem")
+		require.Equal(t, "application/x-pem-file", resp.Data[logical.HTTPContentType])
+		block, _ := pem.Decode(resp.Data[logical.HTTPRawBody].([]byte))
+		require.NotNil(t, block)
+		require.Equal(t, "X509 CRL", block.Type)
+	}
+
+	// Unknown references are rejected with a user error on every variant,
+	// including conditional requests.
+	for _, path := range []string{"issuer/missing/crl", "issuer/missing/crl/der", "issuer/missing/crl/delta/pem"} {
+		resp, err = b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      path,
+			Storage:   s,
+			Headers: map[string][]string{
+				headerIfModifiedSince: {time.Now().Add(time.Hour).Format(time.RFC1123)},
+			},
+		})
+		require.NoError(t, err, path)
+		require.NotNil(t, resp, path)
+		require.True(t, resp.IsError(), path)
+		require.Contains(t, resp.Error().Error(), "missing", path)
+	}
+}
+
+func TestRevokeByPublicKey(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	// Two certificates share the compromised key; a third doesn't.
+	priv, _, csrPem := generateCSR(t, &x509.CertificateRequest{}, "ec", 256)
+	var compromised []string
+	for _, cn := range []string{"one.example.com", "two.example.com"} {
+		resp, err = CBWrite(b, s, "sign/local-testing", map[string]interface{}{
+			"common_name": cn,
+			"csr":         csrPem,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "sign/local-testing")
+		compromised = append(compromised, resp.Data["serial_number"].(string))
+	}
+
+	resp, err = CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+		"common_name": "other.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/local-testing")
+	otherSerial := resp.Data["serial_number"].(string)
+
+	publicKeyDer, err := x509.MarshalPKIXPublicKey(priv.(crypto.Signer).Public())
+	require.NoError(t, err)
+	publicKeyPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDer}))
+	publicKeyHash := sha256.Sum256(publicKeyDer)
+
+	// Exactly one of the key or its hash is required, with a known reason.
+	_, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{})
+	require.Error(t, err)
+	_, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key":        publicKeyPem,
+		"public_key_sha256": hex.EncodeToString(publicKeyHash[:]),
+	})
+	require.Error(t, err)
+	_, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key": publicKeyPem,
+		"reason":     "certificate_hold",
+	})
+	require.Error(t, err)
+
+	// A dry run reports the matches without revoking them.
+	resp, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key_sha256": hex.EncodeToString(publicKeyHash[:]),
+		"dry_run":           true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+	require.ElementsMatch(t, compromised, resp.Data["revoked"])
+	require.Equal(t, true, resp.Data["dry_run"])
+
+	resp, err = CBList(b, s, "certs/revoked")
+	require.NoError(t, err)
+	require.Empty(t, resp.Data["keys"])
+
+	// A stored certificate which fails to parse is reported rather than
+	// failing the request.
+	require.NoError(t, s.Put(context.Background(), &logical.StorageEntry{
+		Key:   "certs/00-01",
+		Value: []byte("not a certificate"),
+	}))
+
+	lastCRLNumber := getCRLNumber(t, getParsedCrlFromBackend(t, b, s, "crl").TBSCertList)
+
+	resp, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key": publicKeyPem,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("revoke/by-public-key"), logical.UpdateOperation), resp, true)
+	require.ElementsMatch(t, compromised, resp.Data["revoked"])
+	require.Empty(t, resp.Data["skipped"])
+	require.Contains(t, resp.Data["parse_errors"], "00:01")
+	require.Len(t, resp.Warnings, 1)
+
+	// The CRL is rebuilt once for all revocations; each rebuild consumes
+	// two CRL numbers, one each for the complete and delta CRLs.
+	crl := getParsedCrlFromBackend(t, b, s, "crl").TBSCertList
+	require.Equal(t, lastCRLNumber+2, getCRLNumber(t, crl))
+	require.Len(t, crl.RevokedCertificates, len(compromised))
+	for _, entry := range crl.RevokedCertificates {
+		require.Contains(t, compromised, serialFromBigInt(entry.SerialNumber))
+		require.NotEqual(t, otherSerial, serialFromBigInt(entry.SerialNumber))
+		require.Len(t, entry.Extensions, 1)
+		require.True(t, entry.Extensions[0].Id.Equal(oidCRLReasonCode))
+		var reason asn1.Enumerated
+		_, err = asn1.Unmarshal(entry.Extensions[0].Value, &reason)
+		require.NoError(t, err)
+		require.Equal(t, asn1.Enumerated(ocsp.KeyCompromise), reason)
+	}
+
+	// Already-revoked certificates are reported as skipped.
+	resp, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key": publicKeyPem,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+	require.Empty(t, resp.Data["revoked"])
+	require.Len(t, resp.Data["skipped"], len(compromised))
+}
+
+func revokeCertsForCRLBuild(t testing.TB, b *backend, s logical.Storage, count int) {
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	_, err = CBWrite(b, s, "roles/local-testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	// Avoid rebuilding the CRL on every revocation.
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		resp, err = CBWrite(b, s, "issue/local-testing", map[string]interface{}{
+			"common_name": fmt.Sprintf("host-%d.example.com", i),
+		})
+		require.NoError(t, err)
+
+		_, err = CBWrite(b, s, "revoke", map[string]interface{}{
+			"serial_number": resp.Data["serial_number"],
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestCRLBuildBatchingMatchesSerial(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	revokeCertsForCRLBuild(t, b, s, 50)
+
+	sc := b.makeStorageContext(context.Background(), s)
+	issuerIDCertMap, err := fetchIssuerMapForRevocationChecking(sc)
+	require.NoError(t, err)
+
+	serialConfig := &crlConfig{BuildBatchSize: 1000, BuildParallelism: 1}
+	wantUnassigned, wantRevoked, err := getLocalRevokedCertEntries(sc, issuerIDCertMap, false, false, serialConfig)
+	require.NoError(t, err)
+	require.Empty(t, wantUnassigned)
+	require.Len(t, wantRevoked, 1)
+
+	var wantEntries []pkix.RevokedCertificate
+	for _, entries := range wantRevoked {
+		wantEntries = entries
+	}
+	require.Len(t, wantEntries, 50)
+	wantDer, err := asn1.Marshal(wantEntries)
+	require.NoError(t, err)
+
+	for _, config := range []*crlConfig{
+		{BuildBatchSize: 7, BuildParallelism: 1},
+		{BuildBatchSize: 7, BuildParallelism: 4},
+		{BuildBatchSize: 50, BuildParallelism: 8},
+		{BuildBatchSize: 1000, BuildParallelism: 16},
+	} {
+		unassigned, revoked, err := getLocalRevokedCertEntries(sc, issuerIDCertMap, false, false, config)
+		require.NoError(t, err)
+		require.Empty(t, unassigned)
+		require.Len(t, revoked, 1)
+
+		for _, entries := range revoked {
+			der, err := asn1.Marshal(entries)
+			require.NoError(t, err)
+			require.Equal(t, wantDer, der, "batch size %d, parallelism %d", config.BuildBatchSize, config.BuildParallelism)
+		}
+	}
+
+	// The configuration is exposed and validated on config/crl, and the
+	// complete CRL lists every revocation.
+	resp, err := CBWrite(b, s, "config/crl", map[string]interface{}{
+		"build_batch_size":  7,
+		"build_parallelism": 4,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/crl")
+	require.Equal(t, 7, resp.Data["build_batch_size"])
+	require.Equal(t, 4, resp.Data["build_parallelism"])
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"build_parallelism": 0,
+	})
+	require.Error(t, err)
+
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	crl := getParsedCrlFromBackend(t, b, s, "crl").TBSCertList
+	crlDer, err := asn1.Marshal(crl.RevokedCertificates)
+	require.NoError(t, err)
+	require.Equal(t, wantDer, crlDer)
+}
+
+func BenchmarkCRLBuild(b *testing.B) {
+	backend, s := CreateBackendWithStorage(b)
+	revokeCertsForCRLBuild(b, backend, s, 2000)
+
+	for _, config := range []struct {
+		batchSize   int
+		parallelism int
+	}{
+		{1 << 30, 1},
+		{defaultCrlBuildBatchSize, 1},
+		{defaultCrlBuildBatchSize, 4},
+		{100, 4},
+	} {
+		b.Run(fmt.Sprintf("batch=%d/parallelism=%d", config.batchSize, config.parallelism), func(b *testing.B) {
+			_, err := CBWrite(backend, s, "config/crl", map[string]interface{}{
+				"build_batch_size":  config.batchSize,
+				"build_parallelism": config.parallelism,
+			})
+			require.NoError(b, err)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := CBRead(backend, s, "crl/rotate")
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestCRLAudit(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// With automatic rebuilding, revocations aren't yet on the CRL.
+	revokeCertsForCRLBuild(t, b, s, 3)
+
+	resp, err := CBRead(b, s, "crl/audit")
+	requireSuccessNonNilResponse(t, resp, err, "crl/audit")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("crl/audit"), logical.ReadOperation), resp, true)
+	require.Equal(t, 1, resp.Data["crls"])
+	require.Equal(t, 3, resp.Data["revoked_certs"])
+	require.Len(t, resp.Data["missing_from_crl"], 3)
+	require.Empty(t, resp.Data["not_revoked_in_crl"])
+	require.True(t, resp.Data["drift_detected"].(bool))
+	require.Len(t, resp.Warnings, 2)
+
+	// Rebuilding resolves the drift.
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "crl/audit")
+	requireSuccessNonNilResponse(t, resp, err, "crl/audit")
+	require.Empty(t, resp.Data["missing_from_crl"])
+	require.Empty(t, resp.Data["not_revoked_in_crl"])
+	require.False(t, resp.Data["drift_detected"].(bool))
+	require.Empty(t, resp.Warnings)
+
+	// A revocation entry removed from storage remains on the CRL.
+	sc := b.makeStorageContext(context.Background(), s)
+	revoked, err := sc.listRevokedCerts()
+	require.NoError(t, err)
+	require.Len(t, revoked, 3)
+	require.NoError(t, s.Delete(context.Background(), revokedPath+revoked[0]))
+
+	resp, err = CBRead(b, s, "crl/audit")
+	requireSuccessNonNilResponse(t, resp, err, "crl/audit")
+	require.Equal(t, 2, resp.Data["revoked_certs"])
+	require.Empty(t, resp.Data["missing_from_crl"])
+	require.Equal(t, []string{denormalizeSerial(revoked[0])}, resp.Data["not_revoked_in_crl"])
+	require.True(t, resp.Data["drift_detected"].(bool))
+
+	// Auditing doesn't backfill the issuer of a revocation entry.
+	entry, err := s.Get(context.Background(), revokedPath+revoked[1])
+	require.NoError(t, err)
+	var revInfo revocationInfo
+	require.NoError(t, entry.DecodeJSON(&revInfo))
+	revInfo.CertificateIssuer = ""
+	entry, err = logical.StorageEntryJSON(revokedPath+revoked[1], revInfo)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(context.Background(), entry))
+
+	resp, err = CBRead(b, s, "crl/audit")
+	requireSuccessNonNilResponse(t, resp, err, "crl/audit")
+	require.Equal(t, 2, resp.Data["revoked_certs"])
+
+	after, err := s.Get(context.Background(), revokedPath+revoked[1])
+	require.NoError(t, err)
+	require.Equal(t, entry.Value, after.Value)
+}
+
+func TestOcspOnlyRevocation(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	issuer := parseCert(t, resp.Data["certificate"].(string))
+
+	_, err = CBWrite(b, s, "roles/ocsp-only", map[string]interface{}{
+		"allow_any_name":       true,
+		"key_type":             "ec",
+		"ttl":                  "1h",
+		"ocsp_only_revocation": true,
+		"no_store":             true,
+	})
+	require.ErrorContains(t, err, "ocsp_only_revocation")
+
+	_, err = CBWrite(b, s, "roles/ocsp-only", map[string]interface{}{
+		"allow_any_name":       true,
+		"key_type":             "ec",
+		"ttl":                  "1h",
+		"ocsp_only_revocation": true,
+	})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "roles/crl", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	require.NoError(t, err)
+
+	issue := func(role string) *x509.Certificate {
+		t.Helper()
+		resp, err := CBWrite(b, s, "issue/"+role, map[string]interface{}{
+			"common_name": role + ".example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/"+role)
+		return parseCert(t, resp.Data["certificate"].(string))
+	}
+
+	requireOcspRevoked := func(cert *x509.Certificate) {
+		t.Helper()
+		resp, err := SendOcspRequest(t, b, s, "post", cert, issuer, crypto.SHA256)
+		requireSuccessNonNilResponse(t, resp, err, "ocsp")
+		ocspResp, err := ocsp.ParseResponse(resp.Data["http_raw_body"].([]byte), issuer)
+		require.NoError(t, err)
+		require.Equal(t, ocsp.Revoked, ocspResp.Status)
+	}
+
+	ocspOnlyCert := issue("ocsp-only")
+	crlCert := issue("crl")
+	ocspOnlySerial := serialFromCert(ocspOnlyCert)
+	crlSerial := serialFromCert(crlCert)
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": ocspOnlySerial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	require.NotEmpty(t, resp.Warnings)
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": crlSerial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	// Both revocations are reported by OCSP, but only the certificate of
+	// the regular role is listed on the CRL, even after a rebuild.
+	requireOcspRevoked(ocspOnlyCert)
+	requireOcspRevoked(crlCert)
+
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	crl := getParsedCrlFromBackend(t, b, s, "crl").TBSCertList
+	requireSerialNumberInCRL(t, crl, crlSerial)
+	require.Len(t, crl.RevokedCertificates, 1)
+
+	// Nor are they listed on delta CRLs.
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+
+	laterOcspOnlyCert := issue("ocsp-only")
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serialFromCert(laterOcspOnlyCert)})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	requireOcspRevoked(laterOcspOnlyCert)
+
+	_, err = CBRead(b, s, "crl/rotate-delta")
+	require.NoError(t, err)
+	delta := getParsedCrlFromBackend(t, b, s, "crl/delta").TBSCertList
+	require.Empty(t, delta.RevokedCertificates)
+
+	// The CRL audit doesn't consider them missing from the CRL.
+	resp, err = CBRead(b, s, "crl/audit")
+	requireSuccessNonNilResponse(t, resp, err, "crl/audit")
+	require.False(t, resp.Data["drift_detected"].(bool))
+}
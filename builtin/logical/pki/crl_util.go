@@ -8,8 +8,10 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,10 +21,12 @@ import (
 	"github.com/openbao/openbao/sdk/v2/helper/errutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	atomic2 "go.uber.org/atomic"
+	"golang.org/x/crypto/ocsp"
 )
 
 const (
 	revokedPath                    = "revoked/"
+	releasedPath                   = "released/"
 	deltaWALLastBuildSerialName    = "last-build-serial"
 	deltaWALLastRevokedSerialName  = "last-revoked-serial"
 	localDeltaWALPath              = "delta-wal/"
@@ -35,6 +39,84 @@ type revocationInfo struct {
 	RevocationTime    int64     `json:"revocation_time"`
 	RevocationTimeUTC time.Time `json:"revocation_time_utc"`
 	CertificateIssuer issuerID  `json:"issuer_id"`
+
+	// OnHold is set when the certificate was placed on hold (revocation
+	// reason certificateHold) rather than permanently revoked; such
+	// certificates may later be released.
+	OnHold bool `json:"on_hold,omitempty"`
+
+	// RevocationReason is the RFC 5280 reason code given when revoking the
+	// certificate; zero (unspecified) omits the reason from the CRL entry.
+	RevocationReason int `json:"revocation_reason,omitempty"`
+
+	// ReasonRecorded distinguishes an unspecified RevocationReason from
+	// entries written before reasons were recorded, whose reason is unknown.
+	ReasonRecorded bool `json:"reason_recorded,omitempty"`
+
+	// OcspOnly is set when the certificate was issued by a role with
+	// ocsp_only_revocation: its revocation is reported by OCSP but never
+	// listed on a CRL.
+	OcspOnly bool `json:"ocsp_only,omitempty"`
+}
+
+var oidCRLReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+// revocationReasonNames maps RFC 5280 reason codes to their names.
+var revocationReasonNames = map[int]string{
+	ocsp.Unspecified:          "unspecified",
+	ocsp.KeyCompromise:        "keyCompromise",
+	ocsp.CACompromise:         "cACompromise",
+	ocsp.AffiliationChanged:   "affiliationChanged",
+	ocsp.Superseded:           "superseded",
+	ocsp.CessationOfOperation: "cessationOfOperation",
+	ocsp.CertificateHold:      "certificateHold",
+	ocsp.RemoveFromCRL:        "removeFromCRL",
+	ocsp.PrivilegeWithdrawn:   "privilegeWithdrawn",
+	ocsp.AACompromise:         "aACompromise",
+}
+
+// revocationReasonOf returns the RFC 5280 reason code of a revocation, and
+// false when it isn't known because the entry predates recorded reasons.
+func revocationReasonOf(revInfo *revocationInfo) (int, bool) {
+	if revInfo.OnHold {
+		return ocsp.CertificateHold, true
+	}
+	return revInfo.RevocationReason, revInfo.ReasonRecorded
+}
+
+// parseRevocationReason parses a revocation reason given either as its
+// numeric RFC 5280 code or by name. Names are matched case-insensitively,
+// either in their RFC 5280 form (keyCompromise) or in the form accepted by
+// revoke/by-public-key (key_compromise).
+func parseRevocationReason(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if code, err := strconv.Atoi(value); err == nil {
+		if _, ok := revocationReasonNames[code]; ok {
+			return code, nil
+		}
+		return 0, fmt.Errorf("unknown revocation reason code %d", code)
+	}
+
+	for code, name := range revocationReasonNames {
+		if strings.EqualFold(name, value) {
+			return code, nil
+		}
+	}
+	if code, ok := revocationReasons[strings.ToLower(value)]; ok {
+		return code, nil
+	}
+	return 0, fmt.Errorf("unknown revocation reason %q", value)
+}
+
+// crlReasonCodeExtension builds the CRL entry extension carrying the given
+// revocation reason (RFC 5280 Section 5.3.1).
+func crlReasonCodeExtension(reason int) (pkix.Extension, error) {
+	value, err := asn1.Marshal(asn1.Enumerated(reason))
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{Id: oidCRLReasonCode, Value: value}, nil
 }
 
 type revocationRequest struct {
@@ -333,6 +415,12 @@ func (cb *crlBuilder) _clearDeltaWAL(sc *storageContext, walSerials []string, pa
 		if err := sc.Storage.Delete(sc.Context, path+serial); err != nil {
 			return fmt.Errorf("error clearing delta WAL certificate: %w", err)
 		}
+
+		// Released certificates only need to appear on the delta CRL until
+		// the next complete CRL is built.
+		if err := sc.Storage.Delete(sc.Context, releasedPath+serial); err != nil {
+			return fmt.Errorf("error clearing released certificate: %w", err)
+		}
 	}
 
 	return nil
@@ -504,6 +592,29 @@ func fetchIssuerMapForRevocationChecking(sc *storageContext) (map[issuerID]*x509
 	return issuerIDCertMap, nil
 }
 
+// validateRevocationTime ensures a client-supplied revocation time is within
+// the configured skew of the server's clock; a zero skew disables the check.
+func validateRevocationTime(config *crlConfig, revTime time.Time) error {
+	maxSkew, err := parseutil.ParseDurationSecond(config.MaxRevocationTimeSkew)
+	if err != nil {
+		return fmt.Errorf("unable to parse max_revocation_time_skew (%v): %w", config.MaxRevocationTimeSkew, err)
+	}
+
+	if maxSkew <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if revTime.After(now.Add(maxSkew)) {
+		return errutil.UserError{Err: fmt.Sprintf("revocation time %v is more than %v in the future", revTime.UTC().Format(time.RFC3339), maxSkew)}
+	}
+	if revTime.Before(now.Add(-maxSkew)) {
+		return errutil.UserError{Err: fmt.Sprintf("revocation time %v is more than %v in the past", revTime.UTC().Format(time.RFC3339), maxSkew)}
+	}
+
+	return nil
+}
+
 // Revoke a certificate from a given serial number if it is present in local
 // storage.
 func tryRevokeCertBySerial(sc *storageContext, config *crlConfig, serial string) (*logical.Response, error) {
@@ -535,13 +646,33 @@ func tryRevokeCertBySerial(sc *storageContext, config *crlConfig, serial string)
 
 // Revokes a cert, and tries to be smart about error recovery
 func revokeCert(sc *storageContext, config *crlConfig, cert *x509.Certificate) (*logical.Response, error) {
+	return revokeOrHoldCert(sc, config, cert, false, ocsp.Unspecified)
+}
+
+// revokeOrHoldCert revokes the certificate or, when hold is set, places it on
+// hold so that it may later be released. Revoking a held certificate makes
+// its revocation permanent.
+func revokeOrHoldCert(sc *storageContext, config *crlConfig, cert *x509.Certificate, hold bool, reason int) (*logical.Response, error) {
+	resp, updateCRLs, err := writeRevocationEntry(sc, config, cert, hold, reason)
+	if err != nil || !updateCRLs {
+		return resp, err
+	}
+
+	return updateCRLsAfterRevocationChange(sc, config, resp, serialFromCert(cert))
+}
+
+// writeRevocationEntry stores the revocation (or hold) entry for the
+// certificate without touching the CRLs. It reports whether the CRLs need
+// updating to reflect the change, so that callers revoking several
+// certificates can update them once for the whole batch.
+func writeRevocationEntry(sc *storageContext, config *crlConfig, cert *x509.Certificate, hold bool, reason int) (*logical.Response, bool, error) {
 	// As this backend is self-contained and this function does not hook into
 	// third parties to manage users or resources, if the mount is tainted,
 	// revocation doesn't matter anyways -- the CRL that would be written will
 	// be immediately blown away by the view being cleared. So we can simply
 	// fast path a successful exit.
 	if sc.Backend.System().Tainted() {
-		return nil, nil
+		return nil, false, nil
 	}
 
 	colonSerial := serialFromCert(cert)
@@ -553,65 +684,91 @@ func revokeCert(sc *storageContext, config *crlConfig, cert *x509.Certificate) (
 	// handle revoking certs.
 	issuerIDCertMap, err := fetchIssuerMapForRevocationChecking(sc)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Ensure we don't revoke an issuer via this API; use /issuer/:issuer_ref/revoke
 	// instead.
 	for issuer, certificate := range issuerIDCertMap {
 		if colonSerial == serialFromCert(certificate) {
-			return logical.ErrorResponse(fmt.Sprintf("adding issuer (id: %v) to its own CRL is not allowed", issuer)), nil
+			return logical.ErrorResponse(fmt.Sprintf("adding issuer (id: %v) to its own CRL is not allowed", issuer)), false, nil
 		}
 	}
 
 	curRevInfo, err := sc.fetchRevocationInfo(colonSerial)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if curRevInfo != nil {
+
+	var revInfo revocationInfo
+	switch {
+	case curRevInfo != nil && curRevInfo.OnHold && !hold:
+		// Permanently revoke the held certificate, keeping its original
+		// revocation time.
+		revInfo = *curRevInfo
+		revInfo.OnHold = false
+		revInfo.RevocationReason = reason
+		revInfo.ReasonRecorded = true
+	case curRevInfo != nil:
+		if hold && !curRevInfo.OnHold {
+			return logical.ErrorResponse(fmt.Sprintf("certificate with serial %s is already revoked and cannot be placed on hold", colonSerial)), false, nil
+		}
+
 		resp := &logical.Response{
 			Data: map[string]interface{}{
 				"revocation_time": curRevInfo.RevocationTime,
-				"state":           "revoked",
+				"state":           revocationState(curRevInfo),
 			},
 		}
 		if !curRevInfo.RevocationTimeUTC.IsZero() {
 			resp.Data["revocation_time_rfc3339"] = curRevInfo.RevocationTimeUTC.Format(time.RFC3339Nano)
 		}
 
-		return resp, nil
-	}
+		return resp, false, nil
+	default:
+		// Add a little wiggle room because leases are stored with a second
+		// granularity
+		if cert.NotAfter.Before(time.Now().Add(2*time.Second)) && !config.AllowExpiredCertRevocation {
+			response := &logical.Response{}
+			response.AddWarning(fmt.Sprintf("certificate with serial %s already expired; refusing to add to CRL. Enable 'AllowExpiredCertRevocation' to allow revoking expired certificates.", colonSerial))
+			return response, false, nil
+		}
 
-	// Add a little wiggle room because leases are stored with a second
-	// granularity
-	if cert.NotAfter.Before(time.Now().Add(2*time.Second)) && !config.AllowExpiredCertRevocation {
-		response := &logical.Response{}
-		response.AddWarning(fmt.Sprintf("certificate with serial %s already expired; refusing to add to CRL. Enable 'AllowExpiredCertRevocation' to allow revoking expired certificates.", colonSerial))
-		return response, nil
-	}
+		currTime := time.Now()
+		revInfo = revocationInfo{
+			CertificateBytes:  cert.Raw,
+			RevocationTime:    currTime.Unix(),
+			RevocationTimeUTC: currTime.UTC(),
+			OnHold:            hold,
+		}
+		if !hold {
+			revInfo.RevocationReason = reason
+			revInfo.ReasonRecorded = true
+		}
 
-	currTime := time.Now()
-	revInfo := revocationInfo{
-		CertificateBytes:  cert.Raw,
-		RevocationTime:    currTime.Unix(),
-		RevocationTimeUTC: currTime.UTC(),
-	}
+		revInfo.OcspOnly, err = sc.isOcspOnlyRevocation(colonSerial)
+		if err != nil {
+			return nil, false, fmt.Errorf("error fetching OCSP-only revocation marker: %w", err)
+		}
 
-	// We may not find an issuer with this certificate; that's fine so
-	// ignore the return value.
-	associateRevokedCertWithIsssuer(&revInfo, cert, issuerIDCertMap)
+		// We may not find an issuer with this certificate; that's fine so
+		// ignore the return value.
+		associateRevokedCertWithIsssuer(&revInfo, cert, issuerIDCertMap)
+	}
 
 	revEntry, err := logical.StorageEntryJSON(revokedPath+hyphenSerial, revInfo)
 	if err != nil {
-		return nil, fmt.Errorf("error creating revocation entry: %w", err)
+		return nil, false, fmt.Errorf("error creating revocation entry: %w", err)
 	}
 
 	certsCounted := sc.Backend.certsCounted.Load()
 	err = sc.Storage.Put(sc.Context, revEntry)
 	if err != nil {
-		return nil, fmt.Errorf("error saving revoked certificate to new location: %w", err)
+		return nil, false, fmt.Errorf("error saving revoked certificate to new location: %w", err)
+	}
+	if curRevInfo == nil {
+		sc.Backend.ifCountEnabledIncrementTotalRevokedCertificatesCount(certsCounted, revEntry.Key)
 	}
-	sc.Backend.ifCountEnabledIncrementTotalRevokedCertificatesCount(certsCounted, revEntry.Key)
 
 	// From here on out, the certificate has been revoked locally. Any other
 	// persistence issues might still err, but any other failure messages
@@ -620,10 +777,82 @@ func revokeCert(sc *storageContext, config *crlConfig, cert *x509.Certificate) (
 		Data: map[string]interface{}{
 			"revocation_time":         revInfo.RevocationTime,
 			"revocation_time_rfc3339": revInfo.RevocationTimeUTC.Format(time.RFC3339Nano),
-			"state":                   "revoked",
+			"state":                   revocationState(&revInfo),
 		},
 	}
 
+	if revInfo.OcspOnly {
+		// The CRLs don't list this certificate, so they needn't change.
+		resp.AddWarning("this certificate was issued by a role with ocsp_only_revocation; its revocation is reported by OCSP but not listed on CRLs")
+		return resp, false, nil
+	}
+
+	return resp, true, nil
+}
+
+func revocationState(revInfo *revocationInfo) string {
+	if revInfo.OnHold {
+		return "held"
+	}
+	return "revoked"
+}
+
+// releaseCert removes a held certificate from the CRL, restoring it to a
+// valid state. When delta CRLs are enabled, the release is listed on the next
+// delta CRL with the removeFromCRL reason until the complete CRL is rebuilt.
+func releaseCert(sc *storageContext, config *crlConfig, serial string) (*logical.Response, error) {
+	revInfo, err := sc.fetchRevocationInfo(serial)
+	if err != nil {
+		return nil, err
+	}
+	if revInfo == nil || !revInfo.OnHold {
+		return logical.ErrorResponse(fmt.Sprintf("certificate with serial %s is not on hold", serial)), nil
+	}
+
+	hyphenSerial := normalizeSerial(serial)
+	colonSerial := denormalizeSerial(hyphenSerial)
+
+	if config.AutoRebuild && config.EnableDelta && !revInfo.OcspOnly {
+		// Keep the held entry around so the delta CRL can reference it.
+		releasedEntry, err := logical.StorageEntryJSON(releasedPath+hyphenSerial, revInfo)
+		if err != nil {
+			return nil, fmt.Errorf("error creating released entry: %w", err)
+		}
+		if err := sc.Storage.Put(sc.Context, releasedEntry); err != nil {
+			return nil, fmt.Errorf("error saving released entry: %w", err)
+		}
+	}
+
+	if err := sc.Storage.Delete(sc.Context, revokedPath+hyphenSerial); err != nil {
+		return nil, fmt.Errorf("error removing revocation entry: %w", err)
+	}
+	sc.Backend.ifCountEnabledDecrementTotalRevokedCertificatesCountReport()
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"state": "released",
+		},
+	}
+	if revInfo.OcspOnly {
+		return resp, nil
+	}
+
+	return updateCRLsAfterRevocationChange(sc, config, resp, colonSerial)
+}
+
+// updateCRLsAfterRevocationChange rebuilds the CRLs, or records the change in
+// the delta WAL, after the revocation state of a certificate changed.
+func updateCRLsAfterRevocationChange(sc *storageContext, config *crlConfig, resp *logical.Response, colonSerial string) (*logical.Response, error) {
+	return updateCRLsAfterRevocationChanges(sc, config, resp, []string{colonSerial})
+}
+
+// updateCRLsAfterRevocationChanges is updateCRLsAfterRevocationChange for a
+// batch of changed serials, rebuilding the CRLs at most once.
+func updateCRLsAfterRevocationChanges(sc *storageContext, config *crlConfig, resp *logical.Response, colonSerials []string) (*logical.Response, error) {
+	if len(colonSerials) == 0 {
+		return resp, nil
+	}
+
 	if !config.AutoRebuild {
 		// Note that writing the Delta WAL here isn't necessary; we've
 		// already rebuilt the full CRL so the Delta WAL will be cleared
@@ -642,8 +871,10 @@ func revokeCert(sc *storageContext, config *crlConfig, cert *x509.Certificate) (
 			resp.AddWarning(fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning))
 		}
 	} else if config.EnableDelta {
-		if err := writeRevocationDeltaWALs(sc, config, resp, hyphenSerial, colonSerial); err != nil {
-			return nil, fmt.Errorf("failed to write WAL entries for Delta CRLs: %w", err)
+		for _, colonSerial := range colonSerials {
+			if err := writeRevocationDeltaWALs(sc, config, resp, normalizeSerial(colonSerial), colonSerial); err != nil {
+				return nil, fmt.Errorf("failed to write WAL entries for Delta CRLs: %w", err)
+			}
 		}
 	}
 
@@ -935,7 +1166,7 @@ func buildAnyLocalCRLs(
 		// these certificates to an issuer. Some certificates will not be
 		// assignable (if they were issued by a since-deleted issuer), so we need
 		// a separate pool for those.
-		unassignedCerts, revokedCertsMap, err = getLocalRevokedCertEntries(sc, issuerIDCertMap, isDelta)
+		unassignedCerts, revokedCertsMap, err = getLocalRevokedCertEntries(sc, issuerIDCertMap, isDelta, false, globalCRLConfig)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error building CRLs: unable to get revoked certificate entries: %w", err)
 		}
@@ -979,6 +1210,12 @@ func buildAnyLocalCRLs(
 		if err := sc.setLocalCRLConfig(internalCRLConfig); err != nil {
 			return nil, nil, fmt.Errorf("error building CRLs: unable to persist updated cluster-local CRL config: %w", err)
 		}
+
+		// The combined CRL merges the CRLs just written, so rebuild it
+		// alongside them rather than on each fetch.
+		if err := sc.writeCombinedCRL(); err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to rebuild combined CRL: %v", err))
+		}
 	}
 
 	if isDelta {
@@ -1222,7 +1459,19 @@ func associateRevokedCertWithIsssuer(revInfo *revocationInfo, revokedCert *x509.
 	return false
 }
 
-func getLocalRevokedCertEntries(sc *storageContext, issuerIDCertMap map[issuerID]*x509.Certificate, isDelta bool) ([]pkix.RevokedCertificate, map[issuerID][]pkix.RevokedCertificate, error) {
+// revokedCertEntryResult is the outcome of loading a single revocation entry
+// during a CRL build.
+type revokedCertEntryResult struct {
+	skip     bool
+	assigned bool
+	issuer   issuerID
+	entry    pkix.RevokedCertificate
+}
+
+// getLocalRevokedCertEntries loads the local revocation entries, grouped by
+// the issuer whose CRL lists them. Unless readOnly is set, entries missing
+// their issuer are updated with the one found, to speed up later builds.
+func getLocalRevokedCertEntries(sc *storageContext, issuerIDCertMap map[issuerID]*x509.Certificate, isDelta bool, readOnly bool, config *crlConfig) ([]pkix.RevokedCertificate, map[issuerID][]pkix.RevokedCertificate, error) {
 	var unassignedCerts []pkix.RevokedCertificate
 	revokedCertsMap := make(map[issuerID][]pkix.RevokedCertificate)
 
@@ -1231,11 +1480,6 @@ func getLocalRevokedCertEntries(sc *storageContext, issuerIDCertMap map[issuerID
 		listingPath = localDeltaWALPath
 	}
 
-	revokedSerials, err := sc.Storage.List(sc.Context, listingPath)
-	if err != nil {
-		return nil, nil, errutil.InternalError{Err: fmt.Sprintf("error fetching list of revoked certs: %s", err)}
-	}
-
 	// Build a mapping of issuer serial -> certificate.
 	issuerSerialCertMap := make(map[string][]*x509.Certificate, len(issuerIDCertMap))
 	for _, cert := range issuerIDCertMap {
@@ -1243,112 +1487,220 @@ func getLocalRevokedCertEntries(sc *storageContext, issuerIDCertMap map[issuerID
 		issuerSerialCertMap[serialStr] = append(issuerSerialCertMap[serialStr], cert)
 	}
 
-	for _, serial := range revokedSerials {
-		if isDelta && (serial == deltaWALLastBuildSerialName || serial == deltaWALLastRevokedSerialName) {
-			// Skip our placeholder entries...
-			continue
-		}
+	batchSize := config.BuildBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultCrlBuildBatchSize
+	}
+	parallelism := config.BuildParallelism
+	if parallelism <= 0 {
+		parallelism = defaultCrlBuildParallelism
+	}
 
-		var revInfo revocationInfo
-		revokedEntry, err := sc.Storage.Get(sc.Context, revokedPath+serial)
+	// Rather than loading every revoked serial at once, stream them from
+	// storage in batches. Within a batch, entries are loaded and parsed by a
+	// bounded pool of workers; results are collected in listing order so
+	// the resulting CRL is identical to a serial build.
+	var after string
+	for {
+		revokedSerials, err := sc.Storage.ListPage(sc.Context, listingPath, after, batchSize)
 		if err != nil {
-			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("unable to fetch revoked cert with serial %s: %s", serial, err)}
+			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("error fetching list of revoked certs: %s", err)}
 		}
 
-		if revokedEntry == nil {
-			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("revoked certificate entry for serial %s is nil", serial)}
+		results := make([]revokedCertEntryResult, len(revokedSerials))
+		errs := make([]error, len(revokedSerials))
+
+		indices := make(chan int)
+		var wg sync.WaitGroup
+		for worker := 0; worker < parallelism && worker < len(revokedSerials); worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for index := range indices {
+					results[index], errs[index] = loadLocalRevokedCertEntry(sc, revokedSerials[index], issuerIDCertMap, issuerSerialCertMap, isDelta, readOnly)
+				}
+			}()
 		}
-		if revokedEntry.Value == nil || len(revokedEntry.Value) == 0 {
-			// TODO: In this case, remove it and continue? How likely is this to
-			// happen? Alternately, could skip it entirely, or could implement a
-			// delete function so that there is a way to remove these
-			return nil, nil, errutil.InternalError{Err: "found revoked serial but actual certificate is empty"}
+		for index := range revokedSerials {
+			indices <- index
 		}
+		close(indices)
+		wg.Wait()
 
-		err = revokedEntry.DecodeJSON(&revInfo)
-		if err != nil {
-			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("error decoding revocation entry for serial %s: %s", serial, err)}
+		for index, result := range results {
+			if errs[index] != nil {
+				return nil, nil, errs[index]
+			}
+			switch {
+			case result.skip:
+			case result.assigned:
+				revokedCertsMap[result.issuer] = append(revokedCertsMap[result.issuer], result.entry)
+			default:
+				// If the parent isn't found, add it to the unassigned bucket.
+				unassignedCerts = append(unassignedCerts, result.entry)
+			}
+		}
+
+		if len(revokedSerials) < batchSize {
+			break
 		}
+		after = revokedSerials[len(revokedSerials)-1]
+	}
 
-		revokedCert, err := x509.ParseCertificate(revInfo.CertificateBytes)
+	return unassignedCerts, revokedCertsMap, nil
+}
+
+// loadLocalRevokedCertEntry loads and parses a single revocation entry and
+// assigns it to its issuer. This may be called concurrently for distinct
+// serials.
+func loadLocalRevokedCertEntry(sc *storageContext, serial string, issuerIDCertMap map[issuerID]*x509.Certificate, issuerSerialCertMap map[string][]*x509.Certificate, isDelta bool, readOnly bool) (revokedCertEntryResult, error) {
+	var result revokedCertEntryResult
+
+	if isDelta && (serial == deltaWALLastBuildSerialName || serial == deltaWALLastRevokedSerialName) {
+		// Skip our placeholder entries...
+		result.skip = true
+		return result, nil
+	}
+
+	var revInfo revocationInfo
+	revokedEntry, err := sc.Storage.Get(sc.Context, revokedPath+serial)
+	if err != nil {
+		return result, errutil.InternalError{Err: fmt.Sprintf("unable to fetch revoked cert with serial %s: %s", serial, err)}
+	}
+
+	// A held certificate which was since released is listed on the
+	// delta CRL with the removeFromCRL reason.
+	released := false
+	if revokedEntry == nil && isDelta {
+		revokedEntry, err = sc.Storage.Get(sc.Context, releasedPath+serial)
 		if err != nil {
-			return nil, nil, errutil.InternalError{Err: fmt.Sprintf("unable to parse stored revoked certificate with serial %s: %s", serial, err)}
+			return result, errutil.InternalError{Err: fmt.Sprintf("unable to fetch released cert with serial %s: %s", serial, err)}
 		}
+		released = revokedEntry != nil
+	}
 
-		// We want to skip issuer certificate's revocationEntries for two
-		// reasons:
-		//
-		// 1. We canonically use augmentWithRevokedIssuers to handle this
-		//    case and this entry is just a backup. This prevents the issue
-		//    of duplicate serial numbers on the CRL from both paths.
-		// 2. We want to avoid a root's serial from appearing on its own
-		//    CRL. If it is a cross-signed or re-issued variant, this is OK,
-		//    but in the case we mark the root itself as "revoked", we want
-		//    to avoid it appearing on the CRL as that is definitely
-		//    undefined/little-supported behavior.
-		//
-		// This hash map lookup should be faster than byte comparison against
-		// each issuer proactively.
-		if candidates, present := issuerSerialCertMap[serialFromCert(revokedCert)]; present {
-			revokedCertIsIssuer := false
-			for _, candidate := range candidates {
-				if bytes.Equal(candidate.Raw, revokedCert.Raw) {
-					revokedCertIsIssuer = true
-					break
-				}
-			}
+	if revokedEntry == nil {
+		return result, errutil.InternalError{Err: fmt.Sprintf("revoked certificate entry for serial %s is nil", serial)}
+	}
+	if revokedEntry.Value == nil || len(revokedEntry.Value) == 0 {
+		// TODO: In this case, remove it and continue? How likely is this to
+		// happen? Alternately, could skip it entirely, or could implement a
+		// delete function so that there is a way to remove these
+		return result, errutil.InternalError{Err: "found revoked serial but actual certificate is empty"}
+	}
 
-			if revokedCertIsIssuer {
-				continue
+	err = revokedEntry.DecodeJSON(&revInfo)
+	if err != nil {
+		return result, errutil.InternalError{Err: fmt.Sprintf("error decoding revocation entry for serial %s: %s", serial, err)}
+	}
+
+	if revInfo.OcspOnly {
+		// Only reported by OCSP, never listed on CRLs.
+		result.skip = true
+		return result, nil
+	}
+
+	revokedCert, err := x509.ParseCertificate(revInfo.CertificateBytes)
+	if err != nil {
+		return result, errutil.InternalError{Err: fmt.Sprintf("unable to parse stored revoked certificate with serial %s: %s", serial, err)}
+	}
+
+	// We want to skip issuer certificate's revocationEntries for two
+	// reasons:
+	//
+	// 1. We canonically use augmentWithRevokedIssuers to handle this
+	//    case and this entry is just a backup. This prevents the issue
+	//    of duplicate serial numbers on the CRL from both paths.
+	// 2. We want to avoid a root's serial from appearing on its own
+	//    CRL. If it is a cross-signed or re-issued variant, this is OK,
+	//    but in the case we mark the root itself as "revoked", we want
+	//    to avoid it appearing on the CRL as that is definitely
+	//    undefined/little-supported behavior.
+	//
+	// This hash map lookup should be faster than byte comparison against
+	// each issuer proactively.
+	if candidates, present := issuerSerialCertMap[serialFromCert(revokedCert)]; present {
+		revokedCertIsIssuer := false
+		for _, candidate := range candidates {
+			if bytes.Equal(candidate.Raw, revokedCert.Raw) {
+				revokedCertIsIssuer = true
+				break
 			}
 		}
 
-		// NOTE: We have to change this to UTC time because the CRL standard
-		// mandates it but Go will happily encode the CRL without this.
-		newRevCert := pkix.RevokedCertificate{
-			SerialNumber: revokedCert.SerialNumber,
-		}
-		if !revInfo.RevocationTimeUTC.IsZero() {
-			newRevCert.RevocationTime = revInfo.RevocationTimeUTC
-		} else {
-			newRevCert.RevocationTime = time.Unix(revInfo.RevocationTime, 0).UTC()
+		if revokedCertIsIssuer {
+			result.skip = true
+			return result, nil
 		}
+	}
 
-		// If we have a CertificateIssuer field on the revocation entry,
-		// prefer it to manually checking each issuer signature, assuming it
-		// appears valid. It's highly unlikely for two different issuers
-		// to have the same id (after the first was deleted).
-		if isRevInfoIssuerValid(&revInfo, issuerIDCertMap) {
-			revokedCertsMap[revInfo.CertificateIssuer] = append(revokedCertsMap[revInfo.CertificateIssuer], newRevCert)
-			continue
+	// NOTE: We have to change this to UTC time because the CRL standard
+	// mandates it but Go will happily encode the CRL without this.
+	newRevCert := pkix.RevokedCertificate{
+		SerialNumber: revokedCert.SerialNumber,
+	}
+	if !revInfo.RevocationTimeUTC.IsZero() {
+		newRevCert.RevocationTime = revInfo.RevocationTimeUTC
+	} else {
+		newRevCert.RevocationTime = time.Unix(revInfo.RevocationTime, 0).UTC()
+	}
 
-			// Otherwise, fall through and update the entry.
+	if released || revInfo.OnHold || revInfo.RevocationReason != ocsp.Unspecified {
+		reason := revInfo.RevocationReason
+		if revInfo.OnHold {
+			reason = ocsp.CertificateHold
+		}
+		if released {
+			reason = ocsp.RemoveFromCRL
 		}
 
-		// Now we need to assign the revoked certificate to an issuer.
-		foundParent := associateRevokedCertWithIsssuer(&revInfo, revokedCert, issuerIDCertMap)
-		if !foundParent {
-			// If the parent isn't found, add it to the unassigned bucket.
-			unassignedCerts = append(unassignedCerts, newRevCert)
-		} else {
-			revokedCertsMap[revInfo.CertificateIssuer] = append(revokedCertsMap[revInfo.CertificateIssuer], newRevCert)
+		reasonExt, err := crlReasonCodeExtension(reason)
+		if err != nil {
+			return result, errutil.InternalError{Err: fmt.Sprintf("unable to encode revocation reason for serial %s: %s", serial, err)}
+		}
+		newRevCert.Extensions = append(newRevCert.Extensions, reasonExt)
+	}
 
-			// When the CertificateIssuer field wasn't found on the existing
-			// entry (or was invalid), and we've found a new value for it,
-			// we should update the entry to make future CRL builds faster.
-			revokedEntry, err = logical.StorageEntryJSON(revokedPath+serial, revInfo)
-			if err != nil {
-				return nil, nil, fmt.Errorf("error creating revocation entry for existing cert: %v: %w", serial, err)
-			}
+	// If we have a CertificateIssuer field on the revocation entry,
+	// prefer it to manually checking each issuer signature, assuming it
+	// appears valid. It's highly unlikely for two different issuers
+	// to have the same id (after the first was deleted).
+	result.entry = newRevCert
+	if isRevInfoIssuerValid(&revInfo, issuerIDCertMap) {
+		result.assigned = true
+		result.issuer = revInfo.CertificateIssuer
+		return result, nil
 
-			err = sc.Storage.Put(sc.Context, revokedEntry)
-			if err != nil {
-				return nil, nil, fmt.Errorf("error updating revoked certificate at existing location: %v: %w", serial, err)
-			}
+		// Otherwise, fall through and update the entry.
+	}
+
+	// Now we need to assign the revoked certificate to an issuer. If the
+	// parent isn't found, it is added to the unassigned bucket.
+	foundParent := associateRevokedCertWithIsssuer(&revInfo, revokedCert, issuerIDCertMap)
+	if foundParent {
+		result.assigned = true
+		result.issuer = revInfo.CertificateIssuer
+		if released || readOnly {
+			// The revocation entry no longer exists or mustn't be
+			// modified; don't (re)write it.
+			return result, nil
+		}
+
+		// When the CertificateIssuer field wasn't found on the existing
+		// entry (or was invalid), and we've found a new value for it,
+		// we should update the entry to make future CRL builds faster.
+		revokedEntry, err = logical.StorageEntryJSON(revokedPath+serial, revInfo)
+		if err != nil {
+			return result, fmt.Errorf("error creating revocation entry for existing cert: %v: %w", serial, err)
+		}
+
+		err = sc.Storage.Put(sc.Context, revokedEntry)
+		if err != nil {
+			return result, fmt.Errorf("error updating revoked certificate at existing location: %v: %w", serial, err)
 		}
 	}
 
-	return unassignedCerts, revokedCertsMap, nil
+	return result, nil
 }
 
 func augmentWithRevokedIssuers(issuerIDEntryMap map[issuerID]*issuerEntry, issuerIDCertMap map[issuerID]*x509.Certificate, revokedCertsMap map[issuerID][]pkix.RevokedCertificate) error {
@@ -1480,3 +1832,246 @@ WRITE:
 
 	return &nextUpdate, nil
 }
+
+var (
+	oidPKCS7Data       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7SignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs7CRLSignedData is a degenerate PKCS#7 SignedData structure which only
+// carries CRLs, without any signers or certificates.
+type pkcs7CRLSignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      pkcs7ContentInfo
+	CRLs             asn1.RawValue
+	SignerInfos      []asn1.RawValue `asn1:"set"`
+}
+
+// pkcs7CertsSignedData is a degenerate PKCS#7 SignedData structure which
+// only carries certificates, without any signers or CRLs.
+type pkcs7CertsSignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []asn1.RawValue `asn1:"set"`
+}
+
+// wrapCRLInPKCS7 wraps the given DER CRL in a degenerate (unsigned) PKCS#7
+// SignedData envelope, as consumed by tooling expecting .p7c/.p7b files. The
+// CRL is embedded byte-for-byte and no randomness is involved, so the same
+// CRL always yields the same envelope.
+func wrapCRLInPKCS7(crl []byte) ([]byte, error) {
+	if _, err := x509.ParseRevocationList(crl); err != nil {
+		return nil, fmt.Errorf("unable to parse CRL: %w", err)
+	}
+
+	signedData, err := asn1.Marshal(pkcs7CRLSignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{},
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidPKCS7Data},
+		CRLs: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        1,
+			IsCompound: true,
+			Bytes:      crl,
+		},
+		SignerInfos: []asn1.RawValue{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal PKCS#7 SignedData: %w", err)
+	}
+
+	return wrapSignedDataInPKCS7(signedData)
+}
+
+// wrapCertsInPKCS7 wraps the given DER certificates, in order, in a
+// degenerate (unsigned) PKCS#7 SignedData envelope, the certificates-only
+// .p7b bundle preferred by some trust store importers.
+func wrapCertsInPKCS7(certs [][]byte) ([]byte, error) {
+	signedData, err := asn1.Marshal(pkcs7CertsSignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{},
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidPKCS7Data},
+		Certificates: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      bytes.Join(certs, nil),
+		},
+		SignerInfos: []asn1.RawValue{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal PKCS#7 SignedData: %w", err)
+	}
+
+	return wrapSignedDataInPKCS7(signedData)
+}
+
+// wrapSignedDataInPKCS7 wraps an encoded SignedData structure in the PKCS#7
+// ContentInfo identifying it.
+func wrapSignedDataInPKCS7(signedData []byte) ([]byte, error) {
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidPKCS7SignedData,
+		Content: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      signedData,
+		},
+	})
+}
+
+// writeCombinedCRL rebuilds the combined CRL served on crl/combined and
+// stores it, or removes it when there is no default issuer or complete CRL.
+func (sc *storageContext) writeCombinedCRL() error {
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return err
+	}
+	if config.DefaultIssuerId == "" {
+		return sc.Storage.Delete(sc.Context, combinedCRLPath)
+	}
+
+	crlBytes, err := sc.buildCombinedCRL()
+	if err != nil {
+		return err
+	}
+	if len(crlBytes) == 0 {
+		return sc.Storage.Delete(sc.Context, combinedCRLPath)
+	}
+
+	return sc.Storage.Put(sc.Context, &logical.StorageEntry{
+		Key:   combinedCRLPath,
+		Value: crlBytes,
+	})
+}
+
+// fetchCombinedCRL returns the stored combined CRL. Mounts whose CRLs were
+// last rebuilt before it was stored, or still using the legacy bundle, serve
+// the default issuer's complete CRL instead.
+func (sc *storageContext) fetchCombinedCRL() ([]byte, error) {
+	entry, err := sc.Storage.Get(sc.Context, combinedCRLPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && len(entry.Value) > 0 {
+		return entry.Value, nil
+	}
+
+	baseEntry, err := fetchCertBySerial(sc, "crl", legacyCRLPath)
+	if err != nil || baseEntry == nil {
+		return nil, err
+	}
+	return baseEntry.Value, nil
+}
+
+// buildCombinedCRL merges the default issuer's delta CRL into its complete
+// CRL, for consumers which only support a single CRL. The result carries the
+// union of both CRLs' entries and their extensions, less those the delta CRL
+// removes from the CRL, under the delta CRL's number, and is signed afresh by
+// the default issuer. When there is no newer delta CRL, the complete CRL is
+// returned as stored; when there is no complete CRL, nil is returned.
+func (sc *storageContext) buildCombinedCRL() ([]byte, error) {
+	baseEntry, err := fetchCertBySerial(sc, "crl", legacyCRLPath)
+	if err != nil {
+		return nil, err
+	}
+	if baseEntry == nil {
+		return nil, nil
+	}
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return baseEntry.Value, nil
+	}
+
+	deltaEntry, err := fetchCertBySerial(sc, "crl", deltaCRLPath)
+	if err != nil {
+		return nil, err
+	}
+	if deltaEntry == nil || len(deltaEntry.Value) == 0 {
+		return baseEntry.Value, nil
+	}
+
+	base, err := x509.ParseRevocationList(baseEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stored CRL: %w", err)
+	}
+	delta, err := x509.ParseRevocationList(deltaEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stored delta CRL: %w", err)
+	}
+
+	// A delta CRL older than the complete CRL is already reflected in it.
+	if delta.Number.Cmp(base.Number) <= 0 {
+		return baseEntry.Value, nil
+	}
+
+	signingBundle, err := sc.fetchCAInfo(defaultRef, CRLSigningUsage)
+	if err != nil {
+		return nil, err
+	}
+	if err := base.CheckSignatureFrom(signingBundle.Certificate); err != nil {
+		return nil, fmt.Errorf("stored CRL was not signed by the default issuer: %w", err)
+	}
+
+	entries := make(map[string]x509.RevocationListEntry, len(base.RevokedCertificateEntries)+len(delta.RevokedCertificateEntries))
+	var order []string
+	for _, list := range []*x509.RevocationList{base, delta} {
+		for _, entry := range list.RevokedCertificateEntries {
+			serial := serialFromBigInt(entry.SerialNumber)
+			if entry.ReasonCode == ocsp.RemoveFromCRL {
+				delete(entries, serial)
+				continue
+			}
+			if _, seen := entries[serial]; !seen {
+				order = append(order, serial)
+			}
+			// The reason code is re-encoded from ReasonCode; any other
+			// entry extension is carried over as is.
+			var extensions []pkix.Extension
+			for _, ext := range entry.Extensions {
+				if !ext.Id.Equal(oidCRLReasonCode) {
+					extensions = append(extensions, ext)
+				}
+			}
+			entries[serial] = x509.RevocationListEntry{
+				SerialNumber:    entry.SerialNumber,
+				RevocationTime:  entry.RevocationTime,
+				ReasonCode:      entry.ReasonCode,
+				ExtraExtensions: extensions,
+			}
+		}
+	}
+
+	revoked := make([]x509.RevocationListEntry, 0, len(entries))
+	for _, serial := range order {
+		if entry, ok := entries[serial]; ok {
+			revoked = append(revoked, entry)
+		}
+	}
+
+	nextUpdate := delta.NextUpdate
+	if base.NextUpdate.Before(nextUpdate) {
+		nextUpdate = base.NextUpdate
+	}
+
+	template := &x509.RevocationList{
+		RevokedCertificateEntries: revoked,
+		Number:                    delta.Number,
+		ThisUpdate:                delta.ThisUpdate,
+		NextUpdate:                nextUpdate,
+		SignatureAlgorithm:        signingBundle.RevocationSigAlg,
+	}
+
+	crlBytes, err := x509.CreateRevocationList(rand.Reader, template, signingBundle.Certificate, signingBundle.PrivateKey)
+	if err != nil {
+		return nil, errutil.InternalError{Err: fmt.Sprintf("error creating combined CRL: %s", err)}
+	}
+	return crlBytes, nil
+}
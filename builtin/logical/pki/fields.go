@@ -89,6 +89,31 @@ comma-delimited list.`,
 		},
 	}
 
+	fields["directory_name_sans"] = &framework.FieldSchema{
+		Type: framework.TypeStringSlice,
+		Description: `Requested directoryName SANs, in an array of RFC 4514
+DN strings, such as "CN=Jane Doe,O=Example,C=US".`,
+		DisplayAttrs: &framework.DisplayAttributes{
+			Name: "Directory Name SANs",
+		},
+	}
+
+	fields = addStructuredWarningsField(fields)
+
+	return fields
+}
+
+// addStructuredWarningsField adds the option to return response warnings
+// as structured objects in addition to plain strings.
+func addStructuredWarningsField(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	fields[structuredWarningsParam] = &framework.FieldSchema{
+		Type:    framework.TypeBool,
+		Default: false,
+		Description: `If true, response warnings are additionally returned
+in the structured_warnings field as objects with code, message, and
+(optionally) field keys. The plain warnings are always returned.`,
+	}
+
 	return fields
 }
 
@@ -172,6 +197,15 @@ Any values are added with OID 0.9.2342.19200300.100.1.1.`,
 		},
 	}
 
+	fields[explainParam] = &framework.FieldSchema{
+		Type:    framework.TypeBool,
+		Default: false,
+		Description: `If true, the response includes a policy_decisions
+list tracing each role policy check performed on the request, with the
+value checked, whether it passed and the governing role field. Rejected
+requests return it alongside the error.`,
+	}
+
 	fields = addIssuerRefField(fields)
 
 	return fields
@@ -413,6 +447,20 @@ to the issuer.`,
 	return fields
 }
 
+// addIssuerOverrideField adds the issuer_ref parameter to the sign and issue
+// paths. On the issuer/:issuer_ref/ variants, this is instead part of the
+// request URL.
+func addIssuerOverrideField(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	fields[issuerRefParam] = &framework.FieldSchema{
+		Type: framework.TypeString,
+		Description: `Reference to a existing issuer; either "default"
+for the configured default issuer, an identifier or the name assigned
+to the issuer. On the sign/:role and issue/:role paths, this overrides
+the role's issuer and must be permitted by the role's allowed_issuers.`,
+	}
+	return fields
+}
+
 func addKeyRefNameFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
 	fields = addKeyNameField(fields)
 	fields = addKeyRefField(fields)
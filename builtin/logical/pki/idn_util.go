@@ -0,0 +1,172 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"golang.org/x/net/idna"
+)
+
+// Values of a role's idn_handling.
+const (
+	// idnHandlingPreserve keeps the historical behavior: DNS SANs are
+	// converted to A-labels, but the common name is validated and issued
+	// as requested.
+	idnHandlingPreserve = "preserve"
+
+	// idnHandlingALabel converts all internationalized domain names, in the
+	// common name, DNS SANs and email addresses alike, to their A-label
+	// (punycode) form before validating them against the role.
+	idnHandlingALabel = "a_label"
+
+	// idnHandlingALabelSingleScript additionally rejects labels mixing
+	// scripts, a common source of confusable (homograph) names.
+	idnHandlingALabelSingleScript = "a_label_single_script"
+)
+
+func validateIDNHandling(entry *roleEntry) error {
+	switch entry.IDNHandling {
+	case "":
+		entry.IDNHandling = idnHandlingPreserve
+	case idnHandlingPreserve, idnHandlingALabel, idnHandlingALabelSingleScript:
+	default:
+		return fmt.Errorf("invalid idn_handling %q: must be %q, %q or %q", entry.IDNHandling, idnHandlingPreserve, idnHandlingALabel, idnHandlingALabelSingleScript)
+	}
+	return nil
+}
+
+func normalizesIDNs(role *roleEntry) bool {
+	return role.IDNHandling == idnHandlingALabel || role.IDNHandling == idnHandlingALabelSingleScript
+}
+
+// normalizeIDNName converts the domain of a DNS name or email address to its
+// A-label form according to the role's idn_handling, rejecting mixed-script
+// labels when the role requires it. Names are returned unmodified for roles
+// preserving IDNs.
+func normalizeIDNName(role *roleEntry, name string) (string, error) {
+	if !normalizesIDNs(role) {
+		return name, nil
+	}
+
+	local, domain, isEmail := strings.Cut(name, "@")
+	if !isEmail {
+		domain, local = name, ""
+	}
+
+	converted, err := toALabels(domain, role.IDNHandling == idnHandlingALabelSingleScript)
+	if err != nil {
+		return "", fmt.Errorf("invalid internationalized domain name %q: %w", name, err)
+	}
+
+	if isEmail {
+		return local + "@" + converted, nil
+	}
+	return converted, nil
+}
+
+// toALabels converts each label of domain containing non-ASCII characters to
+// its A-label. Labels containing wildcards are left as-is, to be validated
+// by the usual wildcard handling.
+func toALabels(domain string, singleScript bool) (string, error) {
+	labels := strings.Split(domain, ".")
+	for index, label := range labels {
+		if strings.Contains(label, "*") {
+			continue
+		}
+
+		unicodeLabel := label
+		if strings.HasPrefix(strings.ToLower(label), "xn--") {
+			decoded, err := idna.Lookup.ToUnicode(label)
+			if err != nil {
+				return "", err
+			}
+			unicodeLabel = decoded
+		} else if isASCII(label) {
+			continue
+		}
+
+		if singleScript {
+			if scripts := labelScripts(unicodeLabel); !allowedScriptMix(scripts) {
+				return "", fmt.Errorf("label %q mixes scripts %s", unicodeLabel, strings.Join(scripts, ", "))
+			}
+		}
+
+		converted, err := idna.Lookup.ToASCII(unicodeLabel)
+		if err != nil {
+			return "", err
+		}
+		labels[index] = converted
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func isASCII(value string) bool {
+	for index := 0; index < len(value); index++ {
+		if value[index] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// labelScripts returns the sorted names of the scripts used by a label,
+// ignoring the Common and Inherited pseudo-scripts (digits, hyphens and
+// combining marks).
+func labelScripts(label string) []string {
+	seen := map[string]bool{}
+	for _, r := range label {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				seen[name] = true
+				break
+			}
+		}
+	}
+
+	scripts := make([]string, 0, len(seen))
+	for name := range seen {
+		scripts = append(scripts, name)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// allowedScriptMix follows the "Highly Restrictive" level of Unicode
+// Technical Standard #39: a label may use a single script, or Latin combined
+// with the scripts customarily written together in Japanese, Chinese or
+// Korean.
+func allowedScriptMix(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+
+	for _, permitted := range [][]string{
+		{"Latin", "Han", "Hiragana", "Katakana"},
+		{"Latin", "Han", "Bopomofo"},
+		{"Latin", "Han", "Hangul"},
+	} {
+		subset := true
+		for _, script := range scripts {
+			if !strutil.StrListContains(permitted, script) {
+				subset = false
+				break
+			}
+		}
+		if subset {
+			return true
+		}
+	}
+
+	return false
+}
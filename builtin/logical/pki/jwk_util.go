@@ -0,0 +1,100 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3"
+)
+
+// Values of the format parameter of certificate and issuer fetches.
+const (
+	fetchFormatPEM = "pem"
+	fetchFormatJWK = "jwk"
+)
+
+// Values of the format parameter of CRL fetches.
+const (
+	fetchFormatDER    = "der"
+	fetchFormatBase64 = "base64"
+)
+
+// certToJWK encodes the public key of a certificate as a JWK (RFC 7517),
+// with the certificate and its issuing chain in the x5c member and the
+// certificate's SHA-256 thumbprint in x5t#S256. The chain must start with
+// the certificate itself.
+func certToJWK(chain []*x509.Certificate) (map[string]interface{}, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate to encode as a JWK")
+	}
+
+	cert := chain[0]
+	thumbprint := sha256.Sum256(cert.Raw)
+	jwk := jose.JSONWebKey{
+		Key:                         cert.PublicKey,
+		KeyID:                       serialFromCert(cert),
+		Certificates:                chain,
+		CertificateThumbprintSHA256: thumbprint[:],
+	}
+
+	encoded, err := jwk.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode certificate as a JWK: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseCertChain parses an issuer's PEM-encoded CA chain.
+func parseCertChain(pemChain []string) ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, 0, len(pemChain))
+	for _, pemCert := range pemChain {
+		cert, err := parseCertificateFromBytes([]byte(pemCert))
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
+
+// fetchChainForCert returns the certificate followed by the CA chain of the
+// issuer recorded as having signed it. When no issuer was recorded or it was
+// since deleted, only the certificate itself is returned.
+func (sc *storageContext) fetchChainForCert(serial string, cert *x509.Certificate) ([]*x509.Certificate, error) {
+	chain := []*x509.Certificate{cert}
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return chain, nil
+	}
+
+	issuerId, err := sc.resolveCertIssuer(serial)
+	if err != nil || issuerId == "" {
+		return chain, err
+	}
+	issuer, err := sc.fetchIssuerById(issuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	caChain, err := parseCertChain(issuer.CAChain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse chain of issuer %v: %w", issuer.ID, err)
+	}
+	return append(chain, caChain...), nil
+}
+
+// certSignedBy reports whether the certificate was signed by the issuer
+// certificate.
+func certSignedBy(cert *x509.Certificate, issuerCert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, issuerCert.RawSubject) && cert.CheckSignatureFrom(issuerCert) == nil
+}
@@ -266,7 +266,7 @@ func (b *backend) acmeFinalizeOrderHandler(ac *acmeContext, _ *logical.Request,
 	}
 
 	hyphenSerialNumber := normalizeSerialFromBigInt(signedCertBundle.Certificate.SerialNumber)
-	err = storeCertificate(ac.sc, signedCertBundle)
+	err = storeCertificate(ac.sc, signedCertBundle, &certIssuanceEntry{IssuerID: issuerId, Role: ac.role.Name, RACredential: len(ac.role.RANamespaces) > 0})
 	if err != nil {
 		return nil, err
 	}
@@ -462,7 +462,7 @@ func removeDuplicatesAndSortIps(ipIdentifiers []net.IP) []net.IP {
 	return uniqueIpIdentifiers
 }
 
-func storeCertificate(sc *storageContext, signedCertBundle *certutil.ParsedCertBundle) error {
+func storeCertificate(sc *storageContext, signedCertBundle *certutil.ParsedCertBundle, issuance *certIssuanceEntry) error {
 	hyphenSerialNumber := normalizeSerialFromBigInt(signedCertBundle.Certificate.SerialNumber)
 	key := "certs/" + hyphenSerialNumber
 	certsCounted := sc.Backend.certsCounted.Load()
@@ -474,6 +474,10 @@ func storeCertificate(sc *storageContext, signedCertBundle *certutil.ParsedCertB
 		return fmt.Errorf("unable to store certificate locally: %w", err)
 	}
 	sc.Backend.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
+
+	if err := sc.writeCertIssuance(hyphenSerialNumber, issuance); err != nil {
+		return fmt.Errorf("unable to store issuance record: %w", err)
+	}
 	return nil
 }
 
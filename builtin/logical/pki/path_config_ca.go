@@ -5,8 +5,10 @@ package pki
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	"github.com/openbao/openbao/sdk/v2/framework"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
@@ -105,6 +107,20 @@ func pathConfigIssuers(b *backend) *framework.Path {
 				Description: `Whether the default issuer should automatically follow the latest generated or imported issuer. Defaults to false.`,
 				Default:     false,
 			},
+			"expired_ca_warning": {
+				Type:        framework.TypeBool,
+				Description: `Whether fetching an expired CA via the ca and ca_chain paths should return a response warning. Defaults to false.`,
+				Default:     false,
+			},
+			"expired_ca_grace_period": {
+				Type:        framework.TypeString,
+				Description: `How long after the CA expires the raw ca and ca_chain paths keep serving it, after which they return 410 Gone. Empty (the default) disables this.`,
+				Default:     "",
+			},
+			"fallback_issuer": {
+				Type:        framework.TypeString,
+				Description: `Reference (name or identifier) to an issuer served by the ca and ca_chain fetch paths while no default issuer is set. Empty (the default) disables this.`,
+			},
 		},
 		Operations: map[logical.Operation]framework.OperationHandler{
 			logical.ReadOperation: &framework.PathOperation{
@@ -126,6 +142,21 @@ func pathConfigIssuers(b *backend) *framework.Path {
 								Description: `Whether the default issuer should automatically follow the latest generated or imported issuer. Defaults to false.`,
 								Required:    true,
 							},
+							"expired_ca_warning": {
+								Type:        framework.TypeBool,
+								Description: `Whether fetching an expired CA via the ca and ca_chain paths should return a response warning. Defaults to false.`,
+								Required:    true,
+							},
+							"expired_ca_grace_period": {
+								Type:        framework.TypeString,
+								Description: `How long after the CA expires the raw ca and ca_chain paths keep serving it, after which they return 410 Gone. Empty (the default) disables this.`,
+								Required:    true,
+							},
+							"fallback_issuer": {
+								Type:        framework.TypeString,
+								Description: `Reference (name or identifier) to an issuer served by the ca and ca_chain fetch paths while no default issuer is set. Empty (the default) disables this.`,
+								Required:    true,
+							},
 						},
 					}},
 				},
@@ -148,6 +179,18 @@ func pathConfigIssuers(b *backend) *framework.Path {
 								Type:        framework.TypeBool,
 								Description: `Whether the default issuer should automatically follow the latest generated or imported issuer. Defaults to false.`,
 							},
+							"expired_ca_warning": {
+								Type:        framework.TypeBool,
+								Description: `Whether fetching an expired CA via the ca and ca_chain paths should return a response warning. Defaults to false.`,
+							},
+							"expired_ca_grace_period": {
+								Type:        framework.TypeString,
+								Description: `How long after the CA expires the raw ca and ca_chain paths keep serving it, after which they return 410 Gone. Empty (the default) disables this.`,
+							},
+							"fallback_issuer": {
+								Type:        framework.TypeString,
+								Description: `Reference (name or identifier) to an issuer served by the ca and ca_chain fetch paths while no default issuer is set. Empty (the default) disables this.`,
+							},
 						},
 					}},
 				},
@@ -197,6 +240,16 @@ func pathReplaceRoot(b *backend) *framework.Path {
 								Description: `Whether the default issuer should automatically follow the latest generated or imported issuer. Defaults to false.`,
 								Required:    true,
 							},
+							"expired_ca_warning": {
+								Type:        framework.TypeBool,
+								Description: `Whether fetching an expired CA via the ca and ca_chain paths should return a response warning. Defaults to false.`,
+								Required:    true,
+							},
+							"expired_ca_grace_period": {
+								Type:        framework.TypeString,
+								Description: `How long after the CA expires the raw ca and ca_chain paths keep serving it, after which they return 410 Gone. Empty (the default) disables this.`,
+								Required:    true,
+							},
 						},
 					}},
 				},
@@ -230,6 +283,9 @@ func (b *backend) formatCAIssuerConfigRead(config *issuerConfigEntry) *logical.R
 		Data: map[string]interface{}{
 			defaultRef:                      config.DefaultIssuerId,
 			"default_follows_latest_issuer": config.DefaultFollowsLatestIssuer,
+			"expired_ca_warning":            config.ExpiredCAWarning,
+			"expired_ca_grace_period":       config.ExpiredCAGracePeriod,
+			"fallback_issuer":               config.FallbackIssuerId,
 		},
 	}
 }
@@ -246,18 +302,37 @@ func (b *backend) pathCAIssuersWrite(ctx context.Context, req *logical.Request,
 
 	sc := b.makeStorageContext(ctx, req.Storage)
 
-	// Validate the new default reference.
-	newDefault := data.Get(defaultRef).(string)
-	if len(newDefault) == 0 || newDefault == defaultRef {
-		return logical.ErrorResponse("Invalid issuer specification; must be non-empty and can't be 'default'."), nil
-	}
-	parsedIssuer, err := sc.resolveIssuerReference(newDefault)
+	config, err := sc.getIssuersConfig()
 	if err != nil {
-		return logical.ErrorResponse("Error resolving issuer reference: " + err.Error()), nil
+		return logical.ErrorResponse("Unable to fetch existing issuers configuration: " + err.Error()), nil
 	}
-	entry, err := sc.fetchIssuerById(parsedIssuer)
-	if err != nil {
-		return logical.ErrorResponse("Unable to fetch issuer: " + err.Error()), nil
+
+	// Validate the new default reference. When only updating other
+	// parameters of config/issuers, the existing default issuer is kept;
+	// root/replace instead defaults to the issuer named "next".
+	// A default which was never set may likewise stay unset, e.g., when
+	// only configuring a fallback issuer during setup.
+	newDefault := data.Get(defaultRef).(string)
+	_, defaultOk := data.GetOk(defaultRef)
+	keepDefault := !defaultOk && req.Path == "config/issuers"
+	if keepDefault {
+		newDefault = string(config.DefaultIssuerId)
+	}
+
+	var parsedIssuer issuerID
+	var entry *issuerEntry
+	if !keepDefault || len(newDefault) > 0 {
+		if len(newDefault) == 0 || newDefault == defaultRef {
+			return logical.ErrorResponse("Invalid issuer specification; must be non-empty and can't be 'default'."), nil
+		}
+		parsedIssuer, err = sc.resolveIssuerReference(newDefault)
+		if err != nil {
+			return logical.ErrorResponse("Error resolving issuer reference: " + err.Error()), nil
+		}
+		entry, err = sc.fetchIssuerById(parsedIssuer)
+		if err != nil {
+			return logical.ErrorResponse("Unable to fetch issuer: " + err.Error()), nil
+		}
 	}
 
 	// Get the other new parameters. This doesn't exist on the /root/replace
@@ -268,11 +343,40 @@ func (b *backend) pathCAIssuersWrite(ctx context.Context, req *logical.Request,
 		followIssuer = followIssuersRaw.(bool)
 	}
 
-	// Update the config
-	config, err := sc.getIssuersConfig()
-	if err != nil {
-		return logical.ErrorResponse("Unable to fetch existing issuers configuration: " + err.Error()), nil
+	// These are likewise only present on config/issuers.
+	if warnRaw, ok := data.GetOk("expired_ca_warning"); ok {
+		config.ExpiredCAWarning = warnRaw.(bool)
+	}
+	if graceRaw, ok := data.GetOk("expired_ca_grace_period"); ok {
+		grace := graceRaw.(string)
+		if len(grace) > 0 {
+			duration, err := parseutil.ParseDurationSecond(grace)
+			if err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("given expired_ca_grace_period could not be decoded: %s", err)), nil
+			}
+			if duration < 0 {
+				return logical.ErrorResponse(fmt.Sprintf("expired_ca_grace_period must be greater than or equal to 0 got: %s", duration)), nil
+			}
+		}
+		config.ExpiredCAGracePeriod = grace
+	}
+	if fallbackRaw, ok := data.GetOk("fallback_issuer"); ok {
+		fallback := fallbackRaw.(string)
+		switch fallback {
+		case "":
+			config.FallbackIssuerId = issuerID("")
+		case defaultRef:
+			return logical.ErrorResponse("Invalid fallback_issuer; can't be 'default'."), nil
+		default:
+			fallbackId, err := sc.resolveIssuerReference(fallback)
+			if err != nil {
+				return logical.ErrorResponse("Error resolving fallback_issuer reference: " + err.Error()), nil
+			}
+			config.FallbackIssuerId = fallbackId
+		}
 	}
+
+	// Update the config
 	config.DefaultIssuerId = parsedIssuer
 	if followOk {
 		config.DefaultFollowsLatestIssuer = followIssuer
@@ -280,7 +384,7 @@ func (b *backend) pathCAIssuersWrite(ctx context.Context, req *logical.Request,
 
 	// Add our warning if necessary.
 	response := b.formatCAIssuerConfigRead(config)
-	if len(entry.KeyID) == 0 {
+	if entry != nil && len(entry.KeyID) == 0 {
 		msg := "This selected default issuer has no key associated with it. Some operations like issuing certificates and signing CRLs will be unavailable with the requested default issuer until a key is imported or the default issuer is changed."
 		response.AddWarning(msg)
 		b.Logger().Error(msg)
@@ -28,8 +28,26 @@ type crlConfig struct {
 	EnableDelta                bool   `json:"enable_delta"`
 	DeltaRebuildInterval       string `json:"delta_rebuild_interval"`
 	AllowExpiredCertRevocation bool   `json:"allow_expired_cert_revocation"`
+	MaxRevocationTimeSkew      string `json:"max_revocation_time_skew"`
+	OcspMaxRequestBytes        int    `json:"ocsp_max_request_bytes"`
+	BuildBatchSize             int    `json:"build_batch_size"`
+	BuildParallelism           int    `json:"build_parallelism"`
+	OcspExpiredBehavior        string `json:"ocsp_expired_behavior"`
 }
 
+const (
+	defaultCrlBuildBatchSize   = 1000
+	defaultCrlBuildParallelism = 1
+)
+
+// Values of ocsp_expired_behavior, the OCSP status of expired certificates
+// which were never revoked.
+const (
+	ocspExpiredBehaviorGood    = "good"
+	ocspExpiredBehaviorUnknown = "unknown"
+	ocspExpiredBehaviorRevoked = "revoked-expired"
+)
+
 // Implicit default values for the config if it does not exist.
 var defaultCrlConfig = crlConfig{
 	Version:                    latestCrlConfigVersion,
@@ -42,6 +60,11 @@ var defaultCrlConfig = crlConfig{
 	EnableDelta:                false,
 	DeltaRebuildInterval:       "15m",
 	AllowExpiredCertRevocation: false,
+	MaxRevocationTimeSkew:      "0s",
+	OcspMaxRequestBytes:        defaultOcspMaxRequestBytes,
+	BuildBatchSize:             defaultCrlBuildBatchSize,
+	BuildParallelism:           defaultCrlBuildParallelism,
+	OcspExpiredBehavior:        ocspExpiredBehaviorGood,
 }
 
 func pathConfigCRL(b *backend) *framework.Path {
@@ -95,6 +118,47 @@ the NextUpdate field); defaults to 12 hours`,
 				Type:        framework.TypeBool,
 				Description: `If set to true, allows the revocation of expired certificates.`,
 			},
+			"max_revocation_time_skew": {
+				Type: framework.TypeString,
+				Description: `The maximum distance, in either direction, from the
+server's clock that a client-supplied revocation time may be; zero disables
+the check. Defaults to 0s.`,
+				Default: "0s",
+			},
+			"ocsp_max_request_bytes": {
+				Type: framework.TypeInt,
+				Description: `The maximum size, in bytes, of an OCSP request; larger
+requests receive a malformedRequest response. For GET requests this applies
+to the decoded request. Defaults to 65536.`,
+				Default: defaultOcspMaxRequestBytes,
+			},
+			"build_batch_size": {
+				Type: framework.TypeInt,
+				Description: `The number of revocation entries read from storage at
+a time while building CRLs. Defaults to 1000.`,
+				Default: defaultCrlBuildBatchSize,
+			},
+			"build_parallelism": {
+				Type: framework.TypeInt,
+				Description: `The number of workers loading and parsing revocation
+entries concurrently while building CRLs. Defaults to 1.`,
+				Default: defaultCrlBuildParallelism,
+			},
+			"ocsp_expired_behavior": {
+				Type: framework.TypeString,
+				Description: `How the OCSP responder answers for expired
+certificates which were never revoked: "good", "unknown" or
+"revoked-expired", answering revoked as of the certificate's expiration.
+Only applies to certificates stored by the mount. Defaults to "good".`,
+				Default:       ocspExpiredBehaviorGood,
+				AllowedValues: []interface{}{ocspExpiredBehaviorGood, ocspExpiredBehaviorUnknown, ocspExpiredBehaviorRevoked},
+			},
+			structuredWarningsParam: {
+				Type: framework.TypeBool,
+				Description: `If true, response warnings are additionally returned
+in the structured_warnings field as objects.`,
+				Default: false,
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -154,6 +218,37 @@ the NextUpdate field); defaults to 12 hours`,
 								Description: `If set to true, allows the revocation of expired certificates.`,
 								Required:    true,
 							},
+							"max_revocation_time_skew": {
+								Type: framework.TypeString,
+								Description: `The maximum distance, in either direction, from the
+server's clock that a client-supplied revocation time may be; zero disables
+the check. Defaults to 0s.`,
+								Required: true,
+							},
+							"ocsp_max_request_bytes": {
+								Type: framework.TypeInt,
+								Description: `The maximum size, in bytes, of an OCSP request; larger
+requests receive a malformedRequest response. For GET requests this applies
+to the decoded request. Defaults to 65536.`,
+								Required: true,
+							},
+							"build_batch_size": {
+								Type: framework.TypeInt,
+								Description: `The number of revocation entries read from storage at
+a time while building CRLs.`,
+								Required: true,
+							},
+							"build_parallelism": {
+								Type: framework.TypeInt,
+								Description: `The number of workers loading and parsing revocation
+entries concurrently while building CRLs.`,
+								Required: true,
+							},
+							"ocsp_expired_behavior": {
+								Type:        framework.TypeString,
+								Description: `How the OCSP responder answers for expired certificates which were never revoked`,
+								Required:    true,
+							},
 						},
 					}},
 				},
@@ -210,6 +305,41 @@ the NextUpdate field); defaults to 12 hours`,
 								Type:        framework.TypeBool,
 								Description: `If set to true, allows the revocation of expired certificates.`,
 							},
+							"max_revocation_time_skew": {
+								Type: framework.TypeString,
+								Description: `The maximum distance, in either direction, from the
+server's clock that a client-supplied revocation time may be; zero disables
+the check. Defaults to 0s.`,
+								Default: "0s",
+							},
+							"ocsp_max_request_bytes": {
+								Type: framework.TypeInt,
+								Description: `The maximum size, in bytes, of an OCSP request; larger
+requests receive a malformedRequest response. For GET requests this applies
+to the decoded request. Defaults to 65536.`,
+								Default: defaultOcspMaxRequestBytes,
+							},
+							"build_batch_size": {
+								Type: framework.TypeInt,
+								Description: `The number of revocation entries read from storage at
+a time while building CRLs. Defaults to 1000.`,
+								Default: defaultCrlBuildBatchSize,
+							},
+							"build_parallelism": {
+								Type: framework.TypeInt,
+								Description: `The number of workers loading and parsing revocation
+entries concurrently while building CRLs. Defaults to 1.`,
+								Default: defaultCrlBuildParallelism,
+							},
+							"ocsp_expired_behavior": {
+								Type:        framework.TypeString,
+								Description: `How the OCSP responder answers for expired certificates which were never revoked`,
+								Default:     ocspExpiredBehaviorGood,
+							},
+							structuredWarningsParam: {
+								Type:        framework.TypeSlice,
+								Description: `Warnings as objects with code, message and field keys, when requested`,
+							},
 						},
 					}},
 				},
@@ -301,6 +431,52 @@ func (b *backend) pathCRLWrite(ctx context.Context, req *logical.Request, d *fra
 		config.AllowExpiredCertRevocation = allowExpiredCertRevocationRaw.(bool)
 	}
 
+	if maxSkewRaw, ok := d.GetOk("max_revocation_time_skew"); ok {
+		maxSkew := maxSkewRaw.(string)
+		duration, err := parseutil.ParseDurationSecond(maxSkew)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("given max_revocation_time_skew could not be decoded: %s", err)), nil
+		}
+		if duration < 0 {
+			return logical.ErrorResponse(fmt.Sprintf("max_revocation_time_skew must be greater than or equal to 0 got: %s", duration)), nil
+		}
+		config.MaxRevocationTimeSkew = maxSkew
+	}
+
+	if maxRequestBytesRaw, ok := d.GetOk("ocsp_max_request_bytes"); ok {
+		maxRequestBytes := maxRequestBytesRaw.(int)
+		if maxRequestBytes <= 0 {
+			return logical.ErrorResponse(fmt.Sprintf("ocsp_max_request_bytes must be greater than 0 got: %d", maxRequestBytes)), nil
+		}
+		config.OcspMaxRequestBytes = maxRequestBytes
+	}
+
+	if batchSizeRaw, ok := d.GetOk("build_batch_size"); ok {
+		batchSize := batchSizeRaw.(int)
+		if batchSize <= 0 {
+			return logical.ErrorResponse(fmt.Sprintf("build_batch_size must be greater than 0 got: %d", batchSize)), nil
+		}
+		config.BuildBatchSize = batchSize
+	}
+
+	if parallelismRaw, ok := d.GetOk("build_parallelism"); ok {
+		parallelism := parallelismRaw.(int)
+		if parallelism <= 0 {
+			return logical.ErrorResponse(fmt.Sprintf("build_parallelism must be greater than 0 got: %d", parallelism)), nil
+		}
+		config.BuildParallelism = parallelism
+	}
+
+	if expiredBehaviorRaw, ok := d.GetOk("ocsp_expired_behavior"); ok {
+		expiredBehavior := expiredBehaviorRaw.(string)
+		switch expiredBehavior {
+		case ocspExpiredBehaviorGood, ocspExpiredBehaviorUnknown, ocspExpiredBehaviorRevoked:
+		default:
+			return logical.ErrorResponse(fmt.Sprintf("invalid ocsp_expired_behavior %q: must be %q, %q or %q", expiredBehavior, ocspExpiredBehaviorGood, ocspExpiredBehaviorUnknown, ocspExpiredBehaviorRevoked)), nil
+		}
+		config.OcspExpiredBehavior = expiredBehavior
+	}
+
 	expiry, _ := parseutil.ParseDurationSecond(config.Expiry)
 	if config.AutoRebuild {
 		gracePeriod, _ := parseutil.ParseDurationSecond(config.AutoRebuildGracePeriod)
@@ -353,11 +529,11 @@ func (b *backend) pathCRLWrite(ctx context.Context, req *logical.Request, d *fra
 			}
 		}
 		for index, warning := range warnings {
-			resp.AddWarning(fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning))
+			addCodedWarning(resp, newWarning(warningCodeCRLRebuild, "", fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning)))
 		}
 	}
 
-	return resp, nil
+	return addStructuredWarnings(resp, d), nil
 }
 
 func genResponseFromCrlConfig(config *crlConfig) *logical.Response {
@@ -372,6 +548,11 @@ func genResponseFromCrlConfig(config *crlConfig) *logical.Response {
 			"enable_delta":                  config.EnableDelta,
 			"delta_rebuild_interval":        config.DeltaRebuildInterval,
 			"allow_expired_cert_revocation": config.AllowExpiredCertRevocation,
+			"max_revocation_time_skew":      config.MaxRevocationTimeSkew,
+			"ocsp_max_request_bytes":        config.OcspMaxRequestBytes,
+			"build_batch_size":              config.BuildBatchSize,
+			"build_parallelism":             config.BuildParallelism,
+			"ocsp_expired_behavior":         config.OcspExpiredBehavior,
 		},
 	}
 }
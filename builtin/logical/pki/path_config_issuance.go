@@ -0,0 +1,196 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathConfigIssuance(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config/issuance",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"max_validated_sans": {
+				Type: framework.TypeInt,
+				Description: `Maximum number of Subject Alternative Names,
+including the common name when added to them, validated against a role for a
+single issuance request; requests exceeding it are rejected. Zero, the
+default, disables the limit.`,
+			},
+			"cabf_max_validity": {
+				Type: framework.TypeString,
+				Description: `Maximum validity of certificates issued under roles
+with enforce_cabf_ttl, as a duration such as "398d". Tracks the CA/Browser
+Forum Baseline Requirements; zero or an empty value selects the current
+baseline of 398 days.`,
+			},
+			"max_batch_fetch_serials": {
+				Type: framework.TypeInt,
+				Description: `Maximum number of serial numbers accepted by a
+single certs/batch fetch; larger batches are rejected. Zero, the default,
+selects a limit of 256.`,
+			},
+			"max_list_page_size": {
+				Type: framework.TypeInt,
+				Description: `Maximum number of entries returned by a single
+page of the certs, certs/detailed and certs/names listings; larger or
+unlimited requests are clamped to it, continuing with next_after. Zero, the
+default, disables the limit.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb:   "configure",
+					OperationSuffix: "issuance",
+				},
+				Callback: b.pathWriteIssuanceConfig,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields:      issuanceConfigResponseFields,
+					}},
+				},
+			},
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathReadIssuanceConfig,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "issuance-configuration",
+				},
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields:      issuanceConfigResponseFields,
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConfigIssuanceHelpSyn,
+		HelpDescription: pathConfigIssuanceHelpDesc,
+	}
+}
+
+var issuanceConfigResponseFields = map[string]*framework.FieldSchema{
+	"max_validated_sans": {
+		Type:        framework.TypeInt,
+		Description: `Maximum number of Subject Alternative Names validated per request`,
+		Required:    true,
+	},
+	"cabf_max_validity": {
+		Type:        framework.TypeString,
+		Description: `Maximum validity of certificates issued under roles with enforce_cabf_ttl`,
+		Required:    true,
+	},
+	"max_batch_fetch_serials": {
+		Type:        framework.TypeInt,
+		Description: `Maximum number of serial numbers accepted by a single certs/batch fetch`,
+		Required:    true,
+	},
+	"max_list_page_size": {
+		Type:        framework.TypeInt,
+		Description: `Maximum number of entries returned by a single page of a certificate listing`,
+		Required:    true,
+	},
+}
+
+func (b *backend) pathReadIssuanceConfig(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.getIssuanceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{Data: issuanceConfigResponseData(config)}, nil
+}
+
+func (b *backend) pathWriteIssuanceConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.getIssuanceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := data.GetOk("max_validated_sans"); ok {
+		config.MaxValidatedSANs = value.(int)
+		if config.MaxValidatedSANs < 0 {
+			return logical.ErrorResponse("max_validated_sans must not be negative"), nil
+		}
+	}
+
+	if value, ok := data.GetOk("cabf_max_validity"); ok {
+		var duration time.Duration
+		if raw := value.(string); len(raw) > 0 {
+			duration, err = parseutil.ParseDurationSecond(raw)
+			if err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("invalid cabf_max_validity: %v", err)), nil
+			}
+		}
+		if duration < 0 {
+			return logical.ErrorResponse("cabf_max_validity must not be negative"), nil
+		}
+		config.CABFMaxValidity = duration
+	}
+
+	if value, ok := data.GetOk("max_batch_fetch_serials"); ok {
+		config.MaxBatchFetchSerials = value.(int)
+		if config.MaxBatchFetchSerials < 0 {
+			return logical.ErrorResponse("max_batch_fetch_serials must not be negative"), nil
+		}
+	}
+
+	if value, ok := data.GetOk("max_list_page_size"); ok {
+		config.MaxListPageSize = value.(int)
+		if config.MaxListPageSize < 0 {
+			return logical.ErrorResponse("max_list_page_size must not be negative"), nil
+		}
+	}
+
+	if err := sc.writeIssuanceConfig(config); err != nil {
+		return nil, err
+	}
+
+	b.issuanceConfig.Store(config)
+
+	return &logical.Response{Data: issuanceConfigResponseData(config)}, nil
+}
+
+func issuanceConfigResponseData(config *issuanceConfigEntry) map[string]interface{} {
+	return map[string]interface{}{
+		"max_validated_sans":      config.MaxValidatedSANs,
+		"cabf_max_validity":       config.cabfMaxValidity().String(),
+		"max_batch_fetch_serials": config.maxBatchFetchSerials(),
+		"max_list_page_size":      config.MaxListPageSize,
+	}
+}
+
+const pathConfigIssuanceHelpSyn = `
+Configure mount-wide limits on the validation of issuance requests.
+`
+
+const pathConfigIssuanceHelpDesc = `
+This path caps the work performed validating a single issuance request against
+a role: the number of Subject Alternative Names validated. Requests exceeding
+it are rejected, protecting the mount against requests crafted to make name
+validation expensive. The limit is disabled by default.
+
+It also sets the maximum validity enforced on roles with enforce_cabf_ttl,
+which defaults to the CA/Browser Forum baseline of 398 days and may be
+updated as that baseline changes, and bounds the serial numbers fetched by a
+single certs/batch request, 256 by default, and the entries returned by a
+single page of the certificate listings, unlimited by default.
+`
@@ -60,6 +60,12 @@ and using '{{cluster_aia_path}}' requires /config/cluster's 'aia_path' member
 to be set on all PR secondary clusters.`,
 				Default: false,
 			},
+			structuredWarningsParam: {
+				Type: framework.TypeBool,
+				Description: `If true, response warnings are additionally returned
+in the structured_warnings field as objects.`,
+				Default: false,
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -103,6 +109,10 @@ URI validity until issuance time. This requires /config/cluster's path to be
 set on all PR Secondary clusters.`,
 								Default: false,
 							},
+							structuredWarningsParam: {
+								Type:        framework.TypeSlice,
+								Description: `Warnings as objects with code, message and field keys, when requested`,
+							},
 						},
 					}},
 				},
@@ -279,7 +289,7 @@ func (b *backend) pathWriteURL(ctx context.Context, req *logical.Request, data *
 
 			_, err = entries.toURLEntries(sc, issuer.ID)
 			if err != nil {
-				resp.AddWarning(fmt.Sprintf("issuance may fail: %v\n\nConsider setting the cluster-local address if it is not already set.", err))
+				addCodedWarning(resp, newWarning(warningCodeAIATemplateInvalid, "enable_templating", fmt.Sprintf("issuance may fail: %v\n\nConsider setting the cluster-local address if it is not already set.", err)))
 			}
 		}
 	} else if !entries.EnableTemplating {
@@ -308,7 +318,7 @@ func (b *backend) pathWriteURL(ctx context.Context, req *logical.Request, data *
 		return nil, err
 	}
 
-	return resp, nil
+	return addStructuredWarnings(resp, data), nil
 }
 
 const pathConfigURLsHelpSyn = `
@@ -0,0 +1,235 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/crypto/ocsp"
+)
+
+func pathCRLAudit(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl/audit`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "audit",
+			OperationSuffix: "crl",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathCRLAuditRead,
+				// Loading revocation entries may update their issuer
+				// association, so this request must reach the active node.
+				ForwardPerformanceStandby: true,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"crls": {
+								Type:        framework.TypeInt,
+								Description: `Number of CRLs compared`,
+								Required:    true,
+							},
+							"revoked_certs": {
+								Type:        framework.TypeInt,
+								Description: `Number of revoked certificates in storage`,
+								Required:    true,
+							},
+							"missing_from_crl": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers of revoked certificates absent from their issuer's CRL`,
+								Required:    true,
+							},
+							"not_revoked_in_crl": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers listed on a CRL without being revoked`,
+								Required:    true,
+							},
+							"drift_detected": {
+								Type:        framework.TypeBool,
+								Description: `Whether the CRLs differ from the live revocation state`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathCRLAuditHelpSyn,
+		HelpDescription: pathCRLAuditHelpDesc,
+	}
+}
+
+func (b *backend) pathCRLAuditRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("cannot audit CRLs until migration has completed"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := b.crlBuilder.getConfigWithUpdate(sc)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching CRL configuration: %w", err)
+	}
+
+	// Hold the builder lock so the CRLs aren't rebuilt mid-comparison.
+	b.crlBuilder._builder.Lock()
+	defer b.crlBuilder._builder.Unlock()
+
+	issuersConfig, err := sc.getIssuersConfig()
+	if err != nil {
+		return nil, err
+	}
+	internalCRLConfig, err := sc.getLocalCRLConfig()
+	if err != nil {
+		return nil, err
+	}
+	issuerIDCertMap, err := fetchIssuerMapForRevocationChecking(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The live revocation state: revoked certificates by the CRL expected
+	// to list them, along with revoked issuers, which appear on the CRLs of
+	// their parents rather than under revoked/. The audit must not modify
+	// the entries it inspects, so they're loaded read-only.
+	unassignedCerts, revokedCertsMap, err := getLocalRevokedCertEntries(sc, issuerIDCertMap, false, true, config)
+	if err != nil {
+		return nil, err
+	}
+
+	liveSerials := make(map[string]bool)
+	for _, entries := range revokedCertsMap {
+		for _, entry := range entries {
+			liveSerials[serialFromBigInt(entry.SerialNumber)] = true
+		}
+	}
+	for _, entry := range unassignedCerts {
+		liveSerials[serialFromBigInt(entry.SerialNumber)] = true
+	}
+	revokedCount := len(liveSerials)
+
+	for issuerId, cert := range issuerIDCertMap {
+		issuer, err := sc.fetchIssuerById(issuerId)
+		if err != nil {
+			return nil, err
+		}
+		if issuer.Revoked {
+			liveSerials[serialFromCert(cert)] = true
+		}
+	}
+
+	// The serials currently listed by each CRL: those on the complete CRL,
+	// plus those added by its delta CRL, less those the delta CRL removes.
+	crlSerials := make(map[crlID]map[string]bool)
+	for _, identifier := range internalCRLConfig.IssuerIDCRLMap {
+		if _, seen := crlSerials[identifier]; seen {
+			continue
+		}
+
+		serials := make(map[string]bool)
+		for _, suffix := range []string{"", deltaCRLPathSuffix} {
+			entry, err := sc.Storage.Get(ctx, "crls/"+identifier.String()+suffix)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil || len(entry.Value) == 0 {
+				continue
+			}
+
+			crl, err := x509.ParseRevocationList(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing stored CRL %v: %w", identifier, err)
+			}
+			for _, revoked := range crl.RevokedCertificateEntries {
+				serial := serialFromBigInt(revoked.SerialNumber)
+				serials[serial] = revoked.ReasonCode != ocsp.RemoveFromCRL
+			}
+		}
+		crlSerials[identifier] = serials
+	}
+
+	listedByCRL := func(identifier crlID, serial string) bool {
+		return crlSerials[identifier][serial]
+	}
+
+	resp := &logical.Response{}
+	missing := []string{}
+	if config.Disable {
+		resp.AddWarning("CRL building is disabled on this mount, so its CRLs are intentionally empty; revoked certificates were not checked against them.")
+	} else {
+		for issuerId, entries := range revokedCertsMap {
+			identifier, ok := internalCRLConfig.IssuerIDCRLMap[issuerId]
+			for _, entry := range entries {
+				serial := serialFromBigInt(entry.SerialNumber)
+				if !ok || !listedByCRL(identifier, serial) {
+					missing = append(missing, serial)
+				}
+			}
+		}
+
+		// Certificates whose issuer isn't known to the mount are listed on
+		// the default issuer's CRL.
+		defaultCRL, haveDefault := internalCRLConfig.IssuerIDCRLMap[issuersConfig.DefaultIssuerId]
+		for _, entry := range unassignedCerts {
+			serial := serialFromBigInt(entry.SerialNumber)
+			if !haveDefault || !listedByCRL(defaultCRL, serial) {
+				missing = append(missing, serial)
+			}
+		}
+	}
+
+	extra := []string{}
+	seenExtra := make(map[string]bool)
+	for _, serials := range crlSerials {
+		for serial, listed := range serials {
+			if listed && !liveSerials[serial] && !seenExtra[serial] {
+				seenExtra[serial] = true
+				extra = append(extra, serial)
+			}
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	drift := len(missing) > 0 || len(extra) > 0
+	if drift {
+		resp.AddWarning("The stored CRLs differ from the live revocation state; rebuild them with crl/rotate.")
+		if config.AutoRebuild {
+			resp.AddWarning("CRLs on this mount are rebuilt automatically, so recent revocations may not be reflected on them until the next scheduled rebuild.")
+		}
+	}
+
+	resp.Data = map[string]interface{}{
+		"crls":               len(crlSerials),
+		"revoked_certs":      revokedCount,
+		"missing_from_crl":   missing,
+		"not_revoked_in_crl": extra,
+		"drift_detected":     drift,
+	}
+	return resp, nil
+}
+
+const pathCRLAuditHelpSyn = `
+Compare the stored CRLs against the live revocation state.
+`
+
+const pathCRLAuditHelpDesc = `
+This endpoint compares the serial numbers listed on the mount's current CRLs,
+complete and delta, against the revoked certificates held in storage. It
+reports revoked certificates missing from their issuer's CRL and serial numbers
+listed on a CRL despite not being revoked, which may indicate a skipped
+rebuild or storage modified out-of-band. When such drift is detected, rebuild
+the CRLs with crl/rotate.
+`
@@ -4,17 +4,28 @@
 package pki
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
 	"github.com/openbao/openbao/sdk/v2/helper/errutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	"golang.org/x/crypto/ed25519"
@@ -41,7 +52,7 @@ var pathFetchReadSchema = map[int][]framework.Response{
 			},
 			"issuer_id": {
 				Type:        framework.TypeString,
-				Description: `ID of the issuer`,
+				Description: `ID of the issuer which signed the certificate, when known`,
 				Required:    false,
 			},
 			"ca_chain": {
@@ -49,6 +60,101 @@ var pathFetchReadSchema = map[int][]framework.Response{
 				Description: `Issuing CA Chain`,
 				Required:    false,
 			},
+			"extensions": {
+				Type:        framework.TypeSlice,
+				Description: `Parsed certificate extensions, if requested`,
+				Required:    false,
+			},
+			"ocsp_servers": {
+				Type:        framework.TypeStringSlice,
+				Description: `OCSP responder URLs from the certificate's AIA extension, if requested`,
+				Required:    false,
+			},
+			"issuing_certificates": {
+				Type:        framework.TypeStringSlice,
+				Description: `Issuing certificate URLs from the certificate's AIA extension, if requested`,
+				Required:    false,
+			},
+			"crl_distribution_points": {
+				Type:        framework.TypeStringSlice,
+				Description: `CRL distribution points from the certificate, if requested`,
+				Required:    false,
+			},
+			"found": {
+				Type:        framework.TypeBool,
+				Description: `Whether a certificate with the serial number exists, when missing_behavior=empty-ok`,
+				Required:    false,
+			},
+			"revocation_reason": {
+				Type:        framework.TypeInt,
+				Description: `RFC 5280 reason code of the revocation, when recorded`,
+				Required:    false,
+			},
+			"revocation_reason_string": {
+				Type:        framework.TypeString,
+				Description: `Name of the revocation reason, such as keyCompromise`,
+				Required:    false,
+			},
+			"jwk": {
+				Type:        framework.TypeMap,
+				Description: `The certificate's public key as a JWK, with its chain in x5c, when format=jwk`,
+				Required:    false,
+			},
+			"certificate_pem": {
+				Type:        framework.TypeString,
+				Description: `PEM-encoded certificate, on cert/:serial/both`,
+				Required:    false,
+			},
+			"certificate_der_base64": {
+				Type:        framework.TypeString,
+				Description: `Base64-encoded DER certificate, on cert/:serial/both`,
+				Required:    false,
+			},
+			"crl": {
+				Type:        framework.TypeString,
+				Description: `Base64-encoded DER CRL, when format=base64`,
+				Required:    false,
+			},
+			"serial_number": {
+				Type:        framework.TypeString,
+				Description: `Serial number of the certificate, in colon-separated hex`,
+				Required:    false,
+			},
+			"serial_number_decimal": {
+				Type:        framework.TypeString,
+				Description: `Serial number of the certificate, as a decimal integer`,
+				Required:    false,
+			},
+			"signature_algorithm": {
+				Type:        framework.TypeString,
+				Description: `Algorithm the certificate was signed with, such as SHA256-RSA`,
+				Required:    false,
+			},
+			"is_ca": {
+				Type:        framework.TypeBool,
+				Description: `Whether the certificate is a CA certificate`,
+				Required:    false,
+			},
+			"max_path_len": {
+				Type:        framework.TypeInt,
+				Description: `Path length constraint of the certificate, or -1 when it has none`,
+				Required:    false,
+			},
+			"validity_state": {
+				Type:        framework.TypeString,
+				Description: `Whether the certificate is not_yet_valid, valid or expired at the time of the request`,
+				Required:    false,
+			},
+			"issuer_present": {
+				Type:        framework.TypeBool,
+				Description: `Whether the issuer which signed the certificate still exists on the mount`,
+				Required:    false,
+			},
+			structuredWarningsParam: {
+				Type:        framework.TypeSlice,
+				Description: `Response warnings as objects with code and message keys, when requested`,
+				Required:    false,
+			},
 		},
 	}},
 }
@@ -75,6 +181,223 @@ func pathFetchCA(b *backend) *framework.Path {
 	}
 }
 
+// Returns identifying details of the CA certificate, without the certificate
+func pathFetchCAInfo(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `ca/info`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "ca-info",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCAInfoRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"serial_number": {
+								Type:        framework.TypeString,
+								Description: `Serial number of the CA certificate`,
+								Required:    true,
+							},
+							"subject": {
+								Type:        framework.TypeString,
+								Description: `Subject of the CA certificate`,
+								Required:    true,
+							},
+							"subject_key_id": {
+								Type:        framework.TypeString,
+								Description: `Subject key identifier of the CA certificate, in colon-separated hex`,
+								Required:    true,
+							},
+							"authority_key_id": {
+								Type:        framework.TypeString,
+								Description: `Authority key identifier of the CA certificate, in colon-separated hex`,
+								Required:    true,
+							},
+							"sha256_fingerprint": {
+								Type:        framework.TypeString,
+								Description: `SHA-256 digest of the CA certificate's DER encoding, in colon-separated hex`,
+								Required:    true,
+							},
+							"not_before": {
+								Type:        framework.TypeTime,
+								Description: `Start of the CA certificate's validity period`,
+								Required:    true,
+							},
+							"not_after": {
+								Type:        framework.TypeTime,
+								Description: `End of the CA certificate's validity period`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCAInfoHelpSyn,
+		HelpDescription: pathFetchCAInfoHelpDesc,
+	}
+}
+
+// fetchDefaultCAInfo loads the CA bundle served by the default issuer's
+// fetch endpoints, along with the reference it was resolved through. When
+// no CA can be served, the returned error is a UserError describing why.
+func (sc *storageContext) fetchDefaultCAInfo() (*certutil.CAInfoBundle, string, error) {
+	caIssuerRef, err := fetchIssuerRef(sc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	caInfo, err := sc.fetchCAInfo(caIssuerRef, ReadOnlyUsage)
+	if err != nil {
+		noCAMessage, describeErr := describeMissingCA(sc)
+		if describeErr != nil {
+			return nil, "", describeErr
+		}
+		if len(noCAMessage) > 0 {
+			return nil, "", errutil.UserError{Err: noCAMessage}
+		}
+		return nil, "", err
+	}
+
+	return caInfo, caIssuerRef, nil
+}
+
+func (b *backend) pathFetchCAInfoRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	caInfo, caIssuerRef, err := sc.fetchDefaultCAInfo()
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+
+	resp := &logical.Response{}
+	if caIssuerRef != defaultRef {
+		resp.AddWarning("no default issuer is set; serving the fallback issuer configured via config/issuers")
+	}
+	if _, err := checkExpiredCA(sc, caInfo.Certificate, true, resp); err != nil {
+		return nil, err
+	}
+
+	cert := caInfo.Certificate
+	fingerprint := sha256.Sum256(cert.Raw)
+	resp.Data = map[string]interface{}{
+		"serial_number":      serialFromCert(cert),
+		"subject":            cert.Subject.String(),
+		"subject_key_id":     certutil.GetHexFormatted(cert.SubjectKeyId, ":"),
+		"authority_key_id":   certutil.GetHexFormatted(cert.AuthorityKeyId, ":"),
+		"sha256_fingerprint": certutil.GetHexFormatted(fingerprint[:], ":"),
+		"not_before":         cert.NotBefore,
+		"not_after":          cert.NotAfter,
+	}
+	return resp, nil
+}
+
+// Returns details of the CA's key, without any private key material
+func pathFetchCAKeyInfo(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `ca/key-info`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "ca-key-info",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCAKeyInfoRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"key_type": {
+								Type:        framework.TypeString,
+								Description: `Type of the CA's key: rsa, ec or ed25519`,
+								Required:    true,
+							},
+							"key_bits": {
+								Type:        framework.TypeInt,
+								Description: `Size of the CA's key in bits`,
+								Required:    true,
+							},
+							"public_key_sha256": {
+								Type:        framework.TypeString,
+								Description: `SHA-256 digest of the CA certificate's DER-encoded SubjectPublicKeyInfo, in colon-separated hex`,
+								Required:    true,
+							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `ID of the issuer served as the CA`,
+								Required:    false,
+							},
+							"key_id": {
+								Type:        framework.TypeString,
+								Description: `ID of the issuer's key`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCAKeyInfoHelpSyn,
+		HelpDescription: pathFetchCAKeyInfoHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCAKeyInfoRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	caInfo, caIssuerRef, err := sc.fetchDefaultCAInfo()
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+
+	resp := &logical.Response{}
+	if caIssuerRef != defaultRef {
+		resp.AddWarning("no default issuer is set; serving the fallback issuer configured via config/issuers")
+	}
+
+	// Only the public half of the key is described, from the certificate.
+	cert := caInfo.Certificate
+	keyType, keyBits := certKeyTypeAndBits(cert)
+	fingerprint := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	resp.Data = map[string]interface{}{
+		"key_type":          keyType,
+		"key_bits":          keyBits,
+		"public_key_sha256": certutil.GetHexFormatted(fingerprint[:], ":"),
+	}
+
+	// Before migration, the legacy bundle has no issuer or key IDs.
+	if !b.useLegacyBundleCaStorage() {
+		issuerId, err := sc.resolveIssuerReference(caIssuerRef)
+		if err != nil {
+			return nil, err
+		}
+		issuer, err := sc.fetchIssuerById(issuerId)
+		if err != nil {
+			return nil, err
+		}
+		resp.Data["issuer_id"] = issuer.ID.String()
+		resp.Data["key_id"] = issuer.KeyID.String()
+	}
+	return resp, nil
+}
+
 // Returns the CA chain
 func pathFetchCAChain(b *backend) *framework.Path {
 	return &framework.Path{
@@ -97,14 +420,14 @@ func pathFetchCAChain(b *backend) *framework.Path {
 	}
 }
 
-// Returns the CRL in raw format
-func pathFetchCRL(b *backend) *framework.Path {
+// Returns the CA chain as a PKCS#7 certificates-only bundle
+func pathFetchCAChainP7B(b *backend) *framework.Path {
 	return &framework.Path{
-		Pattern: `crl(/pem|/delta(/pem)?)?`,
+		Pattern: `ca_chain/p7b(/pem)?`,
 
 		DisplayAttrs: &framework.DisplayAttributes{
 			OperationPrefix: operationPrefixPKI,
-			OperationSuffix: "crl-der|crl-pem|crl-delta|crl-delta-pem",
+			OperationSuffix: "ca-chain-p7b|ca-chain-p7b-pem",
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -119,21 +442,142 @@ func pathFetchCRL(b *backend) *framework.Path {
 	}
 }
 
-// Returns any valid (non-revoked) cert in raw format.
-func pathFetchValidRaw(b *backend) *framework.Path {
+// Returns the CA chain as a list of PEM certificates
+func pathFetchCAChainJSON(b *backend) *framework.Path {
 	return &framework.Path{
-		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/raw(/pem)?`,
+		Pattern: `ca_chain/json`,
 
 		DisplayAttrs: &framework.DisplayAttributes{
 			OperationPrefix: operationPrefixPKI,
-			OperationSuffix: "cert-raw-der|cert-raw-pem",
+			OperationSuffix: "ca-chain-json",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `The default issuer's certificate`,
+								Required:    true,
+							},
+							"ca_chain": {
+								Type:        framework.TypeStringSlice,
+								Description: `The default issuer's CA chain, one PEM certificate per entry, starting with the issuer`,
+								Required:    true,
+							},
+							"revocation_time": {
+								Type:        framework.TypeInt64,
+								Description: `Revocation time`,
+								Required:    false,
+							},
+							"revocation_time_rfc3339": {
+								Type:        framework.TypeString,
+								Description: `Revocation time RFC 3339 formatted`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Verifies that the CA chain links together
+func pathFetchCAChainVerify(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `ca_chain/verify`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "verify",
+			OperationSuffix: "ca-chain",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCAChainVerifyRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"valid": {
+								Type:        framework.TypeBool,
+								Description: `Whether each certificate of the chain is signed by the one following it`,
+								Required:    true,
+							},
+							"problems": {
+								Type:        framework.TypeStringSlice,
+								Description: `Descriptions of the broken links and ordering issues found`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCAChainVerifyHelpSyn,
+		HelpDescription: pathFetchCAChainVerifyHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCAChainVerifyRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	caInfo, caIssuerRef, err := sc.fetchDefaultCAInfo()
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+
+	resp := &logical.Response{}
+	if caIssuerRef != defaultRef {
+		resp.AddWarning("no default issuer is set; verifying the fallback issuer configured via config/issuers")
+	}
+
+	var chain []*x509.Certificate
+	for _, block := range caInfo.GetFullChain() {
+		chain = append(chain, block.Certificate)
+	}
+
+	problems := verifyCAChainLinks(chain)
+	resp.Data = map[string]interface{}{
+		"valid":    len(problems) == 0,
+		"problems": problems,
+	}
+	return resp, nil
+}
+
+// Returns the CRL in raw format
+func pathFetchCRL(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl(/pem|/delta(/pem)?|/pkcs7(/pem)?)?`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "crl-der|crl-pem|crl-delta|crl-delta-pem|crl-pkcs7|crl-pkcs7-pem",
 		},
 
 		Fields: map[string]*framework.FieldSchema{
-			"serial": {
+			"format": {
 				Type: framework.TypeString,
-				Description: `Certificate serial number, in colon- or
-hyphen-separated octal`,
+				Description: `Encoding of the CRL on crl and crl/delta: "der"
+(the default) returns the raw DER CRL, while "base64" returns a JSON
+response whose crl field holds the base64-encoded DER CRL.`,
+				Default:       fetchFormatDER,
+				AllowedValues: []interface{}{fetchFormatDER, fetchFormatBase64},
+				Query:         true,
 			},
 		},
 
@@ -144,97 +588,2918 @@ hyphen-separated octal`,
 			},
 		},
 
-		HelpSynopsis:    pathFetchHelpSyn,
-		HelpDescription: pathFetchHelpDesc,
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Returns the CRL merged with its delta CRL
+func pathFetchCombinedCRL(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl/combined(/pem)?`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "crl-combined|crl-combined-pem",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchRead,
+				Responses: pathFetchReadSchema,
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Returns the header fields of the CRL or delta CRL
+func pathFetchCRLMetadata(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl(/delta)?/metadata`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "crl-metadata|crl-delta-metadata",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCRLMetadataRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"crl_number": {
+								Type:        framework.TypeInt64,
+								Description: `Number of the CRL`,
+								Required:    true,
+							},
+							"this_update": {
+								Type:        framework.TypeTime,
+								Description: `Time the CRL was issued`,
+								Required:    true,
+							},
+							"next_update": {
+								Type:        framework.TypeTime,
+								Description: `Time by which the next CRL will be issued`,
+								Required:    true,
+							},
+							"revoked_count": {
+								Type:        framework.TypeInt,
+								Description: `Number of entries on the CRL`,
+								Required:    true,
+							},
+							"is_delta": {
+								Type:        framework.TypeBool,
+								Description: `Whether the CRL is a delta CRL`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCRLMetadataHelpSyn,
+		HelpDescription: pathFetchCRLMetadataHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCRLMetadataRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	isDelta := strings.HasPrefix(req.Path, "crl/delta")
+	serial := legacyCRLPath
+	if isDelta {
+		serial = deltaCRLPath
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	crlEntry, err := fetchCertBySerial(sc, "crl", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if crlEntry == nil || len(crlEntry.Value) == 0 {
+		return nil, nil
+	}
+
+	crl, err := x509.ParseRevocationList(crlEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stored CRL: %w", err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"crl_number":    crl.Number.Int64(),
+			"this_update":   crl.ThisUpdate,
+			"next_update":   crl.NextUpdate,
+			"revoked_count": len(crl.RevokedCertificateEntries),
+			"is_delta":      isDelta,
+		},
+	}, nil
+}
+
+// Returns the entries of the CRL or delta CRL as JSON
+func pathFetchCRLJSON(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl(/delta)?/json`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "crl-json|crl-delta-json",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional serial number to list revoked entries after, not required to exist.`,
+				Query:       true,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of revoked entries to return; defaults to all entries.`,
+				Query:       true,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+				Query:   true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCRLJSONRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"crl_number": {
+								Type:        framework.TypeInt64,
+								Description: `Number of the CRL`,
+								Required:    true,
+							},
+							"this_update": {
+								Type:        framework.TypeTime,
+								Description: `Time the CRL was issued`,
+								Required:    true,
+							},
+							"next_update": {
+								Type:        framework.TypeTime,
+								Description: `Time by which the next CRL will be issued`,
+								Required:    true,
+							},
+							"revoked": {
+								Type:        framework.TypeSlice,
+								Description: `Entries of the CRL in serial number order, each with its serial, revocation_time and reason`,
+								Required:    true,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the entries are exhausted`,
+								Required:    true,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCRLJSONHelpSyn,
+		HelpDescription: pathFetchCRLJSONHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCRLJSONRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+	limit = b.getIssuanceConfig().listPageLimit(limit)
+
+	serial := legacyCRLPath
+	if strings.HasPrefix(req.Path, "crl/delta") {
+		serial = deltaCRLPath
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	crlEntry, err := fetchCertBySerial(sc, "crl", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if crlEntry == nil || len(crlEntry.Value) == 0 {
+		return nil, nil
+	}
+
+	crl, err := x509.ParseRevocationList(crlEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stored CRL: %w", err)
+	}
+
+	// Page through the entries in the order certificates are listed from
+	// storage, so that after takes the same cursors as other listings.
+	entries := crl.RevokedCertificateEntries
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = normalizeSerial(serialFromBigInt(entry.SerialNumber))
+	}
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+
+	revoked := []map[string]interface{}{}
+	var nextCursor string
+	for _, index := range order {
+		if keys[index] <= after {
+			continue
+		}
+		if limit > 0 && len(revoked) == limit {
+			nextCursor = revoked[len(revoked)-1]["serial"].(string)
+			break
+		}
+
+		entry := entries[index]
+		revoked = append(revoked, map[string]interface{}{
+			"serial":          serialFromBigInt(entry.SerialNumber),
+			"revocation_time": entry.RevocationTime,
+			"reason":          revocationReasonNames[entry.ReasonCode],
+		})
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"crl_number":  crl.Number.Int64(),
+			"this_update": crl.ThisUpdate,
+			"next_update": crl.NextUpdate,
+			"revoked":     revoked,
+			"next_after":  nextCursor,
+			"has_more":    nextCursor != "",
+		},
+	}
+	if data.Get("include_next_cursor").(bool) {
+		resp.Data["next_cursor"] = nextCursor
+	}
+	return resp, nil
+}
+
+// Returns the CRL or delta CRL along with the issuer which signed it
+func pathFetchCRLWithIssuer(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `crl(/delta)?/with-issuer`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "crl-with-issuer|crl-delta-with-issuer",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCRLWithIssuerRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"crl": {
+								Type:        framework.TypeString,
+								Description: `Base64-encoded DER of the CRL`,
+								Required:    true,
+							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `ID of the issuer which signed the CRL`,
+								Required:    true,
+							},
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `Certificate of the issuer which signed the CRL`,
+								Required:    true,
+							},
+							"ca_chain": {
+								Type:        framework.TypeStringSlice,
+								Description: `CA chain of the issuer which signed the CRL, starting with its certificate`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCRLWithIssuerHelpSyn,
+		HelpDescription: pathFetchCRLWithIssuerHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCRLWithIssuerRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("cannot fetch the CRL with its issuer until migration has completed"), nil
+	}
+
+	serial := legacyCRLPath
+	if strings.HasPrefix(req.Path, "crl/delta") {
+		serial = deltaCRLPath
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	crlEntry, err := fetchCertBySerial(sc, "crl", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if crlEntry == nil || len(crlEntry.Value) == 0 {
+		return nil, nil
+	}
+
+	issuer, err := sc.fetchDefaultCRLSigner()
+	if err != nil {
+		return nil, err
+	}
+	if issuer == nil {
+		return logical.ErrorResponse("no issuer of this mount may sign the current CRL; it may be rebuilt with crl/rotate"), nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"crl":         base64.StdEncoding.EncodeToString(crlEntry.Value),
+			"issuer_id":   issuer.ID.String(),
+			"certificate": issuer.Certificate,
+			"ca_chain":    issuer.CAChain,
+		},
+	}, nil
+}
+
+// fetchDefaultCRLSigner returns the issuer which signs the default issuer's
+// CRL, chosen from the cluster-local CRL configuration as buildCRLs does:
+// the default issuer itself when it may sign CRLs, else the first issuer
+// sharing its CRL which may. It is nil when the default issuer has no CRL.
+func (sc *storageContext) fetchDefaultCRLSigner() (*issuerEntry, error) {
+	issuersConfig, err := sc.getIssuersConfig()
+	if err != nil {
+		return nil, err
+	}
+	defaultId := issuersConfig.DefaultIssuerId
+	if len(defaultId) == 0 {
+		return nil, nil
+	}
+
+	crlConfig, err := sc.getLocalCRLConfig()
+	if err != nil {
+		return nil, err
+	}
+	crlId, ok := crlConfig.IssuerIDCRLMap[defaultId]
+	if !ok {
+		return nil, nil
+	}
+
+	issuers, err := sc.listIssuers()
+	if err != nil {
+		return nil, err
+	}
+	candidates := []issuerID{defaultId}
+	for _, issuerId := range issuers {
+		if issuerId != defaultId && crlConfig.IssuerIDCRLMap[issuerId] == crlId {
+			candidates = append(candidates, issuerId)
+		}
+	}
+
+	for _, issuerId := range candidates {
+		issuer, err := sc.fetchIssuerById(issuerId)
+		if err != nil {
+			return nil, err
+		}
+		if issuer.EnsureUsage(CRLSigningUsage) == nil {
+			return issuer, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns any valid (non-revoked) cert in raw format.
+func pathFetchValidRaw(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/raw(/pem)?`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-raw-der|cert-raw-pem",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchRead,
+				Responses: pathFetchReadSchema,
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Returns any valid (non-revoked) cert in both PEM and DER format.
+func pathFetchValidBoth(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/both`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-both",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal, or as contiguous hex`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchRead,
+				Responses: pathFetchReadSchema,
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Returns a cert followed by the CA chain of its issuer, as a raw PEM bundle.
+func pathFetchCertFullChain(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/fullchain/pem`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-full-chain-pem",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertFullChainRead,
+			},
+		},
+
+		HelpSynopsis:    pathFetchCertFullChainHelpSyn,
+		HelpDescription: pathFetchCertFullChainHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertFullChainRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	serial := splitContiguousSerial(data.Get("serial").(string))
+
+	certEntry, err := fetchCertBySerial(sc, "certs/", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if certEntry == nil {
+		return nil, nil
+	}
+
+	cert, err := x509.ParseCertificate(certEntry.Value)
+	if err != nil {
+		return logical.ErrorResponse(fmt.Sprintf("failed to parse certificate for %s: %s", serial, err)), nil
+	}
+
+	issuerId, err := sc.resolveCertIssuer(serial)
+	if err != nil {
+		return nil, err
+	}
+	if issuerId == "" {
+		return logical.ErrorResponse(fmt.Sprintf("the issuer of certificate %s is unknown or no longer exists on this mount", serial)), nil
+	}
+
+	caInfo, err := sc.fetchCAInfoByIssuerId(issuerId, ReadOnlyUsage)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+
+	bundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+	for _, ca := range caInfo.GetFullChain() {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: ca.Bytes,
+		})...)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPContentType: "application/pem-certificate-chain",
+			logical.HTTPStatusCode:  http.StatusOK,
+			logical.HTTPRawBody:     bundle,
+		},
+	}, nil
+}
+
+// resolveCertIssuer returns the ID of the issuer which signed the
+// certificate, as recorded on its revocation entry or, failing that, at
+// issuance. It is empty when no issuer was recorded or the recorded one has
+// since been deleted.
+func (sc *storageContext) resolveCertIssuer(serial string) (issuerID, error) {
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return legacyBundleShimID, nil
+	}
+
+	revokedEntry, err := fetchCertBySerial(sc, "revoked/", serial)
+	if err != nil {
+		return "", err
+	}
+	if revokedEntry != nil {
+		var revInfo revocationInfo
+		if err := revokedEntry.DecodeJSON(&revInfo); err != nil {
+			return "", fmt.Errorf("error decoding revocation entry for serial %s: %w", serial, err)
+		}
+		if len(revInfo.CertificateIssuer) > 0 {
+			entry, err := sc.Storage.Get(sc.Context, issuerPrefix+revInfo.CertificateIssuer.String())
+			if err != nil {
+				return "", err
+			}
+			if entry != nil {
+				return revInfo.CertificateIssuer, nil
+			}
+		}
+	}
+
+	_, issuer, err := sc.fetchRecordedCertIssuer(serial)
+	if err != nil || issuer == nil {
+		return "", err
+	}
+	return issuer.ID, nil
+}
+
+// Returns any valid (non-revoked) cert. Since "ca" fits the pattern, this path
+// also handles returning the CA cert in a non-raw format.
+func pathFetchValid(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal, or as contiguous hex`,
+			},
+			"include_extensions": {
+				Type: framework.TypeBool,
+				Description: `Whether to include the certificate's parsed
+extensions in the response. Defaults to false.`,
+				Query: true,
+			},
+			"include_urls": {
+				Type: framework.TypeBool,
+				Description: `Whether to include the OCSP responder, issuing
+certificate and CRL distribution point URLs embedded in the certificate,
+as ocsp_servers, issuing_certificates and crl_distribution_points.
+Defaults to false.`,
+				Query: true,
+			},
+			"missing_behavior": {
+				Type: framework.TypeString,
+				Description: `Behavior when no certificate with the serial
+number exists: "notfound" (the default) returns a 404 with an error,
+while "empty-ok" returns a 200 with found=false.`,
+				Default:       "notfound",
+				AllowedValues: []interface{}{"notfound", "empty-ok"},
+				Query:         true,
+			},
+			"format": {
+				Type: framework.TypeString,
+				Description: `Additional encoding of the certificate: "pem"
+(the default) returns only the PEM certificate, while "jwk" also returns
+its public key as a JWK with the certificate chain in x5c.`,
+				Default:       fetchFormatPEM,
+				AllowedValues: []interface{}{fetchFormatPEM, fetchFormatJWK},
+				Query:         true,
+			},
+			structuredWarningsParam: {
+				Type:    framework.TypeBool,
+				Default: false,
+				Description: `If true, response warnings, such as those about
+deprecated signature algorithms or weak keys, are additionally returned in the
+structured_warnings field as objects with code and message keys.`,
+				Query: true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchRead,
+				Responses: pathFetchReadSchema,
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// Returns the private key escrowed for a certificate. Unlike the other
+// cert/ paths, this one requires authentication.
+func pathFetchEscrowedKey(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/private-key`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-escrowed-private-key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchEscrowedKeyRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"serial_number": {
+								Type:        framework.TypeString,
+								Description: `Serial number of the certificate`,
+								Required:    true,
+							},
+							"private_key": {
+								Type:        framework.TypeString,
+								Description: `The escrowed private key, PEM encoded`,
+								Required:    true,
+							},
+							"private_key_type": {
+								Type:        framework.TypeString,
+								Description: `The type of the private key`,
+								Required:    true,
+							},
+							"expiration": {
+								Type:        framework.TypeInt64,
+								Description: `Expiration of the certificate, as a Unix timestamp`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchEscrowedKeyHelpSyn,
+		HelpDescription: pathFetchEscrowedKeyHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchEscrowedKeyRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serial := splitContiguousSerial(data.Get("serial").(string))
+	if len(serial) == 0 {
+		return logical.ErrorResponse("the serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	entry, err := sc.fetchEscrowedKey(serial)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching escrowed key: %w", err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	// Every retrieval is worth surfacing beyond the audit log, which only
+	// records an HMAC of the returned key.
+	b.Logger().Warn("escrowed private key retrieved", "serial_number", entry.SerialNumber, "display_name", req.DisplayName, "entity_id", req.EntityID)
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"serial_number":    entry.SerialNumber,
+			"private_key":      entry.PrivateKey,
+			"private_key_type": string(entry.PrivateKeyType),
+			"expiration":       entry.NotAfter.Unix(),
+		},
+	}
+	resp.AddWarning("this response contains an escrowed private key; handle it as sensitive material")
+	return resp, nil
+}
+
+// Returns only the revocation status of a certificate
+func pathFetchRevocationStatus(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/revoked`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-revocation-status",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal, or as contiguous hex`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchRevocationStatusRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"revoked": {
+								Type:        framework.TypeBool,
+								Description: `Whether the certificate is revoked or on hold`,
+								Required:    true,
+							},
+							"revocation_time_rfc3339": {
+								Type:        framework.TypeString,
+								Description: `Revocation time RFC 3339 formatted`,
+								Required:    false,
+							},
+							"revocation_reason": {
+								Type:        framework.TypeInt,
+								Description: `RFC 5280 reason code of the revocation, when recorded`,
+								Required:    false,
+							},
+							"revocation_reason_string": {
+								Type:        framework.TypeString,
+								Description: `Name of the revocation reason, such as keyCompromise`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchRevocationStatusHelpSyn,
+		HelpDescription: pathFetchRevocationStatusHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchRevocationStatusRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serial := splitContiguousSerial(data.Get("serial").(string))
+	if len(serial) == 0 {
+		return logical.ErrorResponse("the serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	revokedEntry, err := fetchCertBySerial(sc, "revoked/", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if revokedEntry == nil {
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"revoked": false,
+			},
+		}, nil
+	}
+
+	var revInfo revocationInfo
+	if err := revokedEntry.DecodeJSON(&revInfo); err != nil {
+		return nil, fmt.Errorf("error decoding revocation entry for serial %s: %w", serial, err)
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{},
+	}
+	addRevocationStatus(resp.Data, &revInfo)
+	return resp, nil
+}
+
+// Returns a certificate along with whether it still verifies against the
+// mount's issuers
+func pathFetchCertStatus(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/status`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-status",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal, or as contiguous hex`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertStatusRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `Certificate`,
+								Required:    true,
+							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `ID of the issuer of the mount which signed the certificate, if any`,
+								Required:    false,
+							},
+							"chain_valid": {
+								Type:        framework.TypeBool,
+								Description: `Whether the certificate verifies against the CA chain of its issuer`,
+								Required:    true,
+							},
+							"revoked": {
+								Type:        framework.TypeBool,
+								Description: `Whether the certificate is revoked or on hold`,
+								Required:    true,
+							},
+							"expired": {
+								Type:        framework.TypeBool,
+								Description: `Whether the certificate has expired`,
+								Required:    true,
+							},
+							"problems": {
+								Type:        framework.TypeStringSlice,
+								Description: `Descriptions of the reasons the certificate is not valid`,
+								Required:    true,
+							},
+							"revocation_time_rfc3339": {
+								Type:        framework.TypeString,
+								Description: `Revocation time RFC 3339 formatted`,
+								Required:    false,
+							},
+							"revocation_reason": {
+								Type:        framework.TypeInt,
+								Description: `RFC 5280 reason code of the revocation, when recorded`,
+								Required:    false,
+							},
+							"revocation_reason_string": {
+								Type:        framework.TypeString,
+								Description: `Name of the revocation reason, such as keyCompromise`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCertStatusHelpSyn,
+		HelpDescription: pathFetchCertStatusHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertStatusRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serial := splitContiguousSerial(data.Get("serial").(string))
+	if len(serial) == 0 {
+		return logical.ErrorResponse("the serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	certEntry, err := fetchCertBySerial(sc, "certs/", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if certEntry == nil {
+		return certNotFoundResponse(req, serial)
+	}
+
+	cert, err := x509.ParseCertificate(certEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate %s: %w", serial, err)
+	}
+
+	now := time.Now()
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"certificate": strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: cert.Raw,
+			}))),
+		},
+	}
+
+	issuerId, problems, err := sc.verifyCertChain(serial, cert, now)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if issuerId != "" {
+		resp.Data["issuer_id"] = issuerId.String()
+	}
+	resp.Data["chain_valid"] = len(problems) == 0
+
+	expired := now.After(cert.NotAfter)
+	if expired {
+		problems = append(problems, fmt.Sprintf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	} else if now.Before(cert.NotBefore) {
+		problems = append(problems, fmt.Sprintf("certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339)))
+	}
+	resp.Data["expired"] = expired
+
+	revokedEntry, err := fetchCertBySerial(sc, "revoked/", serial)
+	if err != nil {
+		return nil, err
+	}
+	resp.Data["revoked"] = revokedEntry != nil
+	if revokedEntry != nil {
+		var revInfo revocationInfo
+		if err := revokedEntry.DecodeJSON(&revInfo); err != nil {
+			return nil, fmt.Errorf("error decoding revocation entry for serial %s: %w", serial, err)
+		}
+		addRevocationDetails(resp.Data, &revInfo)
+		problems = append(problems, "certificate has been revoked")
+	}
+
+	resp.Data["problems"] = problems
+	return resp, nil
+}
+
+// verifyCertChain verifies the certificate against the CA chain of the
+// issuer recorded as having signed it, returning that issuer, if any, and
+// the problems found. The top of the chain is trusted as the mount's own
+// issuers are, whether or not it is self-signed. Only the validity periods
+// of the issuers are checked, at the given time or, when outside of the
+// certificate's own validity period, the nearest time within it.
+func (sc *storageContext) verifyCertChain(serial string, cert *x509.Certificate, now time.Time) (issuerID, []string, error) {
+	problems := []string{}
+
+	var issuerId issuerID
+	var chain []*x509.Certificate
+	if sc.Backend.useLegacyBundleCaStorage() {
+		caInfo, err := sc.fetchCAInfo(defaultRef, ReadOnlyUsage)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, block := range caInfo.GetFullChain() {
+			chain = append(chain, block.Certificate)
+		}
+		if !certSignedBy(cert, chain[0]) {
+			return "", append(problems, "certificate was not signed by any issuer of this mount"), nil
+		}
+	} else {
+		recordedId, issuer, err := sc.fetchRecordedCertIssuer(serial)
+		if err != nil {
+			return "", nil, err
+		}
+		switch {
+		case recordedId == "":
+			return "", append(problems, "the issuer of the certificate is unknown, as it was not recorded at issuance"), nil
+		case issuer == nil:
+			return recordedId, append(problems, fmt.Sprintf("issuer %v which signed the certificate has been deleted", recordedId)), nil
+		default:
+			issuerId = issuer.ID
+			if issuer.Revoked {
+				problems = append(problems, fmt.Sprintf("issuer %v has been revoked", issuer.ID))
+			}
+
+			chain, err = parseCertChain(issuer.CAChain)
+			if err != nil {
+				return "", nil, fmt.Errorf("unable to parse chain of issuer %v: %w", issuer.ID, err)
+			}
+		}
+	}
+
+	if len(chain) == 0 {
+		return issuerId, append(problems, fmt.Sprintf("issuer %v has no CA chain", issuerId)), nil
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(chain[len(chain)-1])
+	intermediates := x509.NewCertPool()
+	for _, caCert := range chain[:len(chain)-1] {
+		intermediates.AddCert(caCert)
+	}
+
+	verifyTime := now
+	if verifyTime.After(cert.NotAfter) {
+		verifyTime = cert.NotAfter
+	} else if verifyTime.Before(cert.NotBefore) {
+		verifyTime = cert.NotBefore
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   verifyTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return issuerId, problems, nil
+}
+
+// Returns the stored revocation entry of a certificate, for debugging
+func pathFetchRevocationEntry(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)/revocation`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-revocation-entry",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal, or as contiguous hex`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchRevocationEntryRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"revoked": {
+								Type:        framework.TypeBool,
+								Description: `Whether a revocation entry is stored for the certificate`,
+								Required:    true,
+							},
+							"certificate_bytes": {
+								Type:        framework.TypeString,
+								Description: `Base64-encoded DER certificate stored with the entry`,
+								Required:    false,
+							},
+							"revocation_time": {
+								Type:        framework.TypeInt64,
+								Description: `Stored revocation time, in Unix seconds`,
+								Required:    false,
+							},
+							"revocation_time_utc": {
+								Type:        framework.TypeTime,
+								Description: `Stored revocation time, zero on entries predating it`,
+								Required:    false,
+							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `ID of the issuer recorded for the certificate`,
+								Required:    false,
+							},
+							"on_hold": {
+								Type:        framework.TypeBool,
+								Description: `Whether the certificate is on hold rather than permanently revoked`,
+								Required:    false,
+							},
+							"revocation_reason": {
+								Type:        framework.TypeInt,
+								Description: `Stored RFC 5280 reason code`,
+								Required:    false,
+							},
+							"reason_recorded": {
+								Type:        framework.TypeBool,
+								Description: `Whether revocation_reason was recorded, rather than predating reasons`,
+								Required:    false,
+							},
+							"ocsp_only": {
+								Type:        framework.TypeBool,
+								Description: `Whether the revocation is only reported by OCSP and never listed on a CRL`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchRevocationEntryHelpSyn,
+		HelpDescription: pathFetchRevocationEntryHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchRevocationEntryRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serial := splitContiguousSerial(data.Get("serial").(string))
+	if len(serial) == 0 {
+		return logical.ErrorResponse("the serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	revokedEntry, err := fetchCertBySerial(sc, "revoked/", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if revokedEntry == nil {
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"revoked": false,
+			},
+		}, nil
+	}
+
+	var revInfo revocationInfo
+	if err := revokedEntry.DecodeJSON(&revInfo); err != nil {
+		return nil, fmt.Errorf("error decoding revocation entry for serial %s: %w", serial, err)
+	}
+
+	// Every field is returned as stored, including those omitted from
+	// storage when unset, rather than interpreted as by cert/:serial.
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"revoked":             true,
+			"certificate_bytes":   base64.StdEncoding.EncodeToString(revInfo.CertificateBytes),
+			"revocation_time":     revInfo.RevocationTime,
+			"revocation_time_utc": revInfo.RevocationTimeUTC,
+			"issuer_id":           revInfo.CertificateIssuer.String(),
+			"on_hold":             revInfo.OnHold,
+			"revocation_reason":   revInfo.RevocationReason,
+			"reason_recorded":     revInfo.ReasonRecorded,
+			"ocsp_only":           revInfo.OcspOnly,
+		},
+	}, nil
+}
+
+// addRevocationStatus records that a certificate was revoked, along with the
+// time and, when known, the reason of its revocation.
+func addRevocationStatus(data map[string]interface{}, revInfo *revocationInfo) {
+	data["revoked"] = true
+	addRevocationDetails(data, revInfo)
+}
+
+// revocationTimeRFC3339 returns the time of a revocation in RFC 3339 format.
+// Entries written before RevocationTimeUTC was recorded only hold the integer
+// RevocationTime, in Unix seconds, which is used instead; an empty string is
+// returned when neither is set.
+func revocationTimeRFC3339(revInfo *revocationInfo) string {
+	switch {
+	case !revInfo.RevocationTimeUTC.IsZero():
+		return revInfo.RevocationTimeUTC.Format(time.RFC3339Nano)
+	case revInfo.RevocationTime != 0:
+		return time.Unix(revInfo.RevocationTime, 0).UTC().Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+// addRevocationDetails adds the time and, when recorded, the reason of a
+// revocation to a response.
+func addRevocationDetails(data map[string]interface{}, revInfo *revocationInfo) {
+	data["revocation_time_rfc3339"] = revocationTimeRFC3339(revInfo)
+	if reason, ok := revocationReasonOf(revInfo); ok {
+		data["revocation_reason"] = reason
+		data["revocation_reason_string"] = revocationReasonNames[reason]
+	}
+}
+
+// This returns the CRL in a non-raw format
+func pathFetchCRLViaCertPath(b *backend) *framework.Path {
+	pattern := `cert/(crl|delta-crl)`
+
+	return &framework.Path{
+		Pattern: pattern,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-crl|cert-delta-crl",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchRead,
+				Responses: pathFetchReadSchema,
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+// This returns the list of serial numbers for certs
+func pathFetchListCerts(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "certs",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+			},
+			"prefix": {
+				Type: framework.TypeString,
+				Description: `Optional hex prefix of the serial numbers to list,
+such as 1a:2b or 1a2b; only certificates whose serial number starts with it
+are returned.`,
+			},
+			"issuer": {
+				Type: framework.TypeString,
+				Description: `Optional issuer reference, by ID or name, to list
+only the certificates recorded at issuance as signed by it. Reading each
+certificate's issuance record costs an additional storage read per listed
+entry. When set, limit applies to the matching certificates.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertList,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `A list of keys`,
+								Required:    true,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the listing is exhausted`,
+								Required:    false,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertList(ctx context.Context, req *logical.Request, data *framework.FieldData) (response *logical.Response, retErr error) {
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	prefix, err := normalizeSerialPrefix(data.Get("prefix").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+	limit = b.getIssuanceConfig().listPageLimit(limit)
+
+	// Use a read-only transaction if available, so that filtering by issuer
+	// reads the certificates from a consistent snapshot.
+	originalStorage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		readOnlyTxn, err := txnStorage.BeginReadOnlyTx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+		}
+
+		defer readOnlyTxn.Rollback(ctx)
+		req.Storage = readOnlyTxn
+	}
+	defer func() { req.Storage = originalStorage }()
+	sc := b.makeStorageContext(ctx, req.Storage)
+
+	issuerId, errResp, err := certListIssuerFilter(sc, data)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+
+	// Listing beneath the prefix returns keys relative to it, so the cursor
+	// must be made relative as well. A cursor sorting before every key with
+	// the prefix lists from the start; one sorting after them lists nothing.
+	var entries []string
+	var nextCursor string
+	if after < prefix || strings.HasPrefix(after, prefix) {
+		if strings.HasPrefix(after, prefix) {
+			after = after[len(prefix):]
+		} else {
+			after = ""
+		}
+
+		if issuerId == "" {
+			entries, err = req.Storage.ListPage(ctx, "certs/"+prefix, after, limit)
+			if err != nil {
+				return nil, err
+			}
+			for i := range entries {
+				entries[i] = denormalizeSerial(prefix + entries[i])
+			}
+			nextCursor = certListNextCursor(entries, limit)
+		} else {
+			entries, nextCursor, err = listCertsByIssuer(sc, prefix, after, limit, issuerId)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resp := logical.ListResponse(entries)
+	addCertListPagination(resp, data, nextCursor)
+	return resp, nil
+}
+
+// certListIssuerFilter resolves the issuer parameter of a certificate
+// listing to the issuer's ID, or empty when none was given.
+func certListIssuerFilter(sc *storageContext, data *framework.FieldData) (issuerID, *logical.Response, error) {
+	issuerRef := data.Get("issuer").(string)
+	if issuerRef == "" {
+		return "", nil, nil
+	}
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return "", logical.ErrorResponse("cannot filter by issuer until migration has completed"), nil
+	}
+
+	issuerId, err := sc.resolveIssuerReference(issuerRef)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return "", logical.ErrorResponse(err.Error()), nil
+		default:
+			return "", nil, err
+		}
+	}
+	return issuerId, nil, nil
+}
+
+// certIssuedBy reports whether the certificate with the given normalized
+// serial was recorded at issuance as signed by the issuer. Certificates
+// without an issuance record match no issuer.
+func certIssuedBy(sc *storageContext, serial string, issuerId issuerID) (bool, error) {
+	issuance, err := sc.fetchCertIssuance(serial)
+	if err != nil {
+		return false, fmt.Errorf("error fetching issuance record of %s: %w", denormalizeSerial(serial), err)
+	}
+	return issuance != nil && issuance.IssuerID == issuerId, nil
+}
+
+// listCertsByIssuer lists the (denormalized) serials of certificates
+// beneath the prefix which were recorded as signed by the issuer, starting
+// after the cursor relative to the prefix. Pages are read until limit
+// matches are found or storage is exhausted; the returned cursor continues
+// the listing, and is empty once it is exhausted.
+func listCertsByIssuer(sc *storageContext, prefix string, after string, limit int, issuerId issuerID) ([]string, string, error) {
+	var matches []string
+	cursor := after
+	for {
+		entries, err := sc.Storage.ListPage(sc.Context, "certs/"+prefix, cursor, limit)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, entry := range entries {
+			cursor = entry
+			matched, err := certIssuedBy(sc, prefix+entry, issuerId)
+			if err != nil {
+				return nil, "", err
+			}
+			if !matched {
+				continue
+			}
+
+			serial := denormalizeSerial(prefix + entry)
+			matches = append(matches, serial)
+			if limit > 0 && len(matches) == limit {
+				return matches, serial, nil
+			}
+		}
+
+		if limit <= 0 || len(entries) < limit {
+			return matches, "", nil
+		}
+	}
+}
+
+func pathFetchListCertNames(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/names/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-names",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertListNames,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers of the certificates`,
+								Required:    false,
+							},
+							"key_info": {
+								Type:        framework.TypeMap,
+								Description: `Common name of each certificate, by serial number`,
+								Required:    false,
+							},
+							"parse_errors": {
+								Type:        framework.TypeMap,
+								Description: `Errors of certificates which failed to parse, by serial number`,
+								Required:    false,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the listing is exhausted`,
+								Required:    false,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchListCertNamesHelpSyn,
+		HelpDescription: pathFetchListCertNamesHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertListNames(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+	limit = b.getIssuanceConfig().listPageLimit(limit)
+
+	entries, err := req.Storage.ListPage(ctx, "certs/", after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	keyInfo := make(map[string]interface{}, len(entries))
+	parseErrors := make(map[string]interface{})
+	for _, entry := range entries {
+		certEntry, err := req.Storage.Get(ctx, "certs/"+entry)
+		if err != nil {
+			return nil, err
+		}
+		if certEntry == nil {
+			continue
+		}
+
+		serial := denormalizeSerial(entry)
+		commonName, err := certCommonName(certEntry.Value)
+		if err != nil {
+			parseErrors[serial] = err.Error()
+			continue
+		}
+
+		keys = append(keys, serial)
+		keyInfo[serial] = map[string]interface{}{
+			"common_name": commonName,
+		}
+	}
+
+	resp := logical.ListResponseWithInfo(keys, keyInfo)
+	if len(parseErrors) > 0 {
+		resp.Data["parse_errors"] = parseErrors
+		resp.AddWarning(fmt.Sprintf("%d stored certificates failed to parse and were omitted from the listing; see parse_errors", len(parseErrors)))
+	}
+	nextCursor := certListNextCursor(entries, limit)
+	if nextCursor != "" {
+		nextCursor = denormalizeSerial(nextCursor)
+	}
+	addCertListPagination(resp, data, nextCursor)
+	return resp, nil
+}
+
+// certCommonName returns the subject common name of a DER certificate,
+// decoding only the fields of the certificate up to its subject rather than
+// parsing it in full.
+func certCommonName(der []byte) (string, error) {
+	var cert struct {
+		TBSCertificate struct {
+			Version            int `asn1:"optional,explicit,default:0,tag:0"`
+			SerialNumber       asn1.RawValue
+			SignatureAlgorithm asn1.RawValue
+			Issuer             asn1.RawValue
+			Validity           asn1.RawValue
+			Subject            asn1.RawValue
+			// The remaining fields are ignored, as trailing elements of a
+			// SEQUENCE are permitted by encoding/asn1.
+		}
+	}
+	if _, err := asn1.Unmarshal(der, &cert); err != nil {
+		return "", fmt.Errorf("malformed certificate: %w", err)
+	}
+
+	var rdns pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(cert.TBSCertificate.Subject.FullBytes, &rdns); err != nil {
+		return "", fmt.Errorf("malformed certificate subject: %w", err)
+	} else if len(rest) > 0 {
+		return "", fmt.Errorf("trailing data after certificate subject")
+	}
+
+	var subject pkix.Name
+	subject.FillFromRDNSequence(&rdns)
+	return subject.CommonName, nil
+}
+
+func pathFetchListExpiredCerts(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/expired/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "expired-certs",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+			},
+			"as_of": {
+				Type: framework.TypeString,
+				Description: `Optional RFC3339 time as of which certificates
+must have expired to be listed; defaults to the time of the request.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertListExpired,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers of the expired certificates`,
+								Required:    false,
+							},
+							"key_info": {
+								Type:        framework.TypeMap,
+								Description: `Expiry (not_after) of each certificate, by serial number`,
+								Required:    false,
+							},
+							"parse_errors": {
+								Type:        framework.TypeMap,
+								Description: `Errors of certificates which failed to parse, by serial number`,
+								Required:    false,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the listing is exhausted`,
+								Required:    false,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchListExpiredCertsHelpSyn,
+		HelpDescription: pathFetchListExpiredCertsHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertListExpired(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+	limit = b.getIssuanceConfig().listPageLimit(limit)
+
+	asOf := time.Now()
+	if value := data.Get("as_of").(string); value != "" {
+		asOf, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("failed to parse as_of as RFC3339: %v", err)), nil
+		}
+	}
+
+	// The limit applies to the expired certificates, so keep reading pages
+	// until it is reached or storage is exhausted.
+	var keys []string
+	keyInfo := make(map[string]interface{})
+	parseErrors := make(map[string]interface{})
+	var nextCursor string
+	cursor := after
+	for {
+		entries, err := req.Storage.ListPage(ctx, "certs/", cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		full := false
+		for _, entry := range entries {
+			cursor = entry
+			certEntry, err := req.Storage.Get(ctx, "certs/"+entry)
+			if err != nil {
+				return nil, err
+			}
+			if certEntry == nil {
+				continue
+			}
+
+			serial := denormalizeSerial(entry)
+			cert, err := x509.ParseCertificate(certEntry.Value)
+			if err != nil {
+				parseErrors[serial] = err.Error()
+				continue
+			}
+			if !asOf.After(cert.NotAfter) {
+				continue
+			}
+
+			keys = append(keys, serial)
+			keyInfo[serial] = map[string]interface{}{
+				"not_after": cert.NotAfter,
+			}
+			if limit > 0 && len(keys) == limit {
+				nextCursor = serial
+				full = true
+				break
+			}
+		}
+
+		if full || limit <= 0 || len(entries) < limit {
+			break
+		}
+	}
+
+	resp := logical.ListResponseWithInfo(keys, keyInfo)
+	if len(parseErrors) > 0 {
+		resp.Data["parse_errors"] = parseErrors
+		resp.AddWarning(fmt.Sprintf("%d stored certificates failed to parse and were omitted from the listing; see parse_errors", len(parseErrors)))
+	}
+	addCertListPagination(resp, data, nextCursor)
+	return resp, nil
+}
+
+// Formats of the detailed certificate listing.
+const (
+	certListFormatJSON   = "json"
+	certListFormatNDJSON = "ndjson"
+)
+
+// certListNDJSONPageSize bounds the storage entries read at once by an
+// NDJSON listing.
+const certListNDJSONPageSize = 1000
+
+// certKeyTypeAndBits returns the type and size of a certificate's public
+// key, as reported by the detailed certificate listing.
+func certKeyTypeAndBits(cert *x509.Certificate) (string, int) {
+	switch pubKey := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "rsa", pubKey.Size() * 8 // Convert byte size to bits
+	case *ecdsa.PublicKey:
+		return "ec", pubKey.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "ed25519", 256 // Fixed size for Ed25519
+	default:
+		return "unknown", 0
+	}
+}
+
+// weakCertWarnings returns warnings for a certificate signed with a
+// deprecated algorithm, such as SHA-1, or holding a key too weak for
+// continued use: RSA keys below 2048 bits and NIST P-224 keys.
+func weakCertWarnings(cert *x509.Certificate) []structuredWarning {
+	var warnings []structuredWarning
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		warnings = append(warnings, newWarning(warningCodeDeprecatedSignature, "", fmt.Sprintf("certificate %s is signed with the deprecated %s algorithm; reissue it with a SHA-2 based signature", serialFromCert(cert), cert.SignatureAlgorithm)))
+	}
+
+	keyType, keyBits := certKeyTypeAndBits(cert)
+	switch {
+	case keyType == "rsa" && keyBits < 2048:
+		warnings = append(warnings, newWarning(warningCodeWeakKey, "", fmt.Sprintf("certificate %s has a weak public key: %d-bit RSA keys are deprecated; use at least 2048 bits", serialFromCert(cert), keyBits)))
+	case keyType == "ec" && keyBits < 256:
+		warnings = append(warnings, newWarning(warningCodeWeakKey, "", fmt.Sprintf("certificate %s has a weak public key: the P-%d curve is deprecated; use P-256 or stronger", serialFromCert(cert), keyBits)))
+	}
+	return warnings
+}
+
+// certDetailedInfo returns the details of a certificate reported by the
+// detailed certificate listing and batch fetches; der is the certificate's
+// stored encoding, from which fingerprints are computed.
+func certDetailedInfo(cert *x509.Certificate, der []byte, includeSANs, includeFingerprints bool) map[string]interface{} {
+	// limit DNS names to 5, unless all SANs were requested
+	dnsNames := cert.DNSNames
+	if len(dnsNames) > 5 && !includeSANs {
+		dnsNames = dnsNames[:5]
+	}
+
+	keyType, keyBits := certKeyTypeAndBits(cert)
+	info := map[string]interface{}{
+		"common_name":         cert.Subject.CommonName,
+		"issuer":              cert.Issuer.String(),
+		"key_type":            keyType,
+		"key_bits":            keyBits,
+		"not_after":           cert.NotAfter,
+		"not_before":          cert.NotBefore,
+		"dns_names":           dnsNames,
+		"key_usage":           keyUsageLabels(cert),
+		"ext_key_usage":       extKeyUsageLabels(cert),
+		"signature_algorithm": cert.SignatureAlgorithm.String(),
+		"is_ca":               cert.IsCA,
+		"max_path_len":        certMaxPathLen(cert),
+	}
+	if includeSANs {
+		ipSANs := make([]string, 0, len(cert.IPAddresses))
+		for _, ip := range cert.IPAddresses {
+			ipSANs = append(ipSANs, ip.String())
+		}
+		uriSANs := make([]string, 0, len(cert.URIs))
+		for _, uri := range cert.URIs {
+			uriSANs = append(uriSANs, uri.String())
+		}
+		info["ip_sans"] = ipSANs
+		info["uri_sans"] = uriSANs
+		info["email_sans"] = append([]string{}, cert.EmailAddresses...)
+	}
+	if includeFingerprints {
+		sha1Sum := sha1.Sum(der)
+		sha256Sum := sha256.Sum256(der)
+		info["sha1_fingerprint"] = certutil.GetHexFormatted(sha1Sum[:], ":")
+		info["sha256_fingerprint"] = certutil.GetHexFormatted(sha256Sum[:], ":")
+	}
+	return info
+}
+
+// certMaxPathLen returns the path length constraint of a certificate's basic
+// constraints, or -1 when it carries none.
+func certMaxPathLen(cert *x509.Certificate) int {
+	if cert.MaxPathLen > 0 || (cert.MaxPathLen == 0 && cert.MaxPathLenZero) {
+		return cert.MaxPathLen
+	}
+	return -1
+}
+
+// sortCertListKeys sorts the keys of a detailed listing by a field of their
+// key_info, keeping serial number order between certificates which tie.
+func sortCertListKeys(keys []string, keyInfo map[string]interface{}, field string, descending bool) {
+	less := func(a, b map[string]interface{}) bool {
+		switch field {
+		case "common_name":
+			return a[field].(string) < b[field].(string)
+		default:
+			return a[field].(time.Time).Before(b[field].(time.Time))
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		a := keyInfo[keys[i]].(map[string]interface{})
+		b := keyInfo[keys[j]].(map[string]interface{})
+		if descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+// Values of validity_state, reporting where a point in time falls within a
+// certificate's validity period.
+const (
+	validityStateNotYetValid = "not_yet_valid"
+	validityStateValid       = "valid"
+	validityStateExpired     = "expired"
+)
+
+// certValidityState returns whether the certificate is not yet valid, valid
+// or expired at the given time.
+func certValidityState(cert *x509.Certificate, now time.Time) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		return validityStateNotYetValid
+	case now.After(cert.NotAfter):
+		return validityStateExpired
+	default:
+		return validityStateValid
+	}
+}
+
+// certNotFoundResponse is the 404 returned to JSON fetches of an unknown
+// serial, carrying an error response.
+func certNotFoundResponse(req *logical.Request, serial string) (*logical.Response, error) {
+	return logical.RespondWithStatusCode(logical.ErrorResponse(fmt.Sprintf("certificate with serial %s not found", serial)), req, http.StatusNotFound)
+}
+
+// certListNextCursor returns the after value for the page following a
+// listing of the given (denormalized) serials, or an empty string when the
+// listing wasn't limited by its page size.
+func certListNextCursor(serials []string, limit int) string {
+	if limit <= 0 || len(serials) < limit {
+		return ""
+	}
+	return serials[len(serials)-1]
+}
+
+// addCertListPagination sets the pagination fields of a certificate
+// listing: next_after and has_more, and next_cursor on request. An empty,
+// exhausted listing is left without data so that it remains a 404.
+func addCertListPagination(resp *logical.Response, data *framework.FieldData, nextCursor string) {
+	if _, ok := resp.Data["keys"]; ok || nextCursor != "" {
+		resp.Data["next_after"] = nextCursor
+		resp.Data["has_more"] = nextCursor != ""
+	}
+	if data.Get("include_next_cursor").(bool) {
+		resp.Data["next_cursor"] = nextCursor
+	}
+}
+
+func pathFetchListCertsDetailed(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/detailed/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "certs",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+			},
+			"include_fingerprints": {
+				Type: framework.TypeBool,
+				Description: `Whether to include the SHA-1 and SHA-256
+fingerprints of each certificate's DER encoding. The SHA-1 fingerprint is
+provided for compatibility with legacy systems only.`,
+				Default: false,
+			},
+			"include_sans": {
+				Type: framework.TypeBool,
+				Description: `Whether to return each certificate's complete
+dns_names, along with its ip_sans, uri_sans and email_sans. By default,
+only the first 5 DNS names are returned.`,
+				Default: false,
+			},
+			"include_revocation": {
+				Type: framework.TypeBool,
+				Description: `Whether to return each certificate's revocation
+status: revoked, along with revocation_time_rfc3339 and, when recorded,
+revocation_reason for revoked certificates. This reads an additional storage
+entry per certificate.`,
+				Default: false,
+			},
+			"format": {
+				Type: framework.TypeString,
+				Description: `Format of the listing: "json" (the default)
+returns keys and key_info, while "ndjson" returns a raw
+application/x-ndjson body with one JSON object per certificate.`,
+				Default:       certListFormatJSON,
+				AllowedValues: []interface{}{certListFormatJSON, certListFormatNDJSON},
+			},
+			"strict": {
+				Type: framework.TypeBool,
+				Description: `Whether to fail the listing when a stored
+certificate fails to parse. By default, such certificates are omitted
+from keys and reported under parse_errors instead.`,
+				Default: false,
+			},
+			"key_type": {
+				Type: framework.TypeString,
+				Description: `Only list certificates whose public key is of
+this type: "rsa", "ec" or "ed25519". Defaults to all key types.`,
+				AllowedValues: []interface{}{"", "rsa", "ec", "ed25519"},
+			},
+			"min_key_bits": {
+				Type: framework.TypeInt,
+				Description: `Only list certificates whose public key has at
+least this many bits. Defaults to 0, listing all key sizes.`,
+				Default: 0,
+			},
+			"expiring_within": {
+				Type: framework.TypeDurationSecond,
+				Description: `Only list certificates which are still valid
+but expire within this duration from now. Defaults to 0, listing all
+certificates.`,
+				Default: 0,
+			},
+			"not_after_before": {
+				Type: framework.TypeString,
+				Description: `Only list certificates expiring before this
+time, in RFC3339 format.`,
+			},
+			"not_after_after": {
+				Type: framework.TypeString,
+				Description: `Only list certificates expiring after this
+time, in RFC3339 format.`,
+			},
+			"issuer": {
+				Type: framework.TypeString,
+				Description: `Optional issuer reference, by ID or name, to list
+only the certificates recorded at issuance as signed by it. Reading each
+certificate's issuance record costs an additional storage read per listed
+entry. When set, limit applies to the matching certificates.`,
+			},
+			"sort": {
+				Type: framework.TypeString,
+				Description: `Field to sort the returned keys by: "not_after",
+"not_before", "issued" (the issuance time, an alias of not_before) or
+"common_name". Sorting applies only within the page of certificates
+fetched, so combined with limit, that many certificates are fetched in
+serial number order and then sorted. Defaults to serial number order. Not
+supported with the ndjson format.`,
+				AllowedValues: []interface{}{"", "not_after", "not_before", "issued", "common_name"},
+			},
+			"order": {
+				Type:          framework.TypeString,
+				Description:   `Order to sort the returned keys in, when sort is set: "asc" (the default) or "desc".`,
+				Default:       "asc",
+				AllowedValues: []interface{}{"asc", "desc"},
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertListDetailed,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `A list of keys`,
+								Required:    true,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the listing is exhausted`,
+								Required:    false,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    false,
+							},
+							"key_info": {
+								Type:        framework.TypeMap,
+								Description: `Key info with certificate details`,
+								Required:    false,
+							},
+							"parse_errors": {
+								Type:        framework.TypeMap,
+								Description: `Errors parsing stored certificates omitted from the listing, by serial number`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchHelpSyn,
+		HelpDescription: pathFetchHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertListDetailed(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	var responseKeys []string
+	responseInfo := make(map[string]interface{})
+
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+	limit = b.getIssuanceConfig().listPageLimit(limit)
+	includeFingerprints := data.Get("include_fingerprints").(bool)
+	includeSANs := data.Get("include_sans").(bool)
+	includeRevocation := data.Get("include_revocation").(bool)
+	strict := data.Get("strict").(bool)
+	parseErrors := make(map[string]interface{})
+
+	var ndjson bool
+	switch format := data.Get("format").(string); format {
+	case certListFormatJSON:
+	case certListFormatNDJSON:
+		ndjson = true
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("unknown format %q; must be %s or %s", format, certListFormatJSON, certListFormatNDJSON)), nil
+	}
+
+	keyTypeFilter := data.Get("key_type").(string)
+	switch keyTypeFilter {
+	case "", "rsa", "ec", "ed25519":
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("unknown key_type %q; must be rsa, ec or ed25519", keyTypeFilter)), nil
+	}
+	minKeyBits := data.Get("min_key_bits").(int)
+	if minKeyBits < 0 {
+		return logical.ErrorResponse("min_key_bits must not be negative"), nil
+	}
+	expiringWithin := time.Duration(data.Get("expiring_within").(int)) * time.Second
+	if expiringWithin < 0 {
+		return logical.ErrorResponse("expiring_within must not be negative"), nil
+	}
+	var notAfterBefore, notAfterAfter time.Time
+	if value := data.Get("not_after_before").(string); value != "" {
+		notAfterBefore, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("failed to parse not_after_before as RFC3339: %v", err)), nil
+		}
+	}
+	if value := data.Get("not_after_after").(string); value != "" {
+		notAfterAfter, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("failed to parse not_after_after as RFC3339: %v", err)), nil
+		}
+	}
+	if !notAfterBefore.IsZero() && !notAfterAfter.IsZero() && !notAfterAfter.Before(notAfterBefore) {
+		return logical.ErrorResponse("not_after_after must be earlier than not_after_before"), nil
+	}
+	filtering := keyTypeFilter != "" || minKeyBits > 0 || expiringWithin > 0 || !notAfterBefore.IsZero() || !notAfterAfter.IsZero()
+	now := time.Now()
+
+	sortField := data.Get("sort").(string)
+	switch sortField {
+	case "", "not_after", "not_before", "common_name":
+	case "issued":
+		// Certificates are issued valid from their NotBefore, so this is
+		// the issuance time.
+		sortField = "not_before"
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("unknown sort %q; must be not_after, not_before, issued or common_name", sortField)), nil
+	}
+	if sortField != "" && ndjson {
+		return logical.ErrorResponse("sort is not supported with the ndjson format"), nil
+	}
+	order := data.Get("order").(string)
+	switch order {
+	case "asc", "desc":
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("unknown order %q; must be asc or desc", order)), nil
+	}
+
+	// Use a read-only transaction if available. This doesn't stop others from writing to
+	// storage but ensures that all read operations within this block work on a consistent
+	// snapshot of the data in case an entry is deleted or updated during the read process.
+	originalStorage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		readOnlyTxn, err := txnStorage.BeginReadOnlyTx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+		}
+
+		defer readOnlyTxn.Rollback(ctx) // Ensure rollback after the operation
+		req.Storage = readOnlyTxn
+	}
+	sc := b.makeStorageContext(ctx, req.Storage)
+
+	issuerId, errResp, err := certListIssuerFilter(sc, data)
+	if errResp != nil || err != nil {
+		req.Storage = originalStorage
+		return errResp, err
+	}
+	if issuerId != "" {
+		filtering = true
+	}
+
+	// When filtering, the limit applies to the matching certificates, so
+	// keep reading pages until it is reached or storage is exhausted. NDJSON
+	// listings are always read in bounded pages, and each certificate is
+	// encoded as it is read rather than kept in responseInfo.
+	pageSize := limit
+	if ndjson && (pageSize <= 0 || pageSize > certListNDJSONPageSize) {
+		pageSize = certListNDJSONPageSize
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	matched := 0
+
+	var nextCursor string
+	cursor := after
+	for {
+		entries, err := req.Storage.ListPage(ctx, "certs/", cursor, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		full := false
+		for i := range entries {
+			if issuerId != "" {
+				issued, err := certIssuedBy(sc, entries[i], issuerId)
+				if err != nil {
+					return nil, err
+				}
+				if !issued {
+					cursor = entries[i]
+					continue
+				}
+			}
+
+			// Fetch the full certificate entry by key
+			entry, err := req.Storage.Get(ctx, "certs/"+entries[i])
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				return logical.ErrorResponse(fmt.Sprintf("failed to retrieve entry for %s", entries[i])), nil
+			}
+
+			cursor = entries[i]
+			entries[i] = denormalizeSerial(entries[i])
+
+			// Parse the certificate details
+			certData, err := x509.ParseCertificate(entry.Value)
+			if err != nil {
+				if strict {
+					return logical.ErrorResponse(fmt.Sprintf("failed to parse certificate for %s: %s", entries[i], err)), nil
+				}
+				// Skip the entry so that isolated legacy or corrupt
+				// certificates don't make the whole listing unusable.
+				if ndjson {
+					if err := encoder.Encode(map[string]interface{}{
+						"serial_number": entries[i],
+						"parse_error":   err.Error(),
+					}); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				parseErrors[entries[i]] = err.Error()
+				continue
+			}
+
+			keyType, keyBits := certKeyTypeAndBits(certData)
+			if keyTypeFilter != "" && keyType != keyTypeFilter {
+				continue
+			}
+			if keyBits < minKeyBits {
+				continue
+			}
+			if expiringWithin > 0 && (now.After(certData.NotAfter) || certData.NotAfter.After(now.Add(expiringWithin))) {
+				continue
+			}
+			if !notAfterBefore.IsZero() && !certData.NotAfter.Before(notAfterBefore) {
+				continue
+			}
+			if !notAfterAfter.IsZero() && !certData.NotAfter.After(notAfterAfter) {
+				continue
+			}
+
+			info := certDetailedInfo(certData, entry.Value, includeSANs, includeFingerprints)
+			if includeRevocation {
+				revokedEntry, err := fetchCertBySerial(sc, "revoked/", entries[i])
+				if err != nil {
+					return nil, err
+				}
+				if revokedEntry != nil {
+					var revInfo revocationInfo
+					if err := revokedEntry.DecodeJSON(&revInfo); err != nil {
+						return nil, fmt.Errorf("error decoding revocation entry for serial %s: %w", entries[i], err)
+					}
+					addRevocationStatus(info, &revInfo)
+				} else {
+					info["revoked"] = false
+				}
+			}
+
+			matched++
+			if ndjson {
+				info["serial_number"] = entries[i]
+				if err := encoder.Encode(info); err != nil {
+					return nil, err
+				}
+			} else {
+				responseKeys = append(responseKeys, string(entries[i]))
+				responseInfo[string(entries[i])] = info
+			}
+
+			if (filtering || ndjson) && limit > 0 && matched == limit {
+				nextCursor = entries[i]
+				full = true
+				break
+			}
+		}
+
+		if !filtering && !ndjson {
+			// Skipped entries still advance the cursor.
+			nextCursor = certListNextCursor(entries, limit)
+			break
+		}
+		if full || pageSize <= 0 || len(entries) < pageSize {
+			break
+		}
+	}
+
+	req.Storage = originalStorage
+
+	if ndjson {
+		return &logical.Response{
+			Data: map[string]interface{}{
+				logical.HTTPContentType: "application/x-ndjson",
+				logical.HTTPRawBody:     body.Bytes(),
+				logical.HTTPStatusCode:  http.StatusOK,
+			},
+		}, nil
+	}
+
+	// Pagination follows serial number order, so the cursor above is
+	// unaffected by sorting the page.
+	if sortField != "" {
+		sortCertListKeys(responseKeys, responseInfo, sortField, order == "desc")
+	}
+
+	resp := logical.ListResponseWithInfo(responseKeys, responseInfo)
+	if len(parseErrors) > 0 {
+		resp.Data["parse_errors"] = parseErrors
+		resp.AddWarning(fmt.Sprintf("%d stored certificates failed to parse and were omitted from the listing; see parse_errors", len(parseErrors)))
+	}
+	addCertListPagination(resp, data, nextCursor)
+	return resp, nil
+}
+
+func pathFetchCertBatch(b *backend) *framework.Path {
+	responses := map[int][]framework.Response{
+		http.StatusOK: {{
+			Description: "OK",
+			Fields: map[string]*framework.FieldSchema{
+				"certs": {
+					Type:        framework.TypeMap,
+					Description: `Details of each certificate found, keyed by serial number`,
+					Required:    true,
+				},
+				"not_found": {
+					Type:        framework.TypeStringSlice,
+					Description: `Serial numbers with no stored certificate`,
+					Required:    true,
+				},
+				"parse_errors": {
+					Type:        framework.TypeMap,
+					Description: `Serial numbers of stored certificates which failed to parse, mapped to the parse error`,
+					Required:    false,
+				},
+			},
+		}},
+	}
+
+	return &framework.Path{
+		Pattern: "certs/batch/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "certs-batch",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serials": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `Serial numbers of the certificates to fetch, in
+colon- or hyphen-separated hex, or as contiguous hex. At most
+max_batch_fetch_serials from config/issuance, 256 by default, may be given.`,
+				Required: true,
+			},
+			"include_fingerprints": {
+				Type: framework.TypeBool,
+				Description: `Whether to include the SHA-1 and SHA-256
+fingerprints of each certificate's DER encoding. Defaults to false.`,
+			},
+			"include_sans": {
+				Type: framework.TypeBool,
+				Description: `Whether to include all of each certificate's DNS
+names, along with its IP, URI and email Subject Alternative Names, rather than
+only its first five DNS names. Defaults to false.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:  b.pathFetchCertBatch,
+				Responses: responses,
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback:  b.pathFetchCertBatch,
+				Responses: responses,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb: "fetch",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCertBatchHelpSyn,
+		HelpDescription: pathFetchCertBatchHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertBatch(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serials := data.Get("serials").([]string)
+	if len(serials) == 0 {
+		return logical.ErrorResponse("missing required serials"), nil
+	}
+	if maxSerials := b.getIssuanceConfig().maxBatchFetchSerials(); len(serials) > maxSerials {
+		return logical.ErrorResponse(fmt.Sprintf("%d serial numbers requested, exceeding the mount's max_batch_fetch_serials of %d", len(serials), maxSerials)), nil
+	}
+	includeFingerprints := data.Get("include_fingerprints").(bool)
+	includeSANs := data.Get("include_sans").(bool)
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	certs := make(map[string]interface{}, len(serials))
+	notFound := []string{}
+	parseErrors := make(map[string]interface{})
+	for _, serial := range serials {
+		serial = strings.TrimSpace(serial)
+		if _, ok := serialToBigInt(serial); !ok {
+			return logical.ErrorResponse(fmt.Sprintf("invalid serial number %q", serial)), nil
+		}
+		serial = denormalizeSerial(splitContiguousSerial(serial))
+		if _, seen := certs[serial]; seen {
+			continue
+		}
+
+		entry, err := fetchCertBySerial(sc, "certs/", serial)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			notFound = append(notFound, serial)
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(entry.Value)
+		if err != nil {
+			parseErrors[serial] = err.Error()
+			continue
+		}
+		certs[serial] = certDetailedInfo(cert, entry.Value, includeSANs, includeFingerprints)
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"certs":     certs,
+			"not_found": strutil.RemoveDuplicatesStable(notFound, false),
+		},
+	}
+	if len(parseErrors) > 0 {
+		resp.Data["parse_errors"] = parseErrors
+		resp.AddWarning(fmt.Sprintf("%d stored certificates failed to parse and were omitted from certs; see parse_errors", len(parseErrors)))
+	}
+	return resp, nil
+}
+
+const pathFetchCAInfoHelpSyn = `
+Fetch identifying details of the default issuer's certificate.
+`
+
+const pathFetchCAInfoHelpDesc = `
+This endpoint returns the serial number, subject, key identifiers, SHA-256
+fingerprint and validity window of the default issuer's certificate, for
+certificate pinning and trust store reconciliation, without returning the
+certificate itself.
+`
+
+const pathFetchCAKeyInfoHelpSyn = `
+Fetch details of the default issuer's key, without the key itself.
+`
+
+const pathFetchCAKeyInfoHelpDesc = `
+This endpoint returns the type, size and public key SHA-256 fingerprint of the
+default issuer's key, along with the IDs of the issuer and its key, for
+tracking key rotations. The fingerprint is computed over the certificate's
+SubjectPublicKeyInfo; no private key material is returned.
+`
+
+const pathFetchCAChainVerifyHelpSyn = `
+Verify that the default issuer's CA chain links together.
+`
+
+const pathFetchCAChainVerifyHelpDesc = `
+This endpoint walks the default issuer's CA chain, as served by ca_chain, and
+checks that each certificate names and is signed by the certificate following
+it. It returns valid along with a list of problems describing any broken link
+or misordered certificate. The chain need not end in a self-signed root.
+`
+
+const pathFetchCRLMetadataHelpSyn = `
+Fetch the number, validity and size of the default issuer's CRL.
+`
+
+const pathFetchCRLMetadataHelpDesc = `
+This endpoint returns the CRL number, thisUpdate and nextUpdate times, and
+number of entries of the default issuer's CRL, or of its delta CRL on
+crl/delta/metadata, so that staleness can be monitored without fetching and
+parsing the CRL itself.
+`
+
+const pathFetchCRLJSONHelpSyn = `
+Fetch the entries of the CRL or delta CRL as JSON.
+`
+
+const pathFetchCRLJSONHelpDesc = `
+This endpoint parses the default issuer's CRL, or its delta CRL on
+crl/delta/json, and returns its number, thisUpdate and nextUpdate times and
+its entries, each with its serial number, revocation time and reason, for
+clients unable to parse DER or PEM CRLs. The entries are ordered by serial
+number and may be paged through with after and limit.
+`
+
+const pathFetchCRLWithIssuerHelpSyn = `
+Fetch the CRL along with the issuer which signed it.
+`
+
+const pathFetchCRLWithIssuerHelpDesc = `
+This endpoint returns the default issuer's CRL, or its delta CRL on
+crl/delta/with-issuer, as base64-encoded DER, along with the certificate and
+CA chain of the issuer which signed it when it was built. This spares clients
+validating the CRL a separate request for the issuer, which in multi-issuer
+setups may not be the default issuer served by ca.
+`
+
+const pathFetchCertBatchHelpSyn = `
+Fetch the details of several certificates by serial number.
+`
+
+const pathFetchCertBatchHelpDesc = `
+This endpoint returns, for each requested serial number, the certificate
+details reported by the detailed certificate listing, keyed by the serial
+number in colon-separated form. Serial numbers with no stored certificate are
+listed under not_found rather than failing the request. The number of serial
+numbers accepted by a single request is bounded by max_batch_fetch_serials on
+config/issuance.
+`
+
+func pathFetchCertCount(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/count/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-count",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"include_revoked": {
+				Type:        framework.TypeBool,
+				Description: `Whether to also return revoked_count, the number of revoked certificates.`,
+				Default:     false,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCertCountRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"count": {
+								Type:        framework.TypeInt,
+								Description: `Number of certificates stored by the mount`,
+								Required:    true,
+							},
+							"revoked_count": {
+								Type:        framework.TypeInt,
+								Description: `Number of revoked certificates, when include_revoked is set`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathFetchCertCountHelpSyn,
+		HelpDescription: pathFetchCertCountHelpDesc,
+	}
+}
+
+func (b *backend) pathFetchCertCountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	count, err := countStorageEntries(ctx, req.Storage, "certs/")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"count": count,
+		},
+	}
+
+	if data.Get("include_revoked").(bool) {
+		revokedCount, err := countStorageEntries(ctx, req.Storage, "revoked/")
+		if err != nil {
+			return nil, err
+		}
+		resp.Data["revoked_count"] = revokedCount
 	}
+
+	return resp, nil
 }
 
-// Returns any valid (non-revoked) cert. Since "ca" fits the pattern, this path
-// also handles returning the CA cert in a non-raw format.
-func pathFetchValid(b *backend) *framework.Path {
+// countStorageEntries counts the entries beneath a storage prefix, listing
+// them in bounded pages rather than all at once.
+func countStorageEntries(ctx context.Context, storage logical.Storage, prefix string) (int, error) {
+	count := 0
+	after := ""
+	for {
+		entries, err := storage.ListPage(ctx, prefix, after, 1000)
+		if err != nil {
+			return 0, err
+		}
+		if len(entries) == 0 {
+			return count, nil
+		}
+		count += len(entries)
+		after = entries[len(entries)-1]
+	}
+}
+
+const pathFetchCertCountHelpSyn = `
+Count the certificates stored by the mount.
+`
+
+const pathFetchCertCountHelpDesc = `
+This endpoint returns the number of certificates stored by the mount, without
+returning their serial numbers, by listing them from storage in bounded pages.
+When include_revoked is set, the number of revoked certificates is returned as
+well.
+`
+
+func pathFetchLatestCert(b *backend) *framework.Path {
 	return &framework.Path{
-		Pattern: `cert/(?P<serial>[0-9A-Fa-f-:]+)`,
+		Pattern: "certs/latest/?$",
 
 		DisplayAttrs: &framework.DisplayAttributes{
 			OperationPrefix: operationPrefixPKI,
-			OperationSuffix: "cert",
+			OperationSuffix: "latest-cert",
 		},
 
 		Fields: map[string]*framework.FieldSchema{
-			"serial": {
-				Type: framework.TypeString,
-				Description: `Certificate serial number, in colon- or
-hyphen-separated octal`,
+			"common_name": {
+				Type:        framework.TypeString,
+				Description: `Common name to find the most recently issued certificate for.`,
+				Required:    true,
+			},
+			"match_sans": {
+				Type: framework.TypeBool,
+				Description: `Whether to also consider certificates carrying the
+common name as a DNS Subject Alternative Name. When set, certificates whose
+SANs match are preferred over those matching only on their subject.`,
+				Default: false,
 			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
 			logical.ReadOperation: &framework.PathOperation{
-				Callback:  b.pathFetchRead,
-				Responses: pathFetchReadSchema,
+				Callback: b.pathFetchLatestCertRead,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"serial_number": {
+								Type:        framework.TypeString,
+								Description: `Serial number of the certificate`,
+								Required:    true,
+							},
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `The PEM-encoded certificate`,
+								Required:    true,
+							},
+							"not_before": {
+								Type:        framework.TypeTime,
+								Description: `Start of the certificate's validity period`,
+								Required:    true,
+							},
+							"expiration": {
+								Type:        framework.TypeInt64,
+								Description: `Expiration of the certificate as a Unix timestamp`,
+								Required:    true,
+							},
+						},
+					}},
+				},
 			},
 		},
 
-		HelpSynopsis:    pathFetchHelpSyn,
-		HelpDescription: pathFetchHelpDesc,
+		HelpSynopsis:    pathFetchLatestCertHelpSyn,
+		HelpDescription: pathFetchLatestCertHelpDesc,
 	}
 }
 
-// This returns the CRL in a non-raw format
-func pathFetchCRLViaCertPath(b *backend) *framework.Path {
-	pattern := `cert/(crl|delta-crl)`
+func (b *backend) pathFetchLatestCertRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	commonName := data.Get("common_name").(string)
+	if len(commonName) == 0 {
+		return logical.ErrorResponse("missing required common_name"), nil
+	}
+	matchSans := data.Get("match_sans").(bool)
 
-	return &framework.Path{
-		Pattern: pattern,
+	// As with the detailed listing, scan a consistent snapshot of storage
+	// when the backend supports it.
+	storage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		readOnlyTxn, err := txnStorage.BeginReadOnlyTx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+		}
 
-		DisplayAttrs: &framework.DisplayAttributes{
-			OperationPrefix: operationPrefixPKI,
-			OperationSuffix: "cert-crl|cert-delta-crl",
-		},
+		defer readOnlyTxn.Rollback(ctx)
+		storage = readOnlyTxn
+	}
+	sc := b.makeStorageContext(ctx, storage)
 
-		Operations: map[logical.Operation]framework.OperationHandler{
-			logical.ReadOperation: &framework.PathOperation{
-				Callback:  b.pathFetchRead,
-				Responses: pathFetchReadSchema,
-			},
-		},
+	type latestCandidate struct {
+		serial   string
+		cert     *x509.Certificate
+		sanMatch bool
+	}
 
-		HelpSynopsis:    pathFetchHelpSyn,
-		HelpDescription: pathFetchHelpDesc,
+	now := time.Now()
+	var candidates []latestCandidate
+	after := ""
+	for {
+		entries, err := storage.ListPage(ctx, "certs/", after, 1000)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1]
+
+		for _, entry := range entries {
+			certEntry, err := storage.Get(ctx, "certs/"+entry)
+			if err != nil {
+				return nil, err
+			}
+			if certEntry == nil || len(certEntry.Value) == 0 {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(certEntry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate for %s: %w", denormalizeSerial(entry), err)
+			}
+			if now.After(cert.NotAfter) {
+				continue
+			}
+
+			sanMatch := false
+			if matchSans {
+				for _, name := range cert.DNSNames {
+					if strings.EqualFold(name, commonName) {
+						sanMatch = true
+						break
+					}
+				}
+			}
+			if !sanMatch && !strings.EqualFold(cert.Subject.CommonName, commonName) {
+				continue
+			}
+
+			candidates = append(candidates, latestCandidate{
+				serial:   denormalizeSerial(entry),
+				cert:     cert,
+				sanMatch: sanMatch,
+			})
+		}
+	}
+
+	// Order SAN matches first when requested, then newest first; revocation
+	// is only checked until the first acceptable candidate is found.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].sanMatch != candidates[j].sanMatch {
+			return candidates[i].sanMatch
+		}
+		return candidates[i].cert.NotBefore.After(candidates[j].cert.NotBefore)
+	})
+
+	for _, candidate := range candidates {
+		revInfo, err := sc.fetchRevocationInfo(candidate.serial)
+		if err != nil {
+			return nil, err
+		}
+		if revInfo != nil {
+			continue
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"serial_number": candidate.serial,
+				"certificate": strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{
+					Type:  "CERTIFICATE",
+					Bytes: candidate.cert.Raw,
+				}))),
+				"not_before": candidate.cert.NotBefore,
+				"expiration": candidate.cert.NotAfter.Unix(),
+			},
+		}, nil
 	}
+
+	return nil, nil
 }
 
-// This returns the list of serial numbers for certs
-func pathFetchListCerts(b *backend) *framework.Path {
+func pathFetchFindCerts(b *backend) *framework.Path {
 	return &framework.Path{
-		Pattern: "certs/?$",
+		Pattern: "certs/find/?$",
 
 		DisplayAttrs: &framework.DisplayAttributes{
 			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "find",
 			OperationSuffix: "certs",
 		},
 
 		Fields: map[string]*framework.FieldSchema{
-			"after": {
-				Type:        framework.TypeString,
-				Description: `Optional entry to list begin listing after, not required to exist.`,
-			},
-			"limit": {
-				Type:        framework.TypeInt,
-				Description: `Optional number of entries to return; defaults to all entries.`,
+			"ski": {
+				Type: framework.TypeString,
+				Description: `Subject key identifier to find certificates for, in
+hex, either contiguous or with colon-separated octets.`,
+				Required: true,
 			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
-			logical.ListOperation: &framework.PathOperation{
-				Callback: b.pathFetchCertList,
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchFindCertsRead,
 				Responses: map[int][]framework.Response{
 					http.StatusOK: {{
 						Description: "OK",
 						Fields: map[string]*framework.FieldSchema{
-							"keys": {
+							"serial_numbers": {
 								Type:        framework.TypeStringSlice,
-								Description: `A list of keys`,
+								Description: `Serial numbers of the certificates with the subject key identifier`,
 								Required:    true,
 							},
 						},
@@ -243,64 +3508,112 @@ func pathFetchListCerts(b *backend) *framework.Path {
 			},
 		},
 
-		HelpSynopsis:    pathFetchHelpSyn,
-		HelpDescription: pathFetchHelpDesc,
+		HelpSynopsis:    pathFetchFindCertsHelpSyn,
+		HelpDescription: pathFetchFindCertsHelpDesc,
 	}
 }
 
-func (b *backend) pathFetchCertList(ctx context.Context, req *logical.Request, data *framework.FieldData) (response *logical.Response, retErr error) {
-	after := data.Get("after").(string)
-	limit := data.Get("limit").(int)
-	if limit <= 0 {
-		limit = -1
+func (b *backend) pathFetchFindCertsRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	rawSKI := data.Get("ski").(string)
+	if len(rawSKI) == 0 {
+		return logical.ErrorResponse("missing required ski"), nil
 	}
-
-	entries, err := req.Storage.ListPage(ctx, "certs/", after, limit)
+	ski, err := parseKeyIdentifier(rawSKI)
 	if err != nil {
-		return nil, err
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	// As with the detailed listing, scan a consistent snapshot of storage
+	// when the backend supports it.
+	storage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		readOnlyTxn, err := txnStorage.BeginReadOnlyTx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+		}
+
+		defer readOnlyTxn.Rollback(ctx)
+		storage = readOnlyTxn
 	}
-	for i := range entries {
-		entries[i] = denormalizeSerial(entries[i])
+
+	serials := []string{}
+	after := ""
+	for {
+		entries, err := storage.ListPage(ctx, "certs/", after, 1000)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1]
+
+		for _, entry := range entries {
+			certEntry, err := storage.Get(ctx, "certs/"+entry)
+			if err != nil {
+				return nil, err
+			}
+			if certEntry == nil || len(certEntry.Value) == 0 {
+				continue
+			}
+
+			// Unparsable certificates can't match; skip them rather than
+			// failing the search.
+			cert, err := x509.ParseCertificate(certEntry.Value)
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(cert.SubjectKeyId, ski) {
+				serials = append(serials, denormalizeSerial(entry))
+			}
+		}
 	}
-	return logical.ListResponse(entries), nil
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"serial_numbers": serials,
+		},
+	}, nil
 }
 
-func pathFetchListCertsDetailed(b *backend) *framework.Path {
+func pathFetchCTExport(b *backend) *framework.Path {
 	return &framework.Path{
-		Pattern: "certs/detailed/?$",
+		Pattern: "certs/ct-export/?$",
 
 		DisplayAttrs: &framework.DisplayAttributes{
 			OperationPrefix: operationPrefixPKI,
-			OperationSuffix: "certs",
+			OperationVerb:   "export",
+			OperationSuffix: "certs-ct",
 		},
 
 		Fields: map[string]*framework.FieldSchema{
 			"after": {
 				Type:        framework.TypeString,
-				Description: `Optional entry to list begin listing after, not required to exist.`,
+				Description: `Cursor returned by a previous call; export continues with the certificate after this serial number.`,
 			},
 			"limit": {
 				Type:        framework.TypeInt,
-				Description: `Optional number of entries to return; defaults to all entries.`,
+				Description: `Maximum number of certificates to return; defaults to 100.`,
+				Default:     defaultCTExportLimit,
 			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
-			logical.ListOperation: &framework.PathOperation{
-				Callback: b.pathFetchCertListDetailed,
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathFetchCTExportRead,
 				Responses: map[int][]framework.Response{
 					http.StatusOK: {{
 						Description: "OK",
 						Fields: map[string]*framework.FieldSchema{
-							"keys": {
-								Type:        framework.TypeStringSlice,
-								Description: `A list of keys`,
+							"entries": {
+								Type:        framework.TypeSlice,
+								Description: `Issued certificates as base64 DER with their extracted names and validity`,
 								Required:    true,
 							},
-							"key_info": {
-								Type:        framework.TypeMap,
-								Description: `Key info with certificate details`,
-								Required:    false,
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once all certificates were returned`,
+								Required:    true,
 							},
 						},
 					}},
@@ -308,96 +3621,99 @@ func pathFetchListCertsDetailed(b *backend) *framework.Path {
 			},
 		},
 
-		HelpSynopsis:    pathFetchHelpSyn,
-		HelpDescription: pathFetchHelpDesc,
+		HelpSynopsis:    pathFetchCTExportHelpSyn,
+		HelpDescription: pathFetchCTExportHelpDesc,
 	}
 }
 
-func (b *backend) pathFetchCertListDetailed(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	var responseKeys []string
-	responseInfo := make(map[string]interface{})
+const defaultCTExportLimit = 100
 
+func (b *backend) pathFetchCTExportRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	after := data.Get("after").(string)
+	if len(after) > 0 {
+		after = normalizeSerial(after)
+	}
 	limit := data.Get("limit").(int)
 	if limit <= 0 {
-		limit = -1
+		return logical.ErrorResponse("limit must be positive"), nil
 	}
 
-	// Use a read-only transaction if available. This doesn't stop others from writing to
-	// storage but ensures that all read operations within this block work on a consistent
-	// snapshot of the data in case an entry is deleted or updated during the read process.
-	originalStorage := req.Storage
+	// As with the detailed listing, read a consistent snapshot of storage
+	// when the backend supports it.
+	storage := req.Storage
 	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
 		readOnlyTxn, err := txnStorage.BeginReadOnlyTx(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
 		}
 
-		defer readOnlyTxn.Rollback(ctx) // Ensure rollback after the operation
-		req.Storage = readOnlyTxn
+		defer readOnlyTxn.Rollback(ctx)
+		storage = readOnlyTxn
 	}
 
-	entries, err := req.Storage.ListPage(ctx, "certs/", after, limit)
+	keys, err := storage.ListPage(ctx, "certs/", after, limit)
 	if err != nil {
 		return nil, err
 	}
-	for i := range entries {
-		// Fetch the full certificate entry by key
-		entry, err := req.Storage.Get(ctx, "certs/"+entries[i])
+
+	entries := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		entry, err := storage.Get(ctx, "certs/"+key)
 		if err != nil {
 			return nil, err
 		}
 		if entry == nil {
-			return logical.ErrorResponse(fmt.Sprintf("failed to retrieve entry for %s", entries[i])), nil
+			continue
 		}
 
-		entries[i] = denormalizeSerial(entries[i])
-		responseKeys = append(responseKeys, string(entries[i]))
-
-		// Parse the certificate details
-		certData, err := x509.ParseCertificate(entry.Value)
+		cert, err := x509.ParseCertificate(entry.Value)
 		if err != nil {
-			return logical.ErrorResponse(fmt.Sprintf("failed to parse certificate for %s: %s", entries[i], err)), nil
+			return nil, fmt.Errorf("failed to parse certificate for %s: %w", denormalizeSerial(key), err)
 		}
 
-		// limit DNS names to 5
-		dnsNames := certData.DNSNames
-		if len(dnsNames) > 5 {
-			dnsNames = dnsNames[:5]
-		}
+		entries = append(entries, ctExportEntry(cert))
+	}
 
-		// Parse the key bits and type
-		var keyBits int
-		var keyType string
-		switch pubKey := certData.PublicKey.(type) {
-		case *rsa.PublicKey:
-			keyBits = pubKey.Size() * 8 // Convert byte size to bits
-			keyType = "rsa"
-		case *ecdsa.PublicKey:
-			keyBits = pubKey.Curve.Params().BitSize
-			keyType = "ec"
-		case ed25519.PublicKey:
-			keyBits = 256 // Fixed size for Ed25519
-			keyType = "ed25519"
-		default:
-			keyBits = 0 // Unknown key type
-			keyType = "unknown"
-		}
+	nextCursor := ""
+	if len(keys) == limit {
+		nextCursor = denormalizeSerial(keys[len(keys)-1])
+	}
 
-		responseInfo[string(entries[i])] = map[string]interface{}{
-			"common_name": certData.Subject.CommonName,
-			"issuer":      certData.Issuer.String(),
-			"key_type":    keyType,
-			"key_bits":    keyBits,
-			"not_after":   certData.NotAfter,
-			"not_before":  certData.NotBefore,
-			"dns_names":   dnsNames,
-		}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"entries":     entries,
+			"next_cursor": nextCursor,
+		},
+	}, nil
+}
+
+// ctExportEntry describes a certificate the way certificate transparency
+// monitors consume log entries: the DER certificate alongside the names and
+// validity period extracted from it.
+func ctExportEntry(cert *x509.Certificate) map[string]interface{} {
+	ipAddresses := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
 	}
 
-	req.Storage = originalStorage
+	uris := make([]string, 0, len(cert.URIs))
+	for _, uri := range cert.URIs {
+		uris = append(uris, uri.String())
+	}
 
-	return logical.ListResponseWithInfo(responseKeys, responseInfo), nil
+	return map[string]interface{}{
+		"serial_number":   serialFromCert(cert),
+		"leaf_cert":       base64.StdEncoding.EncodeToString(cert.Raw),
+		"subject":         cert.Subject.String(),
+		"issuer":          cert.Issuer.String(),
+		"dns_names":       append([]string{}, cert.DNSNames...),
+		"ip_addresses":    ipAddresses,
+		"email_addresses": append([]string{}, cert.EmailAddresses...),
+		"uris":            uris,
+		"not_before":      cert.NotBefore.UTC().Format(time.RFC3339),
+		"not_after":       cert.NotAfter.UTC().Format(time.RFC3339),
+		"is_ca":           cert.IsCA,
+	}
 }
 
 func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (response *logical.Response, retErr error) {
@@ -405,10 +3721,26 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 	var certEntry, revokedEntry *logical.StorageEntry
 	var funcErr error
 	var certificate []byte
+	var derCertificate []byte
 	var fullChain []byte
+	var chainList []string
 	var revocationTime int64
-	var revocationIssuerId string
+	var certIssuerId string
 	var revocationTimeRfc3339 string
+	var revocationReason int
+	var haveRevocationReason bool
+	var wrapPKCS7 bool
+	var caGone bool
+	var noCAMessage string
+	var extensions []map[string]interface{}
+	var jwkFormat bool
+	var jwk map[string]interface{}
+	var emptyOkMissing bool
+	var caIssuerRef string
+	var base64Format bool
+	var fetchedCert *x509.Certificate
+	var issuerChecked, issuerPresent bool
+	var negotiated bool
 
 	response = &logical.Response{
 		Data: map[string]interface{}{},
@@ -424,8 +3756,42 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 		req:       req,
 		issuerRef: defaultRef,
 	}
+
+	// Only cert/:serial, including cert/ca, and the CRL paths accept a
+	// format; of the latter, only the DER ones may be base64-encoded.
+	if format, ok := data.GetOk("format"); ok {
+		if strings.HasPrefix(req.Path, "crl") {
+			switch {
+			case format.(string) == fetchFormatDER:
+			case format.(string) == fetchFormatBase64 && (req.Path == "crl" || req.Path == "crl/delta"):
+				base64Format = true
+			case format.(string) == fetchFormatBase64:
+				response = logical.ErrorResponse(fmt.Sprintf("format %s is only supported on crl and crl/delta", fetchFormatBase64))
+				goto reply
+			default:
+				response = logical.ErrorResponse(fmt.Sprintf("unknown format %q; must be %s or %s", format, fetchFormatDER, fetchFormatBase64))
+				goto reply
+			}
+		} else {
+			switch format.(string) {
+			case fetchFormatPEM:
+			case fetchFormatJWK:
+				jwkFormat = true
+			default:
+				response = logical.ErrorResponse(fmt.Sprintf("unknown format %q; must be %s or %s", format, fetchFormatPEM, fetchFormatJWK))
+				goto reply
+			}
+		}
+	}
+
 	switch {
 	case req.Path == "ca" || req.Path == "ca/pem" || req.Path == "cert/ca" || req.Path == "cert/ca/raw" || req.Path == "cert/ca/raw/pem":
+		caIssuerRef, retErr = fetchIssuerRef(sc)
+		if retErr != nil {
+			goto reply
+		}
+
+		modifiedCtx.issuerRef = issuerID(caIssuerRef)
 		modifiedCtx.reqType = ifModifiedCA
 		ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
 		if err != nil || ret {
@@ -441,13 +3807,43 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 		} else if req.Path == "cert/ca" {
 			pemType = "CERTIFICATE"
 			contentType = ""
+		} else if req.Path == "ca" {
+			negotiated = true
+			if acceptsPEM(req, contentType) {
+				pemType = "CERTIFICATE"
+				contentType = "application/pem-certificate-chain"
+			}
+		}
+	case req.Path == "ca_chain" || req.Path == "cert/ca_chain" || req.Path == "ca_chain/json" || req.Path == "ca_chain/p7b" || req.Path == "ca_chain/p7b/pem":
+		caIssuerRef, retErr = fetchIssuerRef(sc)
+		if retErr != nil {
+			goto reply
+		}
+
+		modifiedCtx.issuerRef = issuerID(caIssuerRef)
+		modifiedCtx.reqType = ifModifiedCAChain
+		ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
+		if err != nil || ret {
+			retErr = err
+			goto reply
 		}
-	case req.Path == "ca_chain" || req.Path == "cert/ca_chain":
+
 		serial = "ca_chain"
 		if req.Path == "ca_chain" {
+			negotiated = true
 			contentType = "application/pkix-cert"
+			if acceptsPEM(req, contentType) {
+				contentType = "application/pem-certificate-chain"
+			}
+		} else if strings.HasPrefix(req.Path, "ca_chain/p7b") {
+			wrapPKCS7 = true
+			contentType = "application/x-pkcs7-certificates"
+			if strings.HasSuffix(req.Path, "/pem") {
+				pemType = "PKCS7"
+				contentType = "application/x-pem-file"
+			}
 		}
-	case req.Path == "crl" || req.Path == "crl/pem" || req.Path == "crl/delta" || req.Path == "crl/delta/pem" || req.Path == "cert/crl" || req.Path == "cert/crl/raw" || req.Path == "cert/crl/raw/pem" || req.Path == "cert/delta-crl" || req.Path == "cert/delta-crl/raw" || req.Path == "cert/delta-crl/raw/pem":
+	case req.Path == "crl" || req.Path == "crl/pem" || req.Path == "crl/delta" || req.Path == "crl/delta/pem" || req.Path == "cert/crl" || req.Path == "cert/crl/raw" || req.Path == "cert/crl/raw/pem" || req.Path == "cert/delta-crl" || req.Path == "cert/delta-crl/raw" || req.Path == "cert/delta-crl/raw/pem" || req.Path == "crl/pkcs7" || req.Path == "crl/pkcs7/pem":
 		var isDelta bool
 		if strings.Contains(req.Path, "delta") {
 			isDelta = true
@@ -458,35 +3854,98 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 			modifiedCtx.reqType = ifModifiedDeltaCRL
 		}
 
+		// Conditional requests only apply to the raw CRL.
+		if !base64Format {
+			ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
+			if err != nil || ret {
+				retErr = err
+				goto reply
+			}
+		}
+
+		serial = legacyCRLPath
+		if isDelta {
+			serial = deltaCRLPath
+		}
+
+		contentType = "application/pkix-crl"
+		if strings.HasPrefix(req.Path, "crl/pkcs7") {
+			wrapPKCS7 = true
+			contentType = "application/pkcs7-mime"
+			if strings.HasSuffix(req.Path, "/pem") {
+				pemType = "PKCS7"
+				contentType = "application/x-pem-file"
+			}
+		} else if strings.Contains(req.Path, "pem") {
+			pemType = "X509 CRL"
+			contentType = "application/x-pem-file"
+		} else if req.Path == "cert/crl" || req.Path == "cert/delta-crl" {
+			pemType = "X509 CRL"
+			contentType = ""
+		} else if (req.Path == "crl" || req.Path == "crl/delta") && !base64Format {
+			negotiated = true
+			if acceptsPEM(req, contentType) {
+				pemType = "X509 CRL"
+				contentType = "application/x-pem-file"
+			}
+		}
+	case req.Path == "crl/combined" || req.Path == "crl/combined/pem":
+		contentType = "application/pkix-crl"
+		certificate, retErr = sc.fetchCombinedCRL()
+		if retErr != nil {
+			goto reply
+		}
+
+		if req.Path == "crl/combined/pem" {
+			contentType = "application/x-pem-file"
+			if len(certificate) > 0 {
+				block := pem.Block{
+					Type:  "X509 CRL",
+					Bytes: certificate,
+				}
+				certificate = []byte(strings.TrimSpace(string(pem.EncodeToMemory(&block))))
+			}
+		}
+
+		goto reply
+	case strings.HasSuffix(req.Path, "/pem") || strings.HasSuffix(req.Path, "/raw"):
+		serial = splitContiguousSerial(data.Get("serial").(string))
+
+		modifiedCtx.reqType = ifModifiedCert
+		modifiedCtx.serial = serial
 		ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
 		if err != nil || ret {
 			retErr = err
 			goto reply
 		}
 
-		serial = legacyCRLPath
-		if isDelta {
-			serial = deltaCRLPath
-		}
-
-		contentType = "application/pkix-crl"
-		if strings.Contains(req.Path, "pem") {
-			pemType = "X509 CRL"
-			contentType = "application/x-pem-file"
-		} else if req.Path == "cert/crl" || req.Path == "cert/delta-crl" {
-			pemType = "X509 CRL"
-			contentType = ""
-		}
-	case strings.HasSuffix(req.Path, "/pem") || strings.HasSuffix(req.Path, "/raw"):
-		serial = data.Get("serial").(string)
 		contentType = "application/pkix-cert"
 		if strings.HasSuffix(req.Path, "/pem") {
 			pemType = "CERTIFICATE"
 			contentType = "application/pem-certificate-chain"
 		}
 	default:
-		serial = data.Get("serial").(string)
+		serial = splitContiguousSerial(data.Get("serial").(string))
 		pemType = "CERTIFICATE"
+
+		if missingBehavior, ok := data.GetOk("missing_behavior"); ok {
+			switch missingBehavior.(string) {
+			case "notfound":
+			case "empty-ok":
+				emptyOkMissing = true
+			default:
+				response = logical.ErrorResponse(fmt.Sprintf("unknown missing_behavior %q; must be notfound or empty-ok", missingBehavior))
+				goto reply
+			}
+		}
+
+		modifiedCtx.reqType = ifModifiedCert
+		modifiedCtx.serial = serial
+		ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
+		if err != nil || ret {
+			retErr = err
+			goto reply
+		}
 	}
 	if len(serial) == 0 {
 		response = logical.ErrorResponse("The serial number must be provided")
@@ -495,8 +3954,17 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 
 	// Prefer fetchCAInfo to fetchCertBySerial for CA certificates.
 	if serial == "ca_chain" || serial == "ca" {
-		caInfo, err := sc.fetchCAInfo(defaultRef, ReadOnlyUsage)
+		caInfo, caIssuerId, err := sc.fetchCAInfoWithIssuer(caIssuerRef, ReadOnlyUsage)
 		if err != nil {
+			noCAMessage, retErr = describeMissingCA(sc)
+			if retErr != nil {
+				goto reply
+			}
+			if len(noCAMessage) > 0 {
+				response = logical.ErrorResponse(noCAMessage)
+				goto reply
+			}
+
 			switch err.(type) {
 			case errutil.UserError:
 				response = logical.ErrorResponse(err.Error())
@@ -507,6 +3975,36 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 			}
 		}
 
+		if caIssuerRef != defaultRef {
+			response.AddWarning("no default issuer is set; serving the fallback issuer configured via config/issuers")
+		}
+
+		caGone, err = checkExpiredCA(sc, caInfo.Certificate, len(contentType) == 0, response)
+		if err != nil {
+			retErr = err
+			goto reply
+		}
+
+		if err := b.checkCAExpiryWarning(sc, caIssuerId, caInfo.Certificate, len(contentType) == 0, response); err != nil {
+			retErr = err
+			goto reply
+		}
+
+		if includeExtensions, ok := data.GetOk("include_extensions"); ok && includeExtensions.(bool) && caInfo.Certificate != nil {
+			extensions = getCertExtensionsInfo(caInfo.Certificate)
+		}
+
+		if jwkFormat && caInfo.Certificate != nil {
+			var chain []*x509.Certificate
+			for _, block := range caInfo.GetFullChain() {
+				chain = append(chain, block.Certificate)
+			}
+			jwk, retErr = certToJWK(chain)
+			if retErr != nil {
+				goto reply
+			}
+		}
+
 		if serial == "ca_chain" {
 			rawChain := caInfo.GetFullChain()
 			var chainStr string
@@ -515,12 +4013,43 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 					Type:  "CERTIFICATE",
 					Bytes: ca.Bytes,
 				}
-				chainStr = strings.Join([]string{chainStr, strings.TrimSpace(string(pem.EncodeToMemory(&block)))}, "\n")
+				encoded := strings.TrimSpace(string(pem.EncodeToMemory(&block)))
+				chainStr = strings.Join([]string{chainStr, encoded}, "\n")
+				chainList = append(chainList, encoded)
+				derCertificate = append(derCertificate, ca.Bytes...)
 			}
 			fullChain = []byte(strings.TrimSpace(chainStr))
 			certificate = fullChain
+
+			// The PKCS#7 bundle carries the same certificates, in order.
+			if wrapPKCS7 {
+				var ders [][]byte
+				for _, ca := range rawChain {
+					ders = append(ders, ca.Bytes)
+				}
+				certificate, retErr = wrapCertsInPKCS7(ders)
+				if retErr != nil {
+					goto reply
+				}
+				if len(pemType) != 0 {
+					block := pem.Block{
+						Type:  pemType,
+						Bytes: certificate,
+					}
+					certificate = []byte(strings.TrimSpace(string(pem.EncodeToMemory(&block))))
+				}
+			}
+
+			// The JSON form returns the chain as a list instead, along
+			// with the issuer's certificate alone.
+			if req.Path == "ca_chain/json" {
+				fullChain = nil
+				certificate = []byte(chainList[0])
+			}
 		} else if serial == "ca" {
 			certificate = caInfo.Certificate.Raw
+			derCertificate = certificate
+			fetchedCert = caInfo.Certificate
 
 			if len(pemType) != 0 {
 				block := pem.Block{
@@ -549,16 +4078,71 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 		}
 	}
 	if certEntry == nil {
+		if emptyOkMissing {
+			return &logical.Response{
+				Data: map[string]interface{}{
+					"found": false,
+				},
+			}, nil
+		}
+		// Raw fetches keep their empty 204 for compatibility, while JSON
+		// clients get an explicit not-found error.
+		if len(contentType) == 0 && serial != legacyCRLPath && serial != deltaCRLPath {
+			return certNotFoundResponse(req, serial)
+		}
 		response = nil
 		goto reply
 	}
 
 	certificate = certEntry.Value
+	derCertificate = certificate
+
+	// CRLs are fetched through cert/ as well; only certificates have their
+	// properties reported. An unparsable certificate is still returned as
+	// stored, just without them.
+	if len(contentType) == 0 && serial != legacyCRLPath && serial != deltaCRLPath {
+		if parsedCert, err := x509.ParseCertificate(certificate); err == nil {
+			fetchedCert = parsedCert
+		}
+	}
+
+	if includeExtensions, ok := data.GetOk("include_extensions"); ok && includeExtensions.(bool) {
+		parsedCert, err := x509.ParseCertificate(certificate)
+		if err != nil {
+			response = logical.ErrorResponse(fmt.Sprintf("failed to parse certificate for %s: %s", serial, err))
+			goto reply
+		}
+		extensions = getCertExtensionsInfo(parsedCert)
+	}
+
+	if jwkFormat {
+		parsedCert, err := x509.ParseCertificate(certificate)
+		if err != nil {
+			response = logical.ErrorResponse(fmt.Sprintf("failed to parse certificate for %s: %s", serial, err))
+			goto reply
+		}
+		chain, err := sc.fetchChainForCert(serial, parsedCert)
+		if err != nil {
+			retErr = err
+			goto reply
+		}
+		jwk, retErr = certToJWK(chain)
+		if retErr != nil {
+			goto reply
+		}
+	}
+
+	if wrapPKCS7 {
+		certificate, retErr = wrapCRLInPKCS7(certificate)
+		if retErr != nil {
+			goto reply
+		}
+	}
 
 	if len(pemType) != 0 {
 		block := pem.Block{
 			Type:  pemType,
-			Bytes: certEntry.Value,
+			Bytes: certificate,
 		}
 		// This is convoluted on purpose to ensure that we don't have trailing
 		// newlines via various paths
@@ -583,15 +4167,46 @@ func (b *backend) pathFetchRead(ctx context.Context, req *logical.Request, data
 			return logical.ErrorResponse(fmt.Sprintf("Error decoding revocation entry for serial %s: %s", serial, err)), nil
 		}
 		revocationTime = revInfo.RevocationTime
-		revocationIssuerId = revInfo.CertificateIssuer.String()
+		certIssuerId = revInfo.CertificateIssuer.String()
+		revocationReason, haveRevocationReason = revocationReasonOf(&revInfo)
+		revocationTimeRfc3339 = revocationTimeRFC3339(&revInfo)
+	}
+
+	// The issuer of a certificate is known from its revocation entry or, for
+	// certificates issued since it was recorded, its issuance record; either
+	// may since have been deleted, leaving the certificate uncovered by any
+	// CRL of the mount. Legacy certificates with neither are left unreported.
+	if fetchedCert != nil && !b.useLegacyBundleCaStorage() {
+		if certIssuerId == "" {
+			issuance, err := sc.fetchCertIssuance(serial)
+			if err != nil {
+				retErr = err
+				goto reply
+			}
+			if issuance != nil {
+				certIssuerId = issuance.IssuerID.String()
+			}
+		}
 
-		if !revInfo.RevocationTimeUTC.IsZero() {
-			revocationTimeRfc3339 = revInfo.RevocationTimeUTC.Format(time.RFC3339Nano)
+		if certIssuerId != "" {
+			entry, err := sc.Storage.Get(ctx, issuerPrefix+certIssuerId)
+			if err != nil {
+				retErr = err
+				goto reply
+			}
+			issuerChecked = true
+			issuerPresent = entry != nil
 		}
 	}
 
 reply:
 	switch {
+	case base64Format && retErr == nil && response != nil && !response.IsError():
+		response = &logical.Response{
+			Data: map[string]interface{}{
+				"crl": base64.StdEncoding.EncodeToString(certificate),
+			},
+		}
 	case len(contentType) != 0:
 		response = &logical.Response{
 			Data: map[string]interface{}{
@@ -605,11 +4220,69 @@ reply:
 			}
 		}
 		retErr = nil
-		if len(certificate) > 0 {
+		switch {
+		case caGone:
+			response.Data[logical.HTTPRawBody] = []byte{}
+			response.Data[logical.HTTPStatusCode] = http.StatusGone
+		case len(certificate) > 0:
 			response.Data[logical.HTTPStatusCode] = 200
-		} else {
+		case len(noCAMessage) > 0:
+			// Rather than an empty 204, tell raw clients why there's
+			// nothing to serve.
+			response.Data[logical.HTTPContentType] = "text/plain"
+			response.Data[logical.HTTPRawBody] = []byte(noCAMessage)
+			response.Data[logical.HTTPStatusCode] = http.StatusNotFound
+		default:
 			response.Data[logical.HTTPStatusCode] = 204
 		}
+
+		// CRLs may be large, so they are compressed for clients accepting
+		// gzip.
+		isCRL := serial == legacyCRLPath || serial == deltaCRLPath
+		gzipBody := isCRL && response.Data[logical.HTTPStatusCode] == 200 && acceptsGzip(req)
+
+		// The representation of the paths negotiating it depends on the
+		// Accept header, which caches must then key on.
+		response.Headers = make(map[string][]string)
+		if negotiated {
+			response.Headers[headerVary] = []string{headerAccept}
+		}
+
+		// CA certificates, chains and CRLs carry an entity tag, letting
+		// caches revalidate them across issuer rotations. Each PKCS#7, PEM
+		// and compressed representation is tagged distinctly from the DER
+		// one, as their bodies differ.
+		isCAOrCRL := serial == "ca" || serial == "ca_chain" || isCRL
+		if isCAOrCRL && response.Data[logical.HTTPStatusCode] == 200 && len(derCertificate) > 0 {
+			etag := strings.TrimSuffix(derETag(derCertificate), `"`)
+			if wrapPKCS7 {
+				etag += "-pkcs7"
+			}
+			if contentType == "application/pem-certificate-chain" || contentType == "application/x-pem-file" {
+				etag += "-pem"
+			}
+			if gzipBody {
+				etag += "-gzip"
+			}
+			etag += `"`
+			response.Headers[headerETag] = []string{etag}
+			if ifNoneMatchMatches(req, etag) {
+				response.Data = map[string]interface{}{
+					logical.HTTPContentType: "",
+					logical.HTTPStatusCode:  http.StatusNotModified,
+				}
+				gzipBody = false
+			}
+		}
+
+		if gzipBody {
+			compressed, err := gzipBytes(response.Data[logical.HTTPRawBody].([]byte))
+			if err != nil {
+				return nil, fmt.Errorf("failed to compress CRL: %w", err)
+			}
+			response.Data[logical.HTTPRawBody] = compressed
+			response.Headers[headerContentEncoding] = []string{"gzip"}
+		}
 	case retErr != nil:
 		response = nil
 		return
@@ -623,18 +4296,348 @@ reply:
 		response.Data["revocation_time_rfc3339"] = revocationTimeRfc3339
 		// Only output this field if we have a value for it as it doesn't make sense for a
 		// bunch of code paths that go through here
-		if revocationIssuerId != "" {
-			response.Data["issuer_id"] = revocationIssuerId
+		if certIssuerId != "" {
+			response.Data["issuer_id"] = certIssuerId
+		}
+		if issuerChecked {
+			response.Data["issuer_present"] = issuerPresent
+		}
+
+		// Revocations recorded before reasons were stored omit them, rather
+		// than reporting them as unspecified.
+		if haveRevocationReason {
+			response.Data["revocation_reason"] = revocationReason
+			response.Data["revocation_reason_string"] = revocationReasonNames[revocationReason]
 		}
 
 		if len(fullChain) > 0 {
 			response.Data["ca_chain"] = string(fullChain)
 		}
+
+		if req.Path == "ca_chain/json" {
+			response.Data["ca_chain"] = chainList
+		}
+
+		if extensions != nil {
+			response.Data["extensions"] = extensions
+		}
+
+		if jwk != nil {
+			response.Data["jwk"] = jwk
+		}
+
+		if emptyOkMissing {
+			response.Data["found"] = true
+		}
+
+		// Named as in config/urls, so that both can be compared directly.
+		if includeURLs, ok := data.GetOk("include_urls"); ok && includeURLs.(bool) && fetchedCert != nil {
+			response.Data["ocsp_servers"] = append([]string{}, fetchedCert.OCSPServer...)
+			response.Data["issuing_certificates"] = append([]string{}, fetchedCert.IssuingCertificateURL...)
+			response.Data["crl_distribution_points"] = append([]string{}, fetchedCert.CRLDistributionPoints...)
+		}
+
+		if fetchedCert != nil {
+			response = addWarnings(response, weakCertWarnings(fetchedCert))
+
+			// Taken from the certificate rather than the request, whose
+			// serial may be in any of the accepted encodings.
+			response.Data["serial_number"] = serialFromCert(fetchedCert)
+			response.Data["serial_number_decimal"] = fetchedCert.SerialNumber.String()
+			response.Data["signature_algorithm"] = fetchedCert.SignatureAlgorithm.String()
+			response.Data["is_ca"] = fetchedCert.IsCA
+			response.Data["max_path_len"] = certMaxPathLen(fetchedCert)
+			response.Data["validity_state"] = certValidityState(fetchedCert, time.Now())
+		}
+
+		// Both encodings derive from the single load of the certificate.
+		if strings.HasSuffix(req.Path, "/both") {
+			response.Data["certificate_pem"] = string(certificate)
+			response.Data["certificate_der_base64"] = base64.StdEncoding.EncodeToString(derCertificate)
+		}
+
+		response = addStructuredWarnings(response, data)
 	}
 
 	return
 }
 
+var certExtensionNames = map[string]string{
+	"2.5.29.14":         "subject_key_identifier",
+	"2.5.29.15":         "key_usage",
+	"2.5.29.17":         "subject_alt_name",
+	"2.5.29.19":         "basic_constraints",
+	"2.5.29.30":         "name_constraints",
+	"2.5.29.31":         "crl_distribution_points",
+	"2.5.29.32":         "certificate_policies",
+	"2.5.29.35":         "authority_key_identifier",
+	"2.5.29.37":         "ext_key_usage",
+	"1.3.6.1.5.5.7.1.1": "authority_info_access",
+}
+
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "DigitalSignature"},
+	{x509.KeyUsageContentCommitment, "ContentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "KeyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "DataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "KeyAgreement"},
+	{x509.KeyUsageCertSign, "CertSign"},
+	{x509.KeyUsageCRLSign, "CRLSign"},
+	{x509.KeyUsageEncipherOnly, "EncipherOnly"},
+	{x509.KeyUsageDecipherOnly, "DecipherOnly"},
+}
+
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "Any",
+	x509.ExtKeyUsageServerAuth:                     "ServerAuth",
+	x509.ExtKeyUsageClientAuth:                     "ClientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "CodeSigning",
+	x509.ExtKeyUsageEmailProtection:                "EmailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "IPSECEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "IPSECTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "IPSECUser",
+	x509.ExtKeyUsageTimeStamping:                   "TimeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "MicrosoftServerGatedCrypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "NetscapeServerGatedCrypto",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "MicrosoftCommercialCodeSigning",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "MicrosoftKernelCodeSigning",
+}
+
+// keyUsageLabels returns the names of the certificate's key usages.
+func keyUsageLabels(cert *x509.Certificate) []string {
+	usages := []string{}
+	for _, usage := range keyUsageNames {
+		if cert.KeyUsage&usage.usage != 0 {
+			usages = append(usages, usage.name)
+		}
+	}
+	return usages
+}
+
+// extKeyUsageLabels returns the names of the certificate's extended key
+// usages, followed by the OIDs of any not known to crypto/x509.
+func extKeyUsageLabels(cert *x509.Certificate) []string {
+	usages := []string{}
+	for _, usage := range cert.ExtKeyUsage {
+		if usageName, ok := extKeyUsageNames[usage]; ok {
+			usages = append(usages, usageName)
+		}
+	}
+	for _, usage := range cert.UnknownExtKeyUsage {
+		usages = append(usages, usage.String())
+	}
+	return usages
+}
+
+// getCertExtensionsInfo returns a description of each of the certificate's
+// extensions, in order. Well-known extensions have their value decoded from
+// the parsed certificate; all others are returned base64-encoded.
+func getCertExtensionsInfo(cert *x509.Certificate) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(cert.Extensions))
+	for _, ext := range cert.Extensions {
+		oid := ext.Id.String()
+		info := map[string]interface{}{
+			"oid":      oid,
+			"critical": ext.Critical,
+		}
+
+		name, known := certExtensionNames[oid]
+		if known {
+			info["name"] = name
+		}
+
+		switch oid {
+		case "2.5.29.14":
+			info["value"] = certutil.GetHexFormatted(cert.SubjectKeyId, ":")
+		case "2.5.29.15":
+			info["value"] = keyUsageLabels(cert)
+		case "2.5.29.17":
+			ipAddresses := make([]string, 0, len(cert.IPAddresses))
+			for _, ip := range cert.IPAddresses {
+				ipAddresses = append(ipAddresses, ip.String())
+			}
+			uris := make([]string, 0, len(cert.URIs))
+			for _, uri := range cert.URIs {
+				uris = append(uris, uri.String())
+			}
+			info["value"] = map[string]interface{}{
+				"dns_names":       cert.DNSNames,
+				"email_addresses": cert.EmailAddresses,
+				"ip_addresses":    ipAddresses,
+				"uris":            uris,
+			}
+		case "2.5.29.19":
+			info["value"] = map[string]interface{}{
+				"is_ca":        cert.IsCA,
+				"max_path_len": cert.MaxPathLen,
+			}
+		case "2.5.29.30":
+			info["value"] = map[string]interface{}{
+				"permitted_dns_domains":   cert.PermittedDNSDomains,
+				"excluded_dns_domains":    cert.ExcludedDNSDomains,
+				"permitted_email_domains": cert.PermittedEmailAddresses,
+				"excluded_email_domains":  cert.ExcludedEmailAddresses,
+				"permitted_uri_domains":   cert.PermittedURIDomains,
+				"excluded_uri_domains":    cert.ExcludedURIDomains,
+			}
+		case "2.5.29.31":
+			info["value"] = cert.CRLDistributionPoints
+		case "2.5.29.32":
+			policies := make([]string, 0, len(cert.PolicyIdentifiers))
+			for _, policy := range cert.PolicyIdentifiers {
+				policies = append(policies, policy.String())
+			}
+			info["value"] = policies
+		case "2.5.29.35":
+			info["value"] = certutil.GetHexFormatted(cert.AuthorityKeyId, ":")
+		case "2.5.29.37":
+			info["value"] = extKeyUsageLabels(cert)
+		case "1.3.6.1.5.5.7.1.1":
+			info["value"] = map[string]interface{}{
+				"ocsp_servers":             cert.OCSPServer,
+				"issuing_certificate_urls": cert.IssuingCertificateURL,
+			}
+		default:
+			info["value"] = base64.StdEncoding.EncodeToString(ext.Value)
+		}
+
+		ret = append(ret, info)
+	}
+
+	return ret
+}
+
+// describeMissingCA returns a descriptive message when fetching the default
+// CA failed because none is configured, or an empty string otherwise.
+func describeMissingCA(sc *storageContext) (string, error) {
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return "", nil
+	}
+
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return "", err
+	}
+	if len(config.DefaultIssuerId) > 0 {
+		return "", nil
+	}
+
+	issuers, err := sc.listIssuers()
+	if err != nil {
+		return "", err
+	}
+	if len(issuers) == 0 {
+		return "no CA configured: this mount has no issuers; generate a root, or import a CA certificate and key, first", nil
+	}
+	return "no CA configured: this mount has issuers but no default or fallback issuer; set one via config/issuers", nil
+}
+
+// fetchIssuerRef returns the reference to the issuer served by the ca and
+// ca_chain fetch paths: the default issuer or, while none is set, the
+// fallback issuer from the issuers configuration.
+func fetchIssuerRef(sc *storageContext) (string, error) {
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return defaultRef, nil
+	}
+
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return "", err
+	}
+	if len(config.DefaultIssuerId) == 0 && len(config.FallbackIssuerId) > 0 {
+		return config.FallbackIssuerId.String(), nil
+	}
+
+	return defaultRef, nil
+}
+
+// checkExpiredCA applies the expired CA handling from the issuers
+// configuration to a fetched CA certificate. On JSON responses, a warning is
+// added to the response when enabled; on raw responses, it returns whether
+// the grace period has elapsed and the CA should no longer be served.
+func checkExpiredCA(sc *storageContext, caCert *x509.Certificate, isJSON bool, response *logical.Response) (bool, error) {
+	now := time.Now()
+	if caCert == nil || now.Before(caCert.NotAfter) {
+		return false, nil
+	}
+
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if isJSON {
+		if config.ExpiredCAWarning {
+			response.AddWarning(fmt.Sprintf("the CA certificate expired at %s", caCert.NotAfter.Format(time.RFC3339)))
+		}
+		return false, nil
+	}
+
+	if len(config.ExpiredCAGracePeriod) == 0 {
+		return false, nil
+	}
+
+	grace, err := parseutil.ParseDurationSecond(config.ExpiredCAGracePeriod)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse expired_ca_grace_period: %w", err)
+	}
+
+	return now.After(caCert.NotAfter.Add(grace)), nil
+}
+
+// caExpiryWarningLogInterval bounds how often a raw CA fetch logs the expiry
+// warning for a single issuer, as these paths are unauthenticated.
+const caExpiryWarningLogInterval = 1 * time.Hour
+
+// checkCAExpiryWarning warns when a fetched CA certificate expires within
+// its issuer's expiry_warning_threshold. The warning is added to JSON
+// responses; raw responses must stay unchanged, so it is only logged, at
+// most once per issuer every caExpiryWarningLogInterval.
+func (b *backend) checkCAExpiryWarning(sc *storageContext, issuerId issuerID, caCert *x509.Certificate, isJSON bool, response *logical.Response) error {
+	if caCert == nil || issuerId == legacyBundleShimID {
+		return nil
+	}
+
+	issuer, err := sc.fetchIssuerById(issuerId)
+	if err != nil {
+		return err
+	}
+
+	remaining := time.Until(caCert.NotAfter)
+	if issuer.ExpiryWarningThreshold <= 0 || remaining <= 0 || remaining > issuer.ExpiryWarningThreshold {
+		return nil
+	}
+
+	warning := fmt.Sprintf("issuing CA expires in %d days", int(remaining.Hours()/24))
+	if isJSON {
+		response.AddWarning(warning)
+	} else if b.shouldLogCAExpiryWarning(issuerId, time.Now()) {
+		b.Logger().Warn(warning, "issuer_id", issuerId, "not_after", caCert.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// shouldLogCAExpiryWarning reports whether the expiry warning for this
+// issuer may be logged now, recording the time if so.
+func (b *backend) shouldLogCAExpiryWarning(issuerId issuerID, now time.Time) bool {
+	for {
+		last, loaded := b.caExpiryWarningLogged.LoadOrStore(issuerId, now)
+		if !loaded {
+			return true
+		}
+		if now.Sub(last.(time.Time)) < caExpiryWarningLogInterval {
+			return false
+		}
+		if b.caExpiryWarningLogged.CompareAndSwap(issuerId, last, now) {
+			return true
+		}
+	}
+}
+
 const pathFetchHelpSyn = `
 Fetch a CA, CRL, CA Chain, or non-revoked certificate.
 `
@@ -648,3 +4651,120 @@ Using "ca_chain" as the value fetches the certificate authority trust chain in P
 
 Otherwise, specify a serial number to fetch the specified certificate. Add "/raw" to get just the certificate in DER form, "/raw/pem" to get the PEM encoded certificate.
 `
+
+const pathFetchCertFullChainHelpSyn = `
+Fetch a certificate along with the CA chain of its issuer, as a PEM bundle.
+`
+
+const pathFetchCertFullChainHelpDesc = `
+Returns the certificate with the given serial number, followed by the CA chain
+of the issuer which signed it, as a single raw PEM body. The issuer is the one
+recorded for the certificate at revocation, or else at issuance; certificates
+without a recorded issuer, or whose issuer was deleted, are refused.
+`
+
+const pathFetchLatestCertHelpSyn = `
+Fetch the most recently issued valid certificate for a common name.
+`
+
+const pathFetchLatestCertHelpDesc = `
+This returns the unexpired, non-revoked certificate with the latest NotBefore
+whose subject common name matches the requested common_name. When match_sans
+is set, certificates listing the name as a DNS Subject Alternative Name are
+also considered and are preferred over subject-only matches.
+`
+
+const pathFetchListCertNamesHelpSyn = `
+List issued certificates along with their common names.
+`
+
+const pathFetchListCertNamesHelpDesc = `
+This returns the serial numbers of issued certificates, like the certs listing,
+along with a key_info map holding the subject common name of each. Only the
+certificate fields up to its subject are decoded, making this lighter than
+certs/detailed. Certificates which fail to decode are omitted from keys and
+reported under parse_errors instead.
+`
+
+const pathFetchListExpiredCertsHelpSyn = `
+List the serial numbers of expired certificates.
+`
+
+const pathFetchListExpiredCertsHelpDesc = `
+This returns the serial numbers of issued certificates which expired before
+the time of the request, or before as_of when given, along with a key_info map
+holding the not_after time of each. It previews the certificates a tidy with
+tidy_cert_store would consider; as tidy only removes certificates expired for
+longer than its safety_buffer, set as_of to the current time less that buffer
+to list exactly those. The limit applies to the expired certificates listed.
+`
+
+const pathFetchFindCertsHelpSyn = `
+Find issued certificates by subject key identifier.
+`
+
+const pathFetchFindCertsHelpDesc = `
+This returns the serial numbers of the stored certificates whose subject key
+identifier matches the requested ski, letting a key identifier seen in a TLS
+handshake be mapped back to its certificates. Storage is scanned on the
+server, so only the matching serial numbers are returned.
+`
+
+const pathFetchCTExportHelpSyn = `
+Export issued certificates in a format suited to transparency monitors.
+`
+
+const pathFetchCTExportHelpDesc = `
+This returns a page of stored certificates, each as base64-encoded DER along
+with its subject, issuer, Subject Alternative Names and validity period, for
+feeding an in-house certificate transparency or monitoring pipeline. Pass the
+returned next_cursor as after to fetch the following page; an empty cursor
+means all certificates were returned.
+`
+
+const pathFetchRevocationStatusHelpSyn = `
+Fetch the revocation status of a certificate.
+`
+
+const pathFetchRevocationStatusHelpDesc = `
+This returns whether the certificate with the given serial number is revoked,
+along with the time and reason of its revocation, without the certificate
+itself. Serial numbers without a revocation entry, including unknown ones,
+are reported as not revoked.
+`
+
+const pathFetchCertStatusHelpSyn = `
+Fetch a certificate and verify it against the mount's issuers.
+`
+
+const pathFetchCertStatusHelpDesc = `
+This returns the certificate with the given serial number along with whether it
+is still valid: chain_valid reports whether it verifies against the CA chain of
+the mount's issuer which signed it, while expired and revoked report its
+validity period and revocation status. The reasons it is not valid, if any,
+are listed in problems. Unknown serial numbers return a 404.
+`
+
+const pathFetchRevocationEntryHelpSyn = `
+Fetch the stored revocation entry of a certificate.
+`
+
+const pathFetchRevocationEntryHelpDesc = `
+This returns every field of the revocation entry stored for the certificate
+with the given serial number as persisted, including the recorded issuer, both
+representations of the revocation time and internal flags such as
+reason_recorded and ocsp_only, for debugging revocation discrepancies.
+Certificates without a revocation entry are reported with revoked=false.
+`
+
+const pathFetchEscrowedKeyHelpSyn = `
+Fetch the escrowed private key of a certificate.
+`
+
+const pathFetchEscrowedKeyHelpDesc = `
+This returns the private key generated by the mount for a certificate issued
+under a role with escrow_private_key. Keys are only escrowed when the mount
+generates them, and are removed when tidy removes the certificate. Each
+retrieval is logged by the mount; access to this path should be limited to
+the few callers entitled to recover keys.
+`
@@ -4,6 +4,7 @@
 package pki
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/openbao/openbao/sdk/v2/framework"
 	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
@@ -156,6 +158,145 @@ func pathGetUnauthedIssuer(b *backend) *framework.Path {
 	return buildPathGetIssuer(b, pattern, displayAttrs)
 }
 
+func pathGetIssuerInfo(b *backend) *framework.Path {
+	fields := map[string]*framework.FieldSchema{}
+	fields = addIssuerRefField(fields)
+
+	return &framework.Path{
+		Pattern: "issuer/" + framework.GenericNameRegex(issuerRefParam) + "/info$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKIIssuer,
+			OperationSuffix: "info",
+		},
+
+		Fields: fields,
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathGetIssuerInfo,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Issuer Id`,
+								Required:    true,
+							},
+							"issuer_name": {
+								Type:        framework.TypeString,
+								Description: `Issuer Name`,
+								Required:    true,
+							},
+							"is_default": {
+								Type:        framework.TypeBool,
+								Description: `Whether this is the mount's default issuer`,
+								Required:    true,
+							},
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `Certificate`,
+								Required:    true,
+							},
+							"key_type": {
+								Type:        framework.TypeString,
+								Description: `Type of the issuer's key: rsa, ec or ed25519`,
+								Required:    true,
+							},
+							"key_bits": {
+								Type:        framework.TypeInt,
+								Description: `Size of the issuer's key in bits`,
+								Required:    true,
+							},
+							"not_before": {
+								Type:        framework.TypeTime,
+								Description: `Start of the issuer certificate's validity period`,
+								Required:    true,
+							},
+							"not_after": {
+								Type:        framework.TypeTime,
+								Description: `End of the issuer certificate's validity period`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathGetIssuerInfoHelpSyn,
+		HelpDescription: pathGetIssuerInfoHelpDesc,
+	}
+}
+
+func (b *backend) pathGetIssuerInfo(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("Can not get issuer until migration has completed"), nil
+	}
+
+	issuerName := getIssuerRef(data)
+	if len(issuerName) == 0 {
+		return logical.ErrorResponse("missing issuer reference"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return nil, err
+	}
+	if issuerName == defaultRef && len(config.DefaultIssuerId) == 0 {
+		return logical.ErrorResponse("no default issuer is configured; set one with config/issuers"), nil
+	}
+
+	ref, err := sc.resolveIssuerReference(issuerName)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+
+	issuer, err := sc.fetchIssuerById(ref)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := issuer.GetCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	keyType, keyBits := certKeyTypeAndBits(cert)
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"issuer_id":   issuer.ID,
+			"issuer_name": issuer.Name,
+			"is_default":  issuer.ID == config.DefaultIssuerId,
+			"certificate": issuer.Certificate,
+			"key_type":    keyType,
+			"key_bits":    keyBits,
+			"not_before":  cert.NotBefore,
+			"not_after":   cert.NotAfter,
+		},
+	}, nil
+}
+
+const (
+	pathGetIssuerInfoHelpSyn  = `Fetch an issuer's certificate along with its identity and key details.`
+	pathGetIssuerInfoHelpDesc = `
+This returns, in a single JSON response, the issuer's ID and name, whether it
+is the mount's default issuer, its PEM certificate, the type and size of its
+key, and its validity period.
+
+:ref can be either the literal value "default", in which case /config/issuers
+will be consulted for the present default issuer, an identifier of an issuer,
+or its assigned name value. Reading /issuer/default/info thus returns whichever
+issuer is currently the default, without knowing its identifier.
+`
+)
+
 func buildPathIssuer(b *backend, pattern string, displayAttrs *framework.DisplayAttributes) *framework.Path {
 	fields := map[string]*framework.FieldSchema{}
 	fields = addIssuerRefNameFields(fields)
@@ -228,6 +369,14 @@ and using '{{cluster_aia_path}}' requires /config/cluster's 'aia_path' member
 to be set on all PR secondary clusters.`,
 		Default: false,
 	}
+	fields["expiry_warning_threshold"] = &framework.FieldSchema{
+		Type: framework.TypeDurationSecond,
+		Description: `When this issuer is served as the mount's CA by the
+ca and ca_chain fetch paths and expires within this duration, JSON responses
+carry a warning and raw responses log it. Zero, the default, disables the
+warning.`,
+		Default: 0,
+	}
 
 	updateIssuerSchema := map[int][]framework.Response{
 		http.StatusOK: {{
@@ -316,6 +465,11 @@ to be set on all PR secondary clusters.`,
 					Description: `Whether or not templating is enabled for AIA fields`,
 					Required:    false,
 				},
+				"expiry_warning_threshold": {
+					Type:        framework.TypeInt64,
+					Description: `Seconds before expiry from which fetches of this issuer as the CA warn about it`,
+					Required:    false,
+				},
 			},
 		}},
 	}
@@ -367,6 +521,15 @@ to be set on all PR secondary clusters.`,
 func buildPathGetIssuer(b *backend, pattern string, displayAttrs *framework.DisplayAttributes) *framework.Path {
 	fields := map[string]*framework.FieldSchema{}
 	fields = addIssuerRefField(fields)
+	fields["format"] = &framework.FieldSchema{
+		Type: framework.TypeString,
+		Description: `Additional encoding of the issuer on JSON fetches:
+"pem" (the default) returns only the PEM certificate, while "jwk" also
+returns its public key as a JWK with the CA chain in x5c.`,
+		Default:       fetchFormatPEM,
+		AllowedValues: []interface{}{fetchFormatPEM, fetchFormatJWK},
+		Query:         true,
+	}
 
 	getIssuerSchema := map[int][]framework.Response{
 		http.StatusNotModified: {{
@@ -395,6 +558,11 @@ func buildPathGetIssuer(b *backend, pattern string, displayAttrs *framework.Disp
 					Description: `CA Chain`,
 					Required:    true,
 				},
+				"jwk": {
+					Type:        framework.TypeMap,
+					Description: `The issuer's public key as a JWK, with its CA chain in x5c, when format=jwk`,
+					Required:    false,
+				},
 			},
 		}},
 	}
@@ -478,6 +646,7 @@ func respondReadIssuer(issuer *issuerEntry) (*logical.Response, error) {
 		"crl_distribution_points":        []string{},
 		"delta_crl_distribution_points":  []string{},
 		"ocsp_servers":                   []string{},
+		"expiry_warning_threshold":       int64(issuer.ExpiryWarningThreshold.Seconds()),
 	}
 
 	if issuer.Revoked {
@@ -605,6 +774,11 @@ func (b *backend) pathUpdateIssuer(ctx context.Context, req *logical.Request, da
 		return logical.ErrorResponse(fmt.Sprintf("invalid URL found in Authority Information Access (AIA) parameter ocsp_servers: %s", badURL)), nil
 	}
 
+	expiryWarningThreshold := time.Duration(data.Get("expiry_warning_threshold").(int)) * time.Second
+	if expiryWarningThreshold < 0 {
+		return logical.ErrorResponse("expiry_warning_threshold must not be negative"), nil
+	}
+
 	modified := false
 
 	var oldName string
@@ -648,6 +822,11 @@ func (b *backend) pathUpdateIssuer(ctx context.Context, req *logical.Request, da
 		modified = true
 	}
 
+	if expiryWarningThreshold != issuer.ExpiryWarningThreshold {
+		issuer.ExpiryWarningThreshold = expiryWarningThreshold
+		modified = true
+	}
+
 	if issuer.AIAURIs == nil && (len(issuerCertificates) > 0 || len(crlDistributionPoints) > 0 || len(ocspServers) > 0) {
 		issuer.AIAURIs = &aiaConfigEntry{}
 	}
@@ -893,6 +1072,18 @@ func (b *backend) pathPatchIssuer(ctx context.Context, req *logical.Request, dat
 		}
 	}
 
+	// Expiry warning changes
+	if rawThreshold, ok := data.GetOk("expiry_warning_threshold"); ok {
+		expiryWarningThreshold := time.Duration(rawThreshold.(int)) * time.Second
+		if expiryWarningThreshold < 0 {
+			return logical.ErrorResponse("expiry_warning_threshold must not be negative"), nil
+		}
+		if expiryWarningThreshold != issuer.ExpiryWarningThreshold {
+			issuer.ExpiryWarningThreshold = expiryWarningThreshold
+			modified = true
+		}
+	}
+
 	// AIA access changes.
 	if issuer.AIAURIs == nil {
 		issuer.AIAURIs = &aiaConfigEntry{}
@@ -1031,10 +1222,23 @@ func (b *backend) pathGetRawIssuer(ctx context.Context, req *logical.Request, da
 	}
 
 	sc := b.makeStorageContext(ctx, req.Storage)
-	ref, err := sc.resolveIssuerReference(issuerName)
+	config, err := sc.getIssuersConfig()
 	if err != nil {
 		return nil, err
 	}
+	if issuerName == defaultRef && len(config.DefaultIssuerId) == 0 {
+		return logical.ErrorResponse("no default issuer is configured; set one with config/issuers"), nil
+	}
+
+	ref, err := sc.resolveIssuerReference(issuerName)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
 	if ref == "" {
 		return logical.ErrorResponse("unable to resolve issuer id for reference: " + issuerName), nil
 	}
@@ -1067,7 +1271,7 @@ func (b *backend) pathGetRawIssuer(ctx context.Context, req *logical.Request, da
 	if strings.HasSuffix(req.Path, "/der") {
 		pemBlock, _ := pem.Decode(certificate)
 		if pemBlock == nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to decode certificate of issuer %v", ref)
 		}
 
 		certificate = pemBlock.Bytes
@@ -1087,14 +1291,43 @@ func (b *backend) pathGetRawIssuer(ctx context.Context, req *logical.Request, da
 			},
 		}, nil
 	} else {
-		return &logical.Response{
+		resp := &logical.Response{
 			Data: map[string]interface{}{
 				"certificate": string(certificate),
 				"ca_chain":    issuer.CAChain,
 				"issuer_id":   issuer.ID,
 				"issuer_name": issuer.Name,
 			},
-		}, nil
+		}
+
+		format := data.Get("format").(string)
+		if format != fetchFormatPEM && format != fetchFormatJWK {
+			return logical.ErrorResponse(fmt.Sprintf("unknown format %q; must be %s or %s", format, fetchFormatPEM, fetchFormatJWK)), nil
+		}
+
+		if format == fetchFormatJWK {
+			issuerCert, err := issuer.GetCertificate()
+			if err != nil {
+				return nil, err
+			}
+			caChain, err := parseCertChain(issuer.CAChain)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse chain of issuer %v: %w", issuer.ID, err)
+			}
+
+			// The stored CA chain normally starts with the issuer itself.
+			chain := []*x509.Certificate{issuerCert}
+			if len(caChain) > 0 && bytes.Equal(caChain[0].Raw, issuerCert.Raw) {
+				caChain = caChain[1:]
+			}
+			jwk, err := certToJWK(append(chain, caChain...))
+			if err != nil {
+				return nil, err
+			}
+			resp.Data["jwk"] = jwk
+		}
+
+		return resp, nil
 	}
 }
 
@@ -1281,18 +1514,28 @@ func (b *backend) pathGetIssuerCRL(ctx context.Context, req *logical.Request, da
 		crlType = ifModifiedDeltaCRL
 	}
 
-	ret, err := sendNotModifiedResponseIfNecessary(&IfModifiedSinceHelper{req: req, reqType: crlType}, sc, response)
+	// Resolve the issuer ahead of the If-Modified-Since check, so that an
+	// unknown reference fails rather than reporting an unmodified CRL.
+	crlPath, err := sc.resolveIssuerCRLPath(issuerName)
 	if err != nil {
+		if _, ok := err.(errutil.UserError); ok {
+			return logical.ErrorResponse(err.Error()), nil
+		}
 		return nil, err
 	}
-	if ret {
-		return response, nil
-	}
 
-	crlPath, err := sc.resolveIssuerCRLPath(issuerName)
+	modifiedCtx := &IfModifiedSinceHelper{
+		req:       req,
+		reqType:   crlType,
+		issuerRef: issuerID(issuerName),
+	}
+	ret, err := sendNotModifiedResponseIfNecessary(modifiedCtx, sc, response)
 	if err != nil {
 		return nil, err
 	}
+	if ret {
+		return response, nil
+	}
 
 	if strings.Contains(req.Path, "delta") {
 		crlPath += deltaCRLPathSuffix
@@ -1367,3 +1610,289 @@ or its assigned name value.
  - /issuer/:ref/crl/DER contains the raw DER-encoded (binary) CRL.
 `
 )
+
+func pathGetIssuerRevocationConfig(b *backend) *framework.Path {
+	fields := map[string]*framework.FieldSchema{}
+	fields = addIssuerRefField(fields)
+
+	return &framework.Path{
+		Pattern: "issuer/" + framework.GenericNameRegex(issuerRefParam) + "/revocation-config$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKIIssuer,
+			OperationSuffix: "revocation-config",
+		},
+
+		Fields: fields,
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathGetIssuerRevocationConfig,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Issuer Id`,
+								Required:    true,
+							},
+							"issuer_name": {
+								Type:        framework.TypeString,
+								Description: `Issuer Name`,
+								Required:    true,
+							},
+							"aia_source": {
+								Type:        framework.TypeString,
+								Description: `Where the AIA URLs come from: "issuer" when set on the issuer, "mount" when inherited from config/urls`,
+								Required:    true,
+							},
+							"issuing_certificates": {
+								Type:        framework.TypeStringSlice,
+								Description: `Effective Issuing Certificates URLs`,
+								Required:    true,
+							},
+							"crl_distribution_points": {
+								Type:        framework.TypeStringSlice,
+								Description: `Effective CRL Distribution Points`,
+								Required:    true,
+							},
+							"delta_crl_distribution_points": {
+								Type:        framework.TypeStringSlice,
+								Description: `Effective Delta CRL Distribution Points`,
+								Required:    true,
+							},
+							"ocsp_servers": {
+								Type:        framework.TypeStringSlice,
+								Description: `Effective OCSP Servers`,
+								Required:    true,
+							},
+							"crl_signing": {
+								Type:        framework.TypeBool,
+								Description: `Whether the issuer may sign CRLs`,
+								Required:    true,
+							},
+							"crl_disabled": {
+								Type:        framework.TypeBool,
+								Description: `Whether CRL building is disabled on the mount`,
+								Required:    true,
+							},
+							"crl_expiry": {
+								Type:        framework.TypeString,
+								Description: `Validity period of generated CRLs`,
+								Required:    true,
+							},
+							"auto_rebuild": {
+								Type:        framework.TypeBool,
+								Description: `Whether CRLs are rebuilt periodically rather than on each revocation`,
+								Required:    true,
+							},
+							"auto_rebuild_grace_period": {
+								Type:        framework.TypeString,
+								Description: `Time before CRL expiry at which CRLs are automatically rebuilt`,
+								Required:    true,
+							},
+							"enable_delta": {
+								Type:        framework.TypeBool,
+								Description: `Whether delta CRLs are built`,
+								Required:    true,
+							},
+							"delta_rebuild_interval": {
+								Type:        framework.TypeString,
+								Description: `Interval at which delta CRLs are rebuilt`,
+								Required:    true,
+							},
+							"ocsp_signing": {
+								Type:        framework.TypeBool,
+								Description: `Whether the issuer may sign OCSP responses`,
+								Required:    true,
+							},
+							"ocsp_disabled": {
+								Type:        framework.TypeBool,
+								Description: `Whether the OCSP responder is disabled on the mount`,
+								Required:    true,
+							},
+							"ocsp_expiry": {
+								Type:        framework.TypeString,
+								Description: `Validity period of OCSP responses`,
+								Required:    true,
+							},
+							"ocsp_signer_issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Issuer signing OCSP responses for certificates of this issuer; empty if none can`,
+								Required:    true,
+							},
+							"ocsp_signer_delegated": {
+								Type:        framework.TypeBool,
+								Description: `Whether OCSP responses are signed by another issuer sharing this issuer's subject and key`,
+								Required:    true,
+							},
+							"revocation_signature_algorithm": {
+								Type:        framework.TypeString,
+								Description: `Signature algorithm used for CRLs and OCSP responses`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathGetIssuerRevocationConfigHelpSyn,
+		HelpDescription: pathGetIssuerRevocationConfigHelpDesc,
+	}
+}
+
+func (b *backend) pathGetIssuerRevocationConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("Can not get issuer until migration has completed"), nil
+	}
+
+	issuerName := getIssuerRef(data)
+	if len(issuerName) == 0 {
+		return logical.ErrorResponse("missing issuer reference"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	ref, err := sc.resolveIssuerReference(issuerName)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		return logical.ErrorResponse("unable to resolve issuer id for reference: " + issuerName), nil
+	}
+
+	issuer, err := sc.fetchIssuerById(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	urls, err := issuer.GetAIAURLs(sc)
+	if err != nil {
+		return logical.ErrorResponse(fmt.Sprintf("unable to resolve AIA URLs for issuer: %v", err)), nil
+	}
+
+	aiaSource := "mount"
+	if issuer.AIAURIs != nil && (len(issuer.AIAURIs.IssuingCertificates) > 0 || len(issuer.AIAURIs.CRLDistributionPoints) > 0 || len(issuer.AIAURIs.OCSPServers) > 0) {
+		aiaSource = "issuer"
+	}
+
+	config, err := sc.getRevocationConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := findOcspSigner(sc, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	revSigAlgStr, present := certutil.InvSignatureAlgorithmNames[issuer.RevocationSigAlg]
+	if !present {
+		revSigAlgStr = ""
+		if issuer.RevocationSigAlg != x509.UnknownSignatureAlgorithm {
+			revSigAlgStr = issuer.RevocationSigAlg.String()
+		}
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"issuer_id":                      issuer.ID,
+			"issuer_name":                    issuer.Name,
+			"aia_source":                     aiaSource,
+			"issuing_certificates":           nonNilStrings(urls.IssuingCertificates),
+			"crl_distribution_points":        nonNilStrings(urls.CRLDistributionPoints),
+			"delta_crl_distribution_points":  nonNilStrings(urls.DeltaCRLDistributionPoints),
+			"ocsp_servers":                   nonNilStrings(urls.OCSPServers),
+			"crl_signing":                    issuer.Usage.HasUsage(CRLSigningUsage),
+			"crl_disabled":                   config.Disable,
+			"crl_expiry":                     config.Expiry,
+			"auto_rebuild":                   config.AutoRebuild,
+			"auto_rebuild_grace_period":      config.AutoRebuildGracePeriod,
+			"enable_delta":                   config.EnableDelta,
+			"delta_rebuild_interval":         config.DeltaRebuildInterval,
+			"ocsp_signing":                   issuer.Usage.HasUsage(OCSPSigningUsage),
+			"ocsp_disabled":                  config.OcspDisable,
+			"ocsp_expiry":                    config.OcspExpiry,
+			"ocsp_signer_issuer_id":          "",
+			"ocsp_signer_delegated":          false,
+			"revocation_signature_algorithm": revSigAlgStr,
+		},
+	}
+
+	if signer != nil {
+		resp.Data["ocsp_signer_issuer_id"] = signer.ID
+		resp.Data["ocsp_signer_delegated"] = signer.ID != issuer.ID
+	} else if !config.OcspDisable {
+		resp.AddWarning("No issuer sharing this issuer's subject and key has both a key and the ocsp-signing usage; OCSP requests for its certificates will be answered as unauthorized.")
+	}
+
+	if len(issuer.KeyID) == 0 {
+		resp.AddWarning("This issuer has no key in this mount; its CRL is signed by another issuer sharing its subject and key, if any.")
+	}
+
+	return resp, nil
+}
+
+// findOcspSigner returns the issuer which signs OCSP responses about
+// certificates of issuer, mirroring lookupOcspIssuer: the first issuer,
+// possibly issuer itself, sharing its subject and key, holding that key and
+// permitted the ocsp-signing usage. It returns nil if there is none.
+func findOcspSigner(sc *storageContext, issuer *issuerEntry) (*issuerEntry, error) {
+	if len(issuer.KeyID) == 0 {
+		return nil, nil
+	}
+
+	issuerCert, err := issuer.GetCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	issuerIds, err := sc.listIssuers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range issuerIds {
+		candidate, err := sc.fetchIssuerById(id)
+		if err != nil {
+			return nil, err
+		}
+		if candidate.KeyID != issuer.KeyID || !candidate.Usage.HasUsage(OCSPSigningUsage) {
+			continue
+		}
+
+		candidateCert, err := candidate.GetCertificate()
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(candidateCert.RawSubject, issuerCert.RawSubject) {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+const (
+	pathGetIssuerRevocationConfigHelpSyn  = `Fetch the effective revocation configuration of an issuer.`
+	pathGetIssuerRevocationConfigHelpDesc = `
+This consolidates, for a single issuer, the configuration governing the
+revocation information about its certificates: its effective AIA URLs
+(from the issuer or, when unset there, from /config/urls, with templates
+resolved), the mount's CRL and OCSP settings from /config/crl, whether the
+issuer may sign CRLs and OCSP responses, and which issuer signs its OCSP
+responses.
+
+:ref can be either the literal value "default", in which case /config/issuers
+will be consulted for the present default issuer, an identifier of an issuer,
+or its assigned name value.
+`
+)
@@ -4,13 +4,31 @@
 package pki
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/openbao/openbao/api/v2"
 	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/testhelpers/schema"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	"github.com/openbao/openbao/vault"
 
@@ -122,30 +140,4060 @@ func TestListCertificatesWithDetails(t *testing.T) {
 	}
 }
 
+func TestListCertificatesWithFingerprints(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+	rootSerial := resp.Data["serial_number"].(string)
+
+	// Fingerprints are omitted unless requested.
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	info := resp.Data["key_info"].(map[string]interface{})[rootSerial].(map[string]interface{})
+	require.NotContains(t, info, "sha1_fingerprint")
+	require.NotContains(t, info, "sha256_fingerprint")
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"include_fingerprints": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+	info = resp.Data["key_info"].(map[string]interface{})[rootSerial].(map[string]interface{})
+
+	sha1Sum := sha1.Sum(rootCert.Raw)
+	sha256Sum := sha256.Sum256(rootCert.Raw)
+	require.Equal(t, certutil.GetHexFormatted(sha1Sum[:], ":"), info["sha1_fingerprint"])
+	require.Equal(t, certutil.GetHexFormatted(sha256Sum[:], ":"), info["sha256_fingerprint"])
+}
+
+func TestListCertificatesWithSANs(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name":   true,
+		"allowed_uri_sans": "spiffe://example.com/*",
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var dnsNames []string
+	for i := 0; i < 7; i++ {
+		dnsNames = append(dnsNames, fmt.Sprintf("host%d.example.com", i))
+	}
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name":          "host0.example.com",
+		"alt_names":            strings.Join(dnsNames, ",") + ",ops@example.com",
+		"ip_sans":              "10.0.0.1",
+		"uri_sans":             "spiffe://example.com/svc",
+		"exclude_cn_from_sans": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+
+	// By default, DNS names are truncated and other SANs omitted.
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	info := resp.Data["key_info"].(map[string]interface{})[serial].(map[string]interface{})
+	require.Len(t, info["dns_names"], 5)
+	require.NotContains(t, info, "ip_sans")
+	require.NotContains(t, info, "uri_sans")
+	require.NotContains(t, info, "email_sans")
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"include_sans": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+	info = resp.Data["key_info"].(map[string]interface{})[serial].(map[string]interface{})
+	require.ElementsMatch(t, dnsNames, info["dns_names"])
+	require.Equal(t, []string{"10.0.0.1"}, info["ip_sans"])
+	require.Equal(t, []string{"spiffe://example.com/svc"}, info["uri_sans"])
+	require.Equal(t, []string{"ops@example.com"}, info["email_sans"])
+}
+
+func TestListCertificatesWithKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "roles/server", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+		"server_flag":    true,
+		"client_flag":    false,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/server")
+
+	resp, err = CBWrite(b, s, "roles/signing", map[string]interface{}{
+		"allow_any_name":     true,
+		"key_type":           "ec",
+		"ttl":                "1h",
+		"server_flag":        false,
+		"client_flag":        false,
+		"code_signing_flag":  true,
+		"key_usage":          "DigitalSignature",
+		"ext_key_usage_oids": "1.3.6.1.4.1.311.10.3.13",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/signing")
+
+	resp, err = CBWrite(b, s, "issue/server", map[string]interface{}{
+		"common_name": "server.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/server")
+	serverSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "issue/signing", map[string]interface{}{
+		"common_name": "signing.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/signing")
+	signingSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+
+	root := keyInfo[rootSerial].(map[string]interface{})
+	require.Equal(t, []string{"CertSign", "CRLSign"}, root["key_usage"])
+	require.Equal(t, []string{}, root["ext_key_usage"])
+
+	server := keyInfo[serverSerial].(map[string]interface{})
+	require.Contains(t, server["key_usage"], "DigitalSignature")
+	require.Equal(t, []string{"ServerAuth"}, server["ext_key_usage"])
+
+	// Extended key usages unknown to crypto/x509 are reported by OID.
+	signing := keyInfo[signingSerial].(map[string]interface{})
+	require.Equal(t, []string{"DigitalSignature"}, signing["key_usage"])
+	require.Equal(t, []string{"CodeSigning", "1.3.6.1.4.1.311.10.3.13"}, signing["ext_key_usage"])
+}
+
+func TestFetchCertSignatureAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "rsa",
+		"key_bits":    2048,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	leafSerial := resp.Data["serial_number"].(string)
+
+	// Both certificates are signed by the RSA root.
+	for _, path := range []string{"cert/" + leafSerial, "cert/" + rootSerial, "cert/ca"} {
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ReadOperation), resp, true)
+		require.Equal(t, x509.SHA256WithRSA.String(), resp.Data["signature_algorithm"], "reading %v", path)
+	}
+
+	resp, err = CBRead(b, s, "cert/crl")
+	requireSuccessNonNilResponse(t, resp, err, "cert/crl")
+	require.NotContains(t, resp.Data, "signature_algorithm")
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"detailed": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	for _, serial := range []string{leafSerial, rootSerial} {
+		require.Equal(t, x509.SHA256WithRSA.String(), keyInfo[serial].(map[string]interface{})["signature_algorithm"], "listing %v", serial)
+	}
+}
+
+func TestFetchCertBasicConstraints(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name":     "root example.com",
+		"ttl":             "8h",
+		"key_type":        "ec",
+		"max_path_length": 2,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "intermediate example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+
+	resp, err = CBWrite(b, s, "root/sign-intermediate", map[string]interface{}{
+		"csr":             resp.Data["csr"],
+		"common_name":     "intermediate example.com",
+		"ttl":             "4h",
+		"max_path_length": 0,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/sign-intermediate")
+	intSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	leafSerial := resp.Data["serial_number"].(string)
+
+	expected := map[string]struct {
+		isCA       bool
+		maxPathLen int
+	}{
+		rootSerial: {true, 2},
+		intSerial:  {true, 0},
+		leafSerial: {false, -1},
+	}
+
+	for serial, want := range expected {
+		path := "cert/" + serial
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ReadOperation), resp, true)
+		require.Equal(t, want.isCA, resp.Data["is_ca"], "reading %v", path)
+		require.Equal(t, want.maxPathLen, resp.Data["max_path_len"], "reading %v", path)
+	}
+
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Equal(t, true, resp.Data["is_ca"])
+	require.Equal(t, 2, resp.Data["max_path_len"])
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"detailed": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	for serial, want := range expected {
+		info := keyInfo[serial].(map[string]interface{})
+		require.Equal(t, want.isCA, info["is_ca"], "listing %v", serial)
+		require.Equal(t, want.maxPathLen, info["max_path_len"], "listing %v", serial)
+	}
+}
+
+func TestFetchCertValidityState(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "current.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	current := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "scheduled.example.com",
+		"not_before":  time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		"not_after":   time.Now().Add(3 * time.Hour).UTC().Format(time.RFC3339),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	scheduled := resp.Data["serial_number"].(string)
+
+	for serial, state := range map[string]string{current: validityStateValid, scheduled: validityStateNotYetValid, "ca": validityStateValid} {
+		resp, err = CBRead(b, s, "cert/"+serial)
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+		require.Equal(t, state, resp.Data["validity_state"], "reading %v", serial)
+	}
+
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, validityStateNotYetValid, certValidityState(cert, cert.NotBefore.Add(-time.Second)))
+	require.Equal(t, validityStateValid, certValidityState(cert, cert.NotBefore))
+	require.Equal(t, validityStateValid, certValidityState(cert, cert.NotAfter))
+	require.Equal(t, validityStateExpired, certValidityState(cert, cert.NotAfter.Add(time.Second)))
+}
+
+func TestListCertificatesWithRevocation(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for _, cn := range []string{"valid.example.com", "revoked.example.com"} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": cn,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+	valid, revoked := serials[0], serials[1]
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	revokedAt := resp.Data["revocation_time_rfc3339"]
+
+	// Revocation status is omitted by default.
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.NotContains(t, resp.Data["key_info"].(map[string]interface{})[revoked], "revoked")
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"include_revocation": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+
+	validInfo := keyInfo[valid].(map[string]interface{})
+	require.Equal(t, false, validInfo["revoked"])
+	require.NotContains(t, validInfo, "revocation_time_rfc3339")
+
+	revokedInfo := keyInfo[revoked].(map[string]interface{})
+	require.Equal(t, true, revokedInfo["revoked"])
+	require.Equal(t, revokedAt, revokedInfo["revocation_time_rfc3339"])
+	require.Equal(t, 0, revokedInfo["revocation_reason"])
+	require.Equal(t, "unspecified", revokedInfo["revocation_reason_string"])
+}
+
+func TestListCertificatesPagination(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 4; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	}
+
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	require.NotContains(t, resp.Data, "next_cursor")
+	require.Equal(t, "", resp.Data["next_after"])
+	require.Equal(t, false, resp.Data["has_more"])
+	allSerials := resp.Data["keys"].([]string)
+	require.Len(t, allSerials, 5)
+
+	for _, path := range []string{"certs", "certs/detailed"} {
+		var paged []string
+		after := ""
+		for {
+			resp, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+				"after":               after,
+				"limit":               2,
+				"include_next_cursor": true,
+			})
+			requireSuccessNonNilResponse(t, resp, err, path)
+			schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ListOperation), resp, true)
+			if keys, ok := resp.Data["keys"]; ok {
+				paged = append(paged, keys.([]string)...)
+			}
+
+			after = resp.Data["next_cursor"].(string)
+			require.Equal(t, after, resp.Data["next_after"])
+			require.Equal(t, after != "", resp.Data["has_more"])
+			if after == "" {
+				break
+			}
+			require.Equal(t, paged[len(paged)-1], after)
+		}
+		require.Equal(t, allSerials, paged, "paging through %v", path)
+
+		// Cursors in the hyphenated storage form and in upper case list the
+		// same page as the colon-separated form.
+		for _, cursor := range []string{allSerials[1], normalizeSerial(allSerials[1]), strings.ToUpper(allSerials[1])} {
+			resp, err = CBPaginatedList(b, s, path, cursor, 2)
+			requireSuccessNonNilResponse(t, resp, err, path)
+			require.Equal(t, allSerials[2:4], resp.Data["keys"], "listing %v after %v", path, cursor)
+		}
+
+		for _, cursor := range []string{"test.example.com", "1a::2b", "123"} {
+			resp, err = CBPaginatedList(b, s, path, cursor, 2)
+			require.Error(t, err, "listing %v after %v", path, cursor)
+			require.True(t, resp.IsError())
+		}
+	}
+}
+
+func TestListCertificatesByPrefix(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 8; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	}
+
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	allSerials := resp.Data["keys"].([]string)
+	require.Len(t, allSerials, 9)
+
+	matching := func(digits string) []string {
+		var serials []string
+		for _, serial := range allSerials {
+			if strings.HasPrefix(strings.ReplaceAll(serial, ":", ""), digits) {
+				serials = append(serials, serial)
+			}
+		}
+		return serials
+	}
+
+	// Contiguous, odd-length and separated prefixes are all accepted.
+	target := strings.ReplaceAll(allSerials[4], ":", "")
+	for _, prefix := range []string{target[:1], target[:2], target[:3], strings.ToUpper(target[:4]), allSerials[4][:5], normalizeSerial(allSerials[4][:5])} {
+		digits := strings.ToLower(strings.NewReplacer(":", "", "-", "").Replace(prefix))
+		resp, err = CBReq(b, s, logical.ListOperation, "certs", map[string]interface{}{
+			"prefix": prefix,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs")
+		require.Equal(t, matching(digits), resp.Data["keys"], "listing with prefix %v", prefix)
+	}
+
+	// Shards by the first hex digit page through every serial exactly once.
+	var sharded []string
+	for _, shard := range "0123456789abcdef" {
+		after := ""
+		for {
+			resp, err = CBReq(b, s, logical.ListOperation, "certs", map[string]interface{}{
+				"prefix":              string(shard),
+				"after":               after,
+				"limit":               1,
+				"include_next_cursor": true,
+			})
+			requireSuccessNonNilResponse(t, resp, err, "certs")
+			keys, ok := resp.Data["keys"]
+			if !ok {
+				break
+			}
+			sharded = append(sharded, keys.([]string)...)
+			after = resp.Data["next_cursor"].(string)
+			if after == "" {
+				break
+			}
+		}
+	}
+	require.Equal(t, allSerials, sharded)
+
+	// Cursors before the prefix's range list it from the start, while those
+	// after it list nothing.
+	first := allSerials[0]
+	resp, err = CBReq(b, s, logical.ListOperation, "certs", map[string]interface{}{
+		"prefix": first[:2],
+		"after":  "00",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	require.Equal(t, matching(first[:2]), resp.Data["keys"])
+
+	if first[:2] != "ff" {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs", map[string]interface{}{
+			"prefix": first[:2],
+			"after":  "ff",
+		})
+		require.NoError(t, err)
+		require.NotContains(t, resp.Data, "keys")
+	}
+
+	for _, prefix := range []string{"test", "1a::2b", "0x1a"} {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs", map[string]interface{}{
+			"prefix": prefix,
+		})
+		require.Error(t, err, "listing with prefix %v", prefix)
+		require.True(t, resp.IsError())
+	}
+}
+
+func TestListCertificatesMaxPageSize(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 4; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	}
+
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	allSerials := resp.Data["keys"].([]string)
+	require.Len(t, allSerials, 5)
+
+	_, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_list_page_size": -1})
+	require.Error(t, err)
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_list_page_size": 2})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	require.Equal(t, 2, resp.Data["max_list_page_size"])
+
+	// Unlimited and oversized requests are clamped to the configured page
+	// size, while smaller ones are honored; paging visits every entry.
+	for _, path := range []string{"certs", "certs/detailed"} {
+		for _, limit := range []int{0, 3, 1} {
+			pageSize := 2
+			if limit == 1 {
+				pageSize = 1
+			}
+
+			var paged []string
+			after := ""
+			for {
+				resp, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+					"after": after,
+					"limit": limit,
+				})
+				requireSuccessNonNilResponse(t, resp, err, path)
+				keys, ok := resp.Data["keys"].([]string)
+				if !ok {
+					break
+				}
+				require.LessOrEqual(t, len(keys), pageSize, "%v with limit %v", path, limit)
+				paged = append(paged, keys...)
+
+				if !resp.Data["has_more"].(bool) {
+					break
+				}
+				after = resp.Data["next_after"].(string)
+			}
+			require.Equal(t, allSerials, paged, "%v with limit %v", path, limit)
+		}
+	}
+
+	// Clearing the setting lists everything at once again.
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_list_page_size": 0})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	require.Equal(t, allSerials, resp.Data["keys"])
+	require.Equal(t, false, resp.Data["has_more"])
+}
+
+func TestListCertificateNames(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 3; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	}
+
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	allSerials := resp.Data["keys"].([]string)
+	detailed := resp.Data["key_info"].(map[string]interface{})
+
+	// A corrupt entry sorting after every issued certificate.
+	badSerial := "ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff:ff"
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(badSerial),
+		Value: []byte("not a certificate"),
+	}))
+
+	// Common names match those of the detailed listing.
+	resp, err = CBList(b, s, "certs/names")
+	requireSuccessNonNilResponse(t, resp, err, "certs/names")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/names"), logical.ListOperation), resp, true)
+	require.Equal(t, allSerials, resp.Data["keys"])
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	require.Len(t, keyInfo, len(allSerials))
+	for _, serial := range allSerials {
+		require.Equal(t, map[string]interface{}{
+			"common_name": detailed[serial].(map[string]interface{})["common_name"],
+		}, keyInfo[serial], "serial %v", serial)
+	}
+	require.Contains(t, resp.Data["parse_errors"], badSerial)
+	require.NotEmpty(t, resp.Warnings)
+
+	// Paging with after and limit visits every entry once.
+	var paged []string
+	after := ""
+	for {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/names", map[string]interface{}{
+			"after":               after,
+			"limit":               2,
+			"include_next_cursor": true,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/names")
+		if keys, ok := resp.Data["keys"].([]string); ok {
+			require.LessOrEqual(t, len(keys), 2)
+			paged = append(paged, keys...)
+		}
+
+		after = resp.Data["next_cursor"].(string)
+		if after == "" {
+			break
+		}
+	}
+	require.Equal(t, allSerials, paged)
+}
+
+func TestListExpiredCertificates(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	issue := func(t *testing.T, ttl string) string {
+		t.Helper()
+		resp, err := CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "test.example.com",
+			"ttl":         ttl,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		return resp.Data["serial_number"].(string)
+	}
+
+	var expired []string
+	for i := 0; i < 3; i++ {
+		expired = append(expired, issue(t, "1s"))
+		issue(t, "1h")
+	}
+	sort.Strings(expired)
+	time.Sleep(2 * time.Second)
+
+	resp, err = CBList(b, s, "certs/expired")
+	requireSuccessNonNilResponse(t, resp, err, "certs/expired")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/expired"), logical.ListOperation), resp, true)
+	require.Equal(t, expired, resp.Data["keys"])
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	for _, serial := range expired {
+		notAfter := keyInfo[serial].(map[string]interface{})["not_after"].(time.Time)
+		require.True(t, notAfter.Before(time.Now()), "serial %v", serial)
+	}
+
+	// The limit applies to the expired certificates.
+	var paged []string
+	after := ""
+	for {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/expired", map[string]interface{}{
+			"after": after,
+			"limit": 2,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/expired")
+		keys, _ := resp.Data["keys"].([]string)
+		require.LessOrEqual(t, len(keys), 2)
+		paged = append(paged, keys...)
+		if resp.Data["has_more"] != true {
+			break
+		}
+		after = resp.Data["next_after"].(string)
+	}
+	require.Equal(t, expired, paged)
+
+	// Nothing had expired an hour ago, while everything has in a day.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/expired", map[string]interface{}{
+		"as_of": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/expired")
+	require.Empty(t, resp.Data["keys"])
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/expired", map[string]interface{}{
+		"as_of": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/expired")
+	require.Len(t, resp.Data["keys"], 7)
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/expired", map[string]interface{}{
+		"as_of": "yesterday",
+	})
+	require.ErrorContains(t, err, "as_of")
+}
+
+func TestListCertificatesDetailedParseErrors(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	// A corrupt entry sorting after the root's.
+	badSerial := "ff:ff:ff:ff"
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(badSerial),
+		Value: []byte("not a certificate"),
+	}))
+
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+	require.Equal(t, []string{rootSerial}, resp.Data["keys"])
+	require.Contains(t, resp.Data["key_info"], rootSerial)
+	parseErrors := resp.Data["parse_errors"].(map[string]interface{})
+	require.Len(t, parseErrors, 1)
+	require.Contains(t, parseErrors, badSerial)
+	require.NotEmpty(t, resp.Warnings)
+
+	// The skipped entry still advances the cursor.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"after":               rootSerial,
+		"limit":               1,
+		"include_next_cursor": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.NotContains(t, resp.Data, "keys")
+	require.Contains(t, resp.Data["parse_errors"], badSerial)
+	require.Equal(t, badSerial, resp.Data["next_cursor"])
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"strict": true,
+	})
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), badSerial)
+}
+
+func TestListCertificatesDetailedFilters(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	issued := map[string][]string{}
+	for _, keyType := range []string{"rsa", "ec"} {
+		resp, err = CBWrite(b, s, "roles/"+keyType, map[string]interface{}{
+			"allowed_domains":  "example.com",
+			"allow_subdomains": true,
+			"key_type":         keyType,
+			"ttl":              "1h",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "roles/"+keyType)
+
+		for i := 0; i < 3; i++ {
+			resp, err = CBWrite(b, s, "issue/"+keyType, map[string]interface{}{
+				"common_name": fmt.Sprintf("%s%d.example.com", keyType, i),
+			})
+			requireSuccessNonNilResponse(t, resp, err, "issue/"+keyType)
+			issued[keyType] = append(issued[keyType], resp.Data["serial_number"].(string))
+		}
+	}
+
+	listAll := func(t *testing.T, params map[string]interface{}) []string {
+		t.Helper()
+		var serials []string
+		after := ""
+		for {
+			req := map[string]interface{}{
+				"after":               after,
+				"limit":               2,
+				"include_next_cursor": true,
+			}
+			for k, v := range params {
+				req[k] = v
+			}
+			resp, err := CBReq(b, s, logical.ListOperation, "certs/detailed", req)
+			requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+			if keys, ok := resp.Data["keys"]; ok {
+				schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+				require.LessOrEqual(t, len(keys.([]string)), 2)
+				serials = append(serials, keys.([]string)...)
+			}
+			after = resp.Data["next_cursor"].(string)
+			if after == "" {
+				break
+			}
+		}
+		sort.Strings(serials)
+		return serials
+	}
+	sorted := func(serials ...string) []string {
+		sort.Strings(serials)
+		return serials
+	}
+
+	require.Equal(t, sorted(issued["rsa"]...), listAll(t, map[string]interface{}{"key_type": "rsa"}))
+
+	// The limit applies to the matching certificates rather than to the
+	// entries scanned.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"key_type": "rsa",
+		"limit":    2,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.Len(t, resp.Data["keys"], 2)
+
+	require.Equal(t, sorted(append([]string{rootSerial}, issued["ec"]...)...), listAll(t, map[string]interface{}{"key_type": "ec"}))
+	require.Equal(t, sorted(issued["rsa"]...), listAll(t, map[string]interface{}{"min_key_bits": 2048}))
+	require.Empty(t, listAll(t, map[string]interface{}{"key_type": "ed25519"}))
+
+	// The root expires in 8h, outside the window; the leaves within it.
+	require.Equal(t, sorted(append(issued["rsa"], issued["ec"]...)...), listAll(t, map[string]interface{}{"expiring_within": "2h"}))
+	require.Empty(t, listAll(t, map[string]interface{}{"expiring_within": "30m"}))
+	require.Equal(t, sorted(issued["rsa"]...), listAll(t, map[string]interface{}{
+		"key_type":        "rsa",
+		"min_key_bits":    2048,
+		"expiring_within": "2h",
+	}))
+
+	for _, params := range []map[string]interface{}{
+		{"key_type": "dsa"},
+		{"min_key_bits": -1},
+	} {
+		_, err = CBReq(b, s, logical.ListOperation, "certs/detailed", params)
+		require.Error(t, err, "listing with %v", params)
+	}
+}
+
+func TestListCertificatesDetailedExpirationWindow(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+		"max_ttl":          "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	serials := map[string]string{}
+	for _, ttl := range []string{"1h", "3h"} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "test.example.com",
+			"ttl":         ttl,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials[ttl] = resp.Data["serial_number"].(string)
+	}
+
+	now := time.Now()
+	window := func(after, before time.Duration) map[string]interface{} {
+		params := map[string]interface{}{}
+		if after != 0 {
+			params["not_after_after"] = now.Add(after).Format(time.RFC3339)
+		}
+		if before != 0 {
+			params["not_after_before"] = now.Add(before).Format(time.RFC3339)
+		}
+		return params
+	}
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", window(2*time.Hour, 4*time.Hour))
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+	require.Equal(t, []string{serials["3h"]}, resp.Data["keys"])
+
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", window(0, 2*time.Hour))
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.Equal(t, []string{serials["1h"]}, resp.Data["keys"])
+
+	// The root expires in 8h.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", window(2*time.Hour, 0))
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.Len(t, resp.Data["keys"], 2)
+	require.Contains(t, resp.Data["keys"], serials["3h"])
+
+	// Unparseable entries are reported rather than filtered out.
+	badSerial := "ff:ff:ff:ff"
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(badSerial),
+		Value: []byte("not a certificate"),
+	}))
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", window(2*time.Hour, 4*time.Hour))
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	require.Equal(t, []string{serials["3h"]}, resp.Data["keys"])
+	require.Contains(t, resp.Data["parse_errors"], badSerial)
+
+	for _, params := range []map[string]interface{}{
+		{"not_after_before": "tomorrow"},
+		{"not_after_after": "2024-01-01"},
+		window(4*time.Hour, 2*time.Hour),
+	} {
+		_, err = CBReq(b, s, logical.ListOperation, "certs/detailed", params)
+		require.Error(t, err, "listing with %v", params)
+	}
+}
+
+func TestListCertificatesDetailedSort(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"max_ttl":          "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	serials := map[string]string{}
+	for _, issue := range []struct{ name, ttl string }{{"c", "2h"}, {"a", "3h"}, {"b", "1h"}} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": issue.name + ".example.com",
+			"ttl":         issue.ttl,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials[issue.name] = resp.Data["serial_number"].(string)
+	}
+
+	list := func(t *testing.T, params map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := CBReq(b, s, logical.ListOperation, "certs/detailed", params)
+		requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+		return resp
+	}
+
+	resp = list(t, map[string]interface{}{"sort": "not_after"})
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/detailed"), logical.ListOperation), resp, true)
+	require.Equal(t, []string{serials["b"], serials["c"], serials["a"], rootSerial}, resp.Data["keys"])
+
+	resp = list(t, map[string]interface{}{"sort": "not_after", "order": "desc"})
+	require.Equal(t, []string{rootSerial, serials["a"], serials["c"], serials["b"]}, resp.Data["keys"])
+
+	resp = list(t, map[string]interface{}{"sort": "common_name"})
+	require.Equal(t, []string{serials["a"], serials["b"], serials["c"], rootSerial}, resp.Data["keys"])
+
+	// The leaves share their NotBefore second, so ties keep serial order.
+	resp = list(t, map[string]interface{}{"sort": "not_before", "order": "desc"})
+	keys := resp.Data["keys"].([]string)
+	require.Len(t, keys, 4)
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	for i := 1; i < len(keys); i++ {
+		previous := keyInfo[keys[i-1]].(map[string]interface{})["not_before"].(time.Time)
+		current := keyInfo[keys[i]].(map[string]interface{})["not_before"].(time.Time)
+		require.False(t, previous.Before(current), "keys %v", keys)
+		if previous.Equal(current) {
+			require.Less(t, normalizeSerial(keys[i-1]), normalizeSerial(keys[i]), "keys %v", keys)
+		}
+	}
+
+	// issued sorts by issuance time, as not_before does.
+	resp = list(t, map[string]interface{}{"sort": "issued", "order": "desc"})
+	require.Equal(t, keys, resp.Data["keys"])
+
+	// Sorting applies within the page, which is fetched in serial order.
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err, "certs")
+	firstPage := resp.Data["keys"].([]string)[:2]
+	resp = list(t, map[string]interface{}{"sort": "common_name", "limit": 2, "include_next_cursor": true})
+	require.ElementsMatch(t, firstPage, resp.Data["keys"])
+	require.Equal(t, firstPage[1], resp.Data["next_cursor"])
+
+	for _, params := range []map[string]interface{}{
+		{"sort": "serial_number"},
+		{"sort": "not_after", "order": "up"},
+		{"sort": "not_after", "format": "ndjson"},
+	} {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/detailed", params)
+		require.Error(t, err, "listing with %v", params)
+		require.True(t, resp.IsError(), "listing with %v", params)
+	}
+}
+
+func TestListCertificatesByIssuer(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	bySerial := map[string][]string{}
+	for _, name := range []string{"root-a", "root-b"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"key_name":    name,
+			"ttl":         "8h",
+			"key_type":    "ec",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		bySerial[name] = append(bySerial[name], resp.Data["serial_number"].(string))
+	}
+
+	// An issuer sharing the subject and key of root-a is told apart by the
+	// issuance records, which signatures alone cannot do.
+	resp, err := CBWrite(b, s, "root/generate/existing", map[string]interface{}{
+		"common_name": "root-a example.com",
+		"issuer_name": "root-c",
+		"key_ref":     "root-a",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/existing")
+	bySerial["root-c"] = append(bySerial["root-c"], resp.Data["serial_number"].(string))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 7; i++ {
+		name := "root-a"
+		switch {
+		case i == 6:
+			name = "root-c"
+		case i%3 == 0:
+			name = "root-b"
+		}
+		resp, err = CBWrite(b, s, "issuer/"+name+"/issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issuer/"+name+"/issue/example")
+		bySerial[name] = append(bySerial[name], resp.Data["serial_number"].(string))
+	}
+
+	issuerA, err := b.makeStorageContext(ctx, s).resolveIssuerReference("root-a")
+	require.NoError(t, err)
+
+	for _, path := range []string{"certs", "certs/detailed"} {
+		for name, serials := range bySerial {
+			expected := append([]string{}, serials...)
+			sort.Slice(expected, func(i, j int) bool {
+				return normalizeSerial(expected[i]) < normalizeSerial(expected[j])
+			})
+
+			resp, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+				"issuer": name,
+			})
+			requireSuccessNonNilResponse(t, resp, err, path)
+			schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route(path), logical.ListOperation), resp, true)
+			require.Equal(t, expected, resp.Data["keys"], "%v of %v", path, name)
+
+			// The limit applies to the certificates signed by the issuer.
+			var paged []string
+			after := ""
+			for {
+				resp, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+					"issuer":              name,
+					"after":               after,
+					"limit":               2,
+					"include_next_cursor": true,
+				})
+				requireSuccessNonNilResponse(t, resp, err, path)
+				keys, ok := resp.Data["keys"].([]string)
+				if !ok {
+					break
+				}
+				require.LessOrEqual(t, len(keys), 2)
+				paged = append(paged, keys...)
+
+				after = resp.Data["next_cursor"].(string)
+				if after == "" {
+					break
+				}
+			}
+			require.Equal(t, expected, paged, "%v of %v", path, name)
+		}
+
+		// Issuers may also be referenced by ID.
+		resp, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+			"issuer": string(issuerA),
+		})
+		requireSuccessNonNilResponse(t, resp, err, path)
+		require.Len(t, resp.Data["keys"], len(bySerial["root-a"]))
+
+		_, err = CBReq(b, s, logical.ListOperation, path, map[string]interface{}{
+			"issuer": "missing",
+		})
+		require.Error(t, err, path)
+	}
+}
+
+func TestListCertificatesDetailedNDJSON(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	for i := 0; i < 3; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("host%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	}
+
+	badSerial := "ff:ff:ff:ff"
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(badSerial),
+		Value: []byte("not a certificate"),
+	}))
+
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keys := resp.Data["keys"].([]string)
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	require.Len(t, keys, 4)
+
+	listNDJSON := func(t *testing.T, params map[string]interface{}) []map[string]interface{} {
+		t.Helper()
+		params["format"] = "ndjson"
+		resp, err := CBReq(b, s, logical.ListOperation, "certs/detailed", params)
+		requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+		require.Equal(t, "application/x-ndjson", resp.Data[logical.HTTPContentType])
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(resp.Data[logical.HTTPRawBody].([]byte))), "\n") {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), "line %q", line)
+			lines = append(lines, entry)
+		}
+		return lines
+	}
+
+	// Each certificate is one line carrying the same fields as key_info,
+	// and entries which failed to parse are reported inline.
+	lines := listNDJSON(t, map[string]interface{}{})
+	require.Len(t, lines, 5)
+	var serials, parsed []string
+	for _, line := range lines {
+		serial := line["serial_number"].(string)
+		serials = append(serials, serial)
+		if serial == badSerial {
+			require.Contains(t, line, "parse_error")
+			continue
+		}
+		parsed = append(parsed, serial)
+		info := keyInfo[serial].(map[string]interface{})
+		require.Equal(t, info["common_name"], line["common_name"])
+		require.Equal(t, info["not_after"].(time.Time).Format(time.RFC3339Nano), line["not_after"])
+	}
+	require.Equal(t, keys, parsed)
+	require.Contains(t, serials, badSerial)
+
+	serialsOf := func(lines []map[string]interface{}) []string {
+		var serials []string
+		for _, line := range lines {
+			serials = append(serials, line["serial_number"].(string))
+		}
+		return serials
+	}
+
+	require.Equal(t, serials[:2], serialsOf(listNDJSON(t, map[string]interface{}{"limit": 2})))
+	require.Equal(t, serials[1:3], serialsOf(listNDJSON(t, map[string]interface{}{
+		"after": normalizeSerial(serials[0]),
+		"limit": 2,
+	})))
+
+	_, err = CBReq(b, s, logical.ListOperation, "certs/detailed", map[string]interface{}{
+		"format": "csv",
+	})
+	require.Error(t, err)
+}
+
+func TestListRevokedCertificatesDetailed(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	issuerID := string(resp.Data["issuer_id"].(issuerID))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var revoked []string
+	for i := 0; i < 5; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		if i%2 == 1 {
+			continue
+		}
+
+		serial := resp.Data["serial_number"].(string)
+		resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+			"serial_number": serial,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "revoke")
+		revoked = append(revoked, serial)
+	}
+	sort.Strings(revoked)
+
+	resp, err = CBList(b, s, "certs/revoked/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/revoked/detailed"), logical.ListOperation), resp, true)
+	require.Equal(t, revoked, resp.Data["keys"])
+	require.Equal(t, false, resp.Data["has_more"])
+
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	require.Len(t, keyInfo, len(revoked))
+	for _, serial := range revoked {
+		info := keyInfo[serial].(map[string]interface{})
+		require.Equal(t, issuerID, info["certificate_issuer"], "serial %v", serial)
+		require.NotEmpty(t, info["revocation_time_rfc3339"], "serial %v", serial)
+		require.Equal(t, 0, info["revocation_reason"], "serial %v", serial)
+		require.NotContains(t, info, "revoked", "serial %v", serial)
+	}
+
+	// Paging with after and limit visits every revoked certificate once.
+	var paged []string
+	after := ""
+	for {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+			"after":               after,
+			"limit":               2,
+			"include_next_cursor": true,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+		keys, ok := resp.Data["keys"].([]string)
+		if !ok {
+			break
+		}
+		require.LessOrEqual(t, len(keys), 2)
+		require.Len(t, resp.Data["key_info"], len(keys))
+		paged = append(paged, keys...)
+
+		after = resp.Data["next_cursor"].(string)
+		if after == "" {
+			break
+		}
+	}
+	require.Equal(t, revoked, paged)
+}
+
+func TestListRevokedCertificatesDetailedByReason(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	// Revoke three certificates for key compromise, two without a specific
+	// reason, and one as if before reasons were recorded.
+	var compromised, unspecified []string
+	for i := 0; i < 6; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serial := resp.Data["serial_number"].(string)
+
+		switch {
+		case i%2 == 0:
+			resp, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+				"public_key": string(pem.EncodeToMemory(&pem.Block{
+					Type:  "PUBLIC KEY",
+					Bytes: parseCert(t, resp.Data["certificate"].(string)).RawSubjectPublicKeyInfo,
+				})),
+				"reason": "key_compromise",
+			})
+			requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+			compromised = append(compromised, serial)
+		default:
+			resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+			requireSuccessNonNilResponse(t, resp, err, "revoke")
+			if i == 5 {
+				entry, err := s.Get(ctx, "revoked/"+normalizeSerial(serial))
+				require.NoError(t, err)
+				var revInfo revocationInfo
+				require.NoError(t, entry.DecodeJSON(&revInfo))
+				revInfo.ReasonRecorded = false
+				entry, err = logical.StorageEntryJSON("revoked/"+normalizeSerial(serial), revInfo)
+				require.NoError(t, err)
+				require.NoError(t, s.Put(ctx, entry))
+				continue
+			}
+			unspecified = append(unspecified, serial)
+		}
+	}
+	sort.Strings(compromised)
+	sort.Strings(unspecified)
+
+	// Without a filter, entries lacking a recorded reason are still listed.
+	resp, err = CBList(b, s, "certs/revoked/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+	require.Len(t, resp.Data["keys"], 6)
+
+	for _, reason := range []string{"keyCompromise", "KEYCOMPROMISE", "key_compromise", "1"} {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+			"reason": reason,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/revoked/detailed"), logical.ListOperation), resp, true)
+		require.Equal(t, compromised, resp.Data["keys"], "reason %v", reason)
+		for _, info := range resp.Data["key_info"].(map[string]interface{}) {
+			require.Equal(t, "keyCompromise", info.(map[string]interface{})["revocation_reason_string"])
+		}
+	}
+
+	// Entries without a recorded reason don't match unspecified.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+		"reason": "0",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+	require.Equal(t, unspecified, resp.Data["keys"])
+
+	// The limit applies to the matching entries.
+	var paged []string
+	after := ""
+	for {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+			"reason":              "keyCompromise",
+			"after":               after,
+			"limit":               2,
+			"include_next_cursor": true,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/revoked/detailed")
+		keys, ok := resp.Data["keys"].([]string)
+		if !ok {
+			break
+		}
+		require.LessOrEqual(t, len(keys), 2)
+		paged = append(paged, keys...)
+
+		after = resp.Data["next_cursor"].(string)
+		if after == "" {
+			break
+		}
+	}
+	require.Equal(t, compromised, paged)
+
+	// No certificate was revoked for a superseded key.
+	resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+		"reason": "superseded",
+	})
+	require.NoError(t, err)
+	require.NotContains(t, resp.Data, "keys")
+
+	for _, reason := range []string{"bogus", "11"} {
+		resp, err = CBReq(b, s, logical.ListOperation, "certs/revoked/detailed", map[string]interface{}{
+			"reason": reason,
+		})
+		require.Error(t, err, "reason %v", reason)
+		require.True(t, resp.IsError(), "reason %v", reason)
+	}
+}
+
+func TestFetchCertBatch(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for i := 0; i < 2; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("host%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+
+	badSerial := "ff:ff:ff:ff"
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(badSerial),
+		Value: []byte("not a certificate"),
+	}))
+
+	resp, err = CBList(b, s, "certs/detailed")
+	requireSuccessNonNilResponse(t, resp, err, "certs/detailed")
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+
+	// Serial numbers are accepted in any form and reported colon-separated.
+	resp, err = CBWrite(b, s, "certs/batch", map[string]interface{}{
+		"serials": []string{
+			serials[0],
+			strings.ReplaceAll(serials[1], ":", ""),
+			"01-02-03",
+			badSerial,
+		},
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/batch")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/batch"), logical.UpdateOperation), resp, true)
+	certs := resp.Data["certs"].(map[string]interface{})
+	require.Len(t, certs, 2)
+	for _, serial := range serials {
+		require.Equal(t, keyInfo[serial], certs[serial])
+	}
+	require.Equal(t, []string{"01:02:03"}, resp.Data["not_found"])
+	require.Contains(t, resp.Data["parse_errors"], badSerial)
+	require.NotEmpty(t, resp.Warnings)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "certs/batch", map[string]interface{}{
+		"serials":              serials[0],
+		"include_fingerprints": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/batch")
+	require.Contains(t, resp.Data["certs"].(map[string]interface{})[serials[0]], "sha256_fingerprint")
+
+	_, err = CBWrite(b, s, "certs/batch", map[string]interface{}{"serials": "crl"})
+	require.Error(t, err)
+
+	// Batches are bounded by the mount's configured limit.
+	resp, err = CBWrite(b, s, "config/issuance", map[string]interface{}{"max_batch_fetch_serials": 1})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuance")
+	_, err = CBWrite(b, s, "certs/batch", map[string]interface{}{"serials": serials})
+	require.Error(t, err)
+}
+
+func TestFetchCertCount(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBRead(b, s, "certs/count")
+	requireSuccessNonNilResponse(t, resp, err, "certs/count")
+	require.Equal(t, 0, resp.Data["count"])
+	require.NotContains(t, resp.Data, "revoked_count")
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for i := 0; i < 3; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serials[0],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "certs/count", map[string]interface{}{
+		"include_revoked": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/count")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/count"), logical.ReadOperation), resp, true)
+	require.Equal(t, 4, resp.Data["count"])
+	require.Equal(t, 1, resp.Data["revoked_count"])
+}
+
+func TestFetchCertificateJWK(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"issuer_name": "root",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "rsa",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	leafCert := parseCert(t, resp.Data["certificate"].(string))
+	serial := resp.Data["serial_number"].(string)
+
+	parseJWK := func(t *testing.T, data map[string]interface{}) jose.JSONWebKey {
+		t.Helper()
+		require.Contains(t, data, "jwk")
+		encoded, err := json.Marshal(data["jwk"])
+		require.NoError(t, err)
+		var jwk jose.JSONWebKey
+		require.NoError(t, jwk.UnmarshalJSON(encoded))
+		require.True(t, jwk.IsPublic())
+		return jwk
+	}
+
+	// Without format=jwk, the response is unchanged.
+	resp, err = CBRead(b, s, "cert/"+serial)
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	require.NotContains(t, resp.Data, "jwk")
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"format": "jwk",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+	jwk := parseJWK(t, resp.Data)
+	require.Equal(t, leafCert.PublicKey, jwk.Key)
+	require.Equal(t, serial, jwk.KeyID)
+	require.Len(t, jwk.Certificates, 2)
+	require.Equal(t, leafCert.Raw, jwk.Certificates[0].Raw)
+	require.Equal(t, rootCert.Raw, jwk.Certificates[1].Raw)
+	leafThumbprint := sha256.Sum256(leafCert.Raw)
+	require.Equal(t, leafThumbprint[:], jwk.CertificateThumbprintSHA256)
+
+	// Without an issuance record, the chain holds only the certificate.
+	require.NoError(t, s.Delete(context.Background(), certIssuancePrefix+normalizeSerial(serial)))
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"format": "jwk",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	jwk = parseJWK(t, resp.Data)
+	require.Len(t, jwk.Certificates, 1)
+	require.Equal(t, leafCert.Raw, jwk.Certificates[0].Raw)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/ca", map[string]interface{}{
+		"format": "jwk",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	jwk = parseJWK(t, resp.Data)
+	require.Equal(t, rootCert.PublicKey, jwk.Key)
+	require.Len(t, jwk.Certificates, 1)
+	require.Equal(t, rootCert.Raw, jwk.Certificates[0].Raw)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "issuer/root/json", map[string]interface{}{
+		"format": "jwk",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root/json")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issuer/root/json"), logical.ReadOperation), resp, true)
+	jwk = parseJWK(t, resp.Data)
+	require.Equal(t, rootCert.PublicKey, jwk.Key)
+	require.Len(t, jwk.Certificates, 1)
+	require.Equal(t, rootCert.Raw, jwk.Certificates[0].Raw)
+
+	for _, path := range []string{"cert/" + serial, "issuer/root/json"} {
+		_, err = CBReq(b, s, logical.ReadOperation, path, map[string]interface{}{
+			"format": "jws",
+		})
+		require.Error(t, err, "reading %v", path)
+	}
+}
+
 func checkCertificateDetails(t *testing.T, certData, expectedDetails map[string]interface{}) {
 	actualDNSNames, ok := certData["dns_names"].([]interface{})
 	require.True(t, ok, "Expected dns_names to be a list")
 
-	// Convert actual DNS names to a string slice for comparison
-	actualDNSNamesStr := make([]string, len(actualDNSNames))
-	for i, name := range actualDNSNames {
-		actualDNSNamesStr[i] = name.(string)
+	// Convert actual DNS names to a string slice for comparison
+	actualDNSNamesStr := make([]string, len(actualDNSNames))
+	for i, name := range actualDNSNames {
+		actualDNSNamesStr[i] = name.(string)
+	}
+
+	// Check that each expected DNS name is contained within the actual DNS names
+	for _, expectedDNSName := range expectedDetails["dns_names"].([]string) {
+		require.Contains(t, actualDNSNamesStr, expectedDNSName, "Expected DNS name not found")
+	}
+
+	// Convert key_bits to int before checking
+	keyBits, err := certData["key_bits"].(json.Number).Int64()
+	require.NoError(t, err, "Failed to convert key_bits to int")
+	require.Equal(t, expectedDetails["key_bits"], int(keyBits), "Mismatch in key bits")
+
+	// Check the rest of the details match
+	require.Equal(t, expectedDetails["common_name"], certData["common_name"], "Mismatch in common name")
+	require.Equal(t, expectedDetails["issuer"], certData["issuer"], "Mismatch in issuer")
+	require.Equal(t, expectedDetails["key_type"], certData["key_type"], "Mismatch in key type")
+	require.Equal(t, expectedDetails["not_after"], certData["not_after"], "Mismatch in not after")
+	require.Equal(t, expectedDetails["not_before"], certData["not_before"], "Mismatch in not before")
+}
+
+func TestFetchCAChainJSON(t *testing.T) {
+	t.Parallel()
+
+	bRoot, sRoot := CreateBackendWithStorage(t)
+	resp, err := CBWrite(bRoot, sRoot, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	b, s := CreateBackendWithStorage(t)
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "int example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+
+	resp, err = CBWrite(bRoot, sRoot, "root/sign-intermediate", map[string]interface{}{
+		"csr":    resp.Data["csr"],
+		"format": "pem_bundle",
+		"ttl":    "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/sign-intermediate")
+	intCert := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "intermediate/set-signed", map[string]interface{}{
+		"certificate": resp.Data["certificate"],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/set-signed")
+
+	resp, err = CBRead(b, s, "ca_chain/json")
+	requireSuccessNonNilResponse(t, resp, err, "ca_chain/json")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("ca_chain/json"), logical.ReadOperation), resp, true)
+
+	chain := resp.Data["ca_chain"].([]string)
+	require.Len(t, chain, 2)
+	require.Equal(t, intCert.Raw, parseCert(t, chain[0]).Raw)
+	require.Equal(t, rootCert.Raw, parseCert(t, chain[1]).Raw)
+	require.Equal(t, chain[0], resp.Data["certificate"])
+
+	// The concatenated form is unchanged.
+	resp, err = CBRead(b, s, "cert/ca_chain")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca_chain")
+	require.Equal(t, strings.Join(chain, "\n"), resp.Data["ca_chain"])
+
+	resp, err = CBRead(b, s, "ca_chain")
+	requireSuccessNonNilResponse(t, resp, err, "ca_chain")
+	require.Equal(t, strings.Join(chain, "\n"), string(resp.Data[logical.HTTPRawBody].([]byte)))
+}
+
+func TestFetchCAChainP7B(t *testing.T) {
+	t.Parallel()
+
+	bRoot, sRoot := CreateBackendWithStorage(t)
+	resp, err := CBWrite(bRoot, sRoot, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	b, s := CreateBackendWithStorage(t)
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "int example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+
+	resp, err = CBWrite(bRoot, sRoot, "root/sign-intermediate", map[string]interface{}{
+		"csr":    resp.Data["csr"],
+		"format": "pem_bundle",
+		"ttl":    "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/sign-intermediate")
+	intCert := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "intermediate/set-signed", map[string]interface{}{
+		"certificate": resp.Data["certificate"],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/set-signed")
+
+	resp, err = CBRead(b, s, "ca_chain/p7b")
+	requireSuccessNonNilResponse(t, resp, err, "ca_chain/p7b")
+	require.Equal(t, "application/x-pkcs7-certificates", resp.Data[logical.HTTPContentType])
+	require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+	p7bDer := resp.Data[logical.HTTPRawBody].([]byte)
+
+	// The bundle is a degenerate SignedData carrying the chain in order.
+	var contentInfo pkcs7ContentInfo
+	rest, err := asn1.Unmarshal(p7bDer, &contentInfo)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.True(t, contentInfo.ContentType.Equal(oidPKCS7SignedData))
+
+	var signedData pkcs7CertsSignedData
+	rest, err = asn1.Unmarshal(contentInfo.Content.Bytes, &signedData)
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.Equal(t, 1, signedData.Version)
+	require.Empty(t, signedData.SignerInfos)
+	require.Equal(t, 0, signedData.Certificates.Tag)
+
+	var certs [][]byte
+	rest = signedData.Certificates.Bytes
+	for len(rest) > 0 {
+		var cert asn1.RawValue
+		rest, err = asn1.Unmarshal(rest, &cert)
+		require.NoError(t, err)
+		certs = append(certs, cert.FullBytes)
+	}
+	require.Equal(t, [][]byte{intCert.Raw, rootCert.Raw}, certs)
+
+	resp, err = CBRead(b, s, "ca_chain/p7b/pem")
+	requireSuccessNonNilResponse(t, resp, err, "ca_chain/p7b/pem")
+	require.Equal(t, "application/x-pem-file", resp.Data[logical.HTTPContentType])
+	block, rest := pem.Decode(resp.Data[logical.HTTPRawBody].([]byte))
+	require.NotNil(t, block)
+	require.Empty(t, rest)
+	require.Equal(t, "PKCS7", block.Type)
+	require.Equal(t, p7bDer, block.Bytes)
+}
+
+func TestFetchCAInfo(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// Without an issuer, the reason is reported.
+	resp, err := CBRead(b, s, "ca/info")
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBRead(b, s, "ca/info")
+	requireSuccessNonNilResponse(t, resp, err, "ca/info")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("ca/info"), logical.ReadOperation), resp, true)
+
+	fingerprint := sha256.Sum256(rootCert.Raw)
+	require.Equal(t, serialFromCert(rootCert), resp.Data["serial_number"])
+	require.Equal(t, "CN=root example.com", resp.Data["subject"])
+	require.Equal(t, certutil.GetHexFormatted(rootCert.SubjectKeyId, ":"), resp.Data["subject_key_id"])
+	require.Equal(t, certutil.GetHexFormatted(rootCert.AuthorityKeyId, ":"), resp.Data["authority_key_id"])
+	require.Equal(t, certutil.GetHexFormatted(fingerprint[:], ":"), resp.Data["sha256_fingerprint"])
+	require.Equal(t, rootCert.NotBefore, resp.Data["not_before"])
+	require.Equal(t, rootCert.NotAfter, resp.Data["not_after"])
+	require.NotContains(t, resp.Data, "certificate")
+}
+
+func TestFetchCAKeyInfo(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// Without an issuer, the reason is reported.
+	resp, err := CBRead(b, s, "ca/key-info")
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+		"key_bits":    384,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+	rootIssuerID := resp.Data["issuer_id"].(issuerID)
+	rootKeyID := resp.Data["key_id"].(keyID)
+
+	resp, err = CBRead(b, s, "ca/key-info")
+	requireSuccessNonNilResponse(t, resp, err, "ca/key-info")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("ca/key-info"), logical.ReadOperation), resp, true)
+
+	fingerprint := sha256.Sum256(rootCert.RawSubjectPublicKeyInfo)
+	require.Equal(t, map[string]interface{}{
+		"key_type":          "ec",
+		"key_bits":          384,
+		"public_key_sha256": certutil.GetHexFormatted(fingerprint[:], ":"),
+		"issuer_id":         rootIssuerID.String(),
+		"key_id":            rootKeyID.String(),
+	}, resp.Data)
+}
+
+func TestFetchCAChainVerify(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBRead(b, s, "ca_chain/verify")
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+
+	for _, name := range []string{"root1", "root2"} {
+		resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"key_type":    "ec",
+			"ttl":         "8h",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	}
+
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "intermediate example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+	resp, err = CBWrite(b, s, "issuer/root1/sign-intermediate", map[string]interface{}{
+		"csr":         resp.Data["csr"],
+		"common_name": "intermediate example.com",
+		"ttl":         "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root1/sign-intermediate")
+	resp, err = CBWrite(b, s, "intermediate/set-signed", map[string]interface{}{
+		"certificate": resp.Data["certificate"],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/set-signed")
+	intId := resp.Data["imported_issuers"].([]string)[0]
+	_, err = CBWrite(b, s, "issuer/"+intId, map[string]interface{}{
+		"issuer_name": "int",
+	})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": "int",
+	})
+	require.NoError(t, err)
+
+	verify := func(t *testing.T, manualChain string) ([]string, bool) {
+		t.Helper()
+		_, err := CBPatch(b, s, "issuer/int", map[string]interface{}{
+			"manual_chain": manualChain,
+		})
+		require.NoError(t, err)
+
+		resp, err := CBRead(b, s, "ca_chain/verify")
+		requireSuccessNonNilResponse(t, resp, err, "ca_chain/verify")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("ca_chain/verify"), logical.ReadOperation), resp, true)
+		return resp.Data["problems"].([]string), resp.Data["valid"].(bool)
+	}
+
+	// Complete and partial chains are both valid.
+	for _, manualChain := range []string{"self,root1", "self"} {
+		problems, valid := verify(t, manualChain)
+		require.True(t, valid, "chain %v", manualChain)
+		require.Empty(t, problems, "chain %v", manualChain)
+	}
+
+	problems, valid := verify(t, "self,root2")
+	require.False(t, valid)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "names CN=root1 example.com as its issuer")
+
+	problems, valid = verify(t, "self,root2,root1")
+	require.False(t, valid)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "out of order, at position 2")
+	require.Contains(t, problems[1], "self-signed root")
+}
+
+func TestFetchCertRevocationReason(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	issue := func(t *testing.T, cn string) string {
+		t.Helper()
+		resp, err := CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": cn,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		return resp.Data["serial_number"].(string)
+	}
+
+	revoked := issue(t, "revoked.example.com")
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	held := issue(t, "held.example.com")
+	resp, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": held})
+	requireSuccessNonNilResponse(t, resp, err, "hold")
+
+	_, _, csrPem := generateCSR(t, &x509.CertificateRequest{}, "ec", 256)
+	resp, err = CBWrite(b, s, "sign/example", map[string]interface{}{
+		"common_name": "superseded.example.com",
+		"csr":         csrPem,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "sign/example")
+	superseded := resp.Data["serial_number"].(string)
+	resp, err = CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key": string(pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: parseCert(t, resp.Data["certificate"].(string)).RawSubjectPublicKeyInfo,
+		})),
+		"reason": "superseded",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+
+	// An entry revoked before reasons were recorded.
+	legacy := issue(t, "legacy.example.com")
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": legacy})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	entry, err := s.Get(ctx, "revoked/"+normalizeSerial(legacy))
+	require.NoError(t, err)
+	var revInfo revocationInfo
+	require.NoError(t, entry.DecodeJSON(&revInfo))
+	revInfo.ReasonRecorded = false
+	entry, err = logical.StorageEntryJSON("revoked/"+normalizeSerial(legacy), revInfo)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, entry))
+
+	for serial, expected := range map[string]string{
+		revoked:    "unspecified",
+		held:       "certificateHold",
+		superseded: "superseded",
+	} {
+		resp, err = CBRead(b, s, "cert/"+serial)
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+		require.Equal(t, expected, resp.Data["revocation_reason_string"], "serial %v", serial)
+		require.Equal(t, expected, revocationReasonNames[resp.Data["revocation_reason"].(int)])
+	}
+
+	for _, serial := range []string{legacy, issue(t, "valid.example.com")} {
+		resp, err = CBRead(b, s, "cert/"+serial)
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+		require.NotContains(t, resp.Data, "revocation_reason")
+		require.NotContains(t, resp.Data, "revocation_reason_string")
+	}
+}
+
+func TestFetchCertLegacyRevocationTime(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "legacy.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	// Entries written before the UTC revocation time was recorded only hold
+	// the integer revocation time.
+	entry, err := s.Get(ctx, "revoked/"+normalizeSerial(serial))
+	require.NoError(t, err)
+	var revInfo revocationInfo
+	require.NoError(t, entry.DecodeJSON(&revInfo))
+	revInfo.RevocationTimeUTC = time.Time{}
+	entry, err = logical.StorageEntryJSON("revoked/"+normalizeSerial(serial), revInfo)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, entry))
+
+	expected := time.Unix(revInfo.RevocationTime, 0).UTC().Format(time.RFC3339Nano)
+	resp, err = CBRead(b, s, "cert/"+serial)
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	require.Equal(t, revInfo.RevocationTime, resp.Data["revocation_time"])
+	require.Equal(t, expected, resp.Data["revocation_time_rfc3339"])
+}
+
+func TestFetchCertContiguousSerial(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+	certificate := resp.Data["certificate"].(string)
+
+	// As printed by openssl x509 -serial.
+	contiguous := strings.ToUpper(strings.ReplaceAll(serial, ":", ""))
+	decimal := parseCert(t, certificate).SerialNumber.String()
+	for _, path := range []string{"cert/" + serial, "cert/" + contiguous, "cert/" + strings.ToLower(contiguous), "cert/" + strings.ReplaceAll(serial, ":", "-")} {
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		require.Equal(t, certificate, resp.Data["certificate"], "reading %v", path)
+		require.Equal(t, serial, resp.Data["serial_number"], "reading %v", path)
+		require.Equal(t, decimal, resp.Data["serial_number_decimal"], "reading %v", path)
+	}
+
+	resp, err = CBRead(b, s, "cert/"+contiguous+"/raw/pem")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+contiguous+"/raw/pem")
+	require.Equal(t, certificate, string(resp.Data[logical.HTTPRawBody].([]byte)))
+
+	// Odd-length serials gain a leading zero octet.
+	require.Equal(t, "0A:BC:DE", splitContiguousSerial("ABCDE"))
+	require.Equal(t, "ab", splitContiguousSerial("ab"))
+
+	// Storage keys are derived from serial numbers as given.
+	require.Equal(t, "abcde", normalizeSerial("ABCDE"))
+}
+
+func TestFetchCertWeakAlgorithmWarnings(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+	ctx := context.Background()
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	p256 := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"key_bits":       224,
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "p224.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	p224 := resp.Data["serial_number"].(string)
+
+	// A legacy certificate with a SHA-1 signature and a 1024-bit RSA key,
+	// as could have been imported long ago.
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:       big.NewInt(0x5a1),
+		Subject:            pkix.Name{CommonName: "legacy.example.com"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: x509.SHA1WithRSA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	legacy := serialFromBigInt(tmpl.SerialNumber)
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{
+		Key:   "certs/" + normalizeSerial(legacy),
+		Value: der,
+	}))
+
+	codes := func(t *testing.T, serial string) []string {
+		t.Helper()
+		resp, err := CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+			"structured_warnings": true,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+
+		structured := resp.Data["structured_warnings"].([]structuredWarning)
+		require.Len(t, structured, len(resp.Warnings))
+		var ret []string
+		for _, warning := range structured {
+			ret = append(ret, warning.Code)
+		}
+		return ret
+	}
+
+	require.Equal(t, []string{warningCodeWeakKey}, codes(t, p224))
+	require.Equal(t, []string{warningCodeDeprecatedSignature, warningCodeWeakKey}, codes(t, legacy))
+	require.Empty(t, codes(t, p256))
+
+	// Plain warnings are returned without structured_warnings.
+	resp, err = CBRead(b, s, "cert/"+p224)
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+p224)
+	require.Len(t, resp.Warnings, 1)
+	require.Contains(t, resp.Warnings[0], "P-224")
+	require.NotContains(t, resp.Data, "structured_warnings")
+}
+
+func TestFetchCertIfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+	notBefore := parseCert(t, resp.Data["certificate"].(string)).NotBefore
+
+	readSince := func(t *testing.T, path string, since time.Time) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+			Headers: map[string][]string{
+				headerIfModifiedSince: {since.Format(time.RFC1123)},
+			},
+		})
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		return resp
+	}
+
+	paths := []string{"cert/" + serial, "cert/" + serial + "/raw", "cert/" + serial + "/raw/pem"}
+	for _, path := range paths {
+		resp = readSince(t, path, notBefore.Add(time.Minute))
+		require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.Equal(t, []string{notBefore.UTC().Format(http.TimeFormat)}, resp.Headers[headerLastModified])
+
+		resp = readSince(t, path, notBefore.Add(-time.Minute))
+		require.NotEqual(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+	}
+
+	// So are certificates released from hold since the last complete CRL.
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "held.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	held := resp.Data["serial_number"].(string)
+	resp, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": held})
+	requireSuccessNonNilResponse(t, resp, err, "hold")
+	_, err = CBWrite(b, s, "release", map[string]interface{}{"serial_number": held})
+	require.NoError(t, err)
+	resp = readSince(t, "cert/"+held, time.Now().Add(time.Hour))
+	require.NotEqual(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode])
+	require.Zero(t, resp.Data["revocation_time"])
+
+	// Revoked certificates are always returned in full.
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	for _, path := range paths {
+		resp = readSince(t, path, time.Now().Add(time.Hour))
+		require.NotEqual(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+	}
+	resp = readSince(t, "cert/"+serial, time.Now().Add(time.Hour))
+	require.NotZero(t, resp.Data["revocation_time"])
+}
+
+func TestFetchCAAndCRLETag(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	read := func(t *testing.T, path string, headers map[string][]string) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+			Headers:    headers,
+		})
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		return resp
+	}
+
+	// The entity tag covers the DER encoding, suffixed for each other
+	// representation of it.
+	withSuffix := func(etag string, suffix string) string {
+		return strings.TrimSuffix(etag, `"`) + suffix + `"`
+	}
+	rootETag := derETag(rootCert.Raw)
+	for path, etag := range map[string]string{
+		"ca":               rootETag,
+		"ca/pem":           withSuffix(rootETag, "-pem"),
+		"ca_chain":         rootETag,
+		"ca_chain/p7b":     withSuffix(rootETag, "-pkcs7"),
+		"ca_chain/p7b/pem": withSuffix(rootETag, "-pkcs7-pem"),
+	} {
+		resp = read(t, path, nil)
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.Equal(t, []string{etag}, resp.Headers[headerETag], "reading %v", path)
+	}
+
+	resp = read(t, "crl", nil)
+	crlETag := derETag(resp.Data[logical.HTTPRawBody].([]byte))
+	require.Equal(t, []string{crlETag}, resp.Headers[headerETag])
+	resp = read(t, "crl/pem", nil)
+	crlPEMETag := withSuffix(crlETag, "-pem")
+	require.Equal(t, []string{crlPEMETag}, resp.Headers[headerETag])
+
+	for path, etag := range map[string]string{"ca": rootETag, "crl/pem": crlPEMETag} {
+		for _, header := range []string{etag, `"other", W/` + etag, "*"} {
+			resp = read(t, path, map[string][]string{headerIfNoneMatch: {header}})
+			require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v with %v", path, header)
+			require.Empty(t, resp.Data[logical.HTTPRawBody])
+			require.Equal(t, []string{etag}, resp.Headers[headerETag])
+		}
+
+		// A mismatched entity tag takes precedence over If-Modified-Since.
+		resp = read(t, path, map[string][]string{
+			headerIfNoneMatch:     {`"other"`},
+			headerIfModifiedSince: {time.Now().Add(time.Hour).Format(time.RFC1123)},
+		})
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.NotEmpty(t, resp.Data[logical.HTTPRawBody])
+	}
+
+	// Negotiated representations are tagged by the one served and vary on
+	// the Accept header. Revalidating the PEM body with the DER entity tag
+	// returns the PEM body in full.
+	pemAccept := map[string][]string{headerAccept: {"application/x-pem-file"}}
+	for path, etags := range map[string][2]string{
+		"ca":       {rootETag, withSuffix(rootETag, "-pem")},
+		"ca_chain": {rootETag, withSuffix(rootETag, "-pem")},
+		"crl":      {crlETag, crlPEMETag},
+	} {
+		resp = read(t, path, nil)
+		require.Equal(t, []string{etags[0]}, resp.Headers[headerETag], "reading %v", path)
+		require.Equal(t, []string{headerAccept}, resp.Headers[headerVary], "reading %v", path)
+
+		resp = read(t, path, pemAccept)
+		require.Equal(t, []string{etags[1]}, resp.Headers[headerETag], "reading %v", path)
+		require.Equal(t, []string{headerAccept}, resp.Headers[headerVary], "reading %v", path)
+
+		resp = read(t, path, map[string][]string{
+			headerAccept:      pemAccept[headerAccept],
+			headerIfNoneMatch: {etags[0]},
+		})
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.NotEmpty(t, resp.Data[logical.HTTPRawBody], "reading %v", path)
+		require.Equal(t, []string{etags[1]}, resp.Headers[headerETag], "reading %v", path)
+
+		resp = read(t, path, map[string][]string{
+			headerAccept:      pemAccept[headerAccept],
+			headerIfNoneMatch: {etags[1]},
+		})
+		require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.Equal(t, []string{headerAccept}, resp.Headers[headerVary], "reading %v", path)
+	}
+
+	// Paths with a fixed representation don't vary.
+	resp = read(t, "ca/pem", nil)
+	require.Empty(t, resp.Headers[headerVary])
+
+	// Rebuilding the CRL changes its entity tag.
+	_, err = CBRead(b, s, "crl/rotate")
+	require.NoError(t, err)
+	resp = read(t, "crl", map[string][]string{headerIfNoneMatch: {crlETag}})
+	require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+	require.NotEqual(t, []string{crlETag}, resp.Headers[headerETag])
+}
+
+func TestFetchCAChainIfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"issuer_name": "root",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "intermediate example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+	csr := resp.Data["csr"].(string)
+
+	resp, err = CBWrite(b, s, "root/sign-intermediate", map[string]interface{}{
+		"csr":    csr,
+		"format": "pem_bundle",
+		"ttl":    "4h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/sign-intermediate")
+
+	resp, err = CBWrite(b, s, "intermediate/set-signed", map[string]interface{}{
+		"certificate": resp.Data["certificate"],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/set-signed")
+	intId := resp.Data["imported_issuers"].([]string)[0]
+
+	resp, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": intId,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuers")
+
+	readSince := func(t *testing.T, path string, since time.Time) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+			Headers: map[string][]string{
+				headerIfModifiedSince: {since.Format(time.RFC1123)},
+			},
+		})
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		return resp
+	}
+
+	paths := []string{"ca_chain", "cert/ca_chain", "ca_chain/json", "ca_chain/p7b", "ca_chain/p7b/pem"}
+	afterSetup := time.Now().Add(2 * time.Second)
+	for _, path := range paths {
+		resp = readSince(t, path, afterSetup)
+		require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		require.NotEmpty(t, resp.Headers[headerLastModified], "reading %v", path)
+
+		resp = readSince(t, path, time.Now().Add(-time.Hour))
+		require.NotEqual(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+	}
+
+	// Modifying the root, which is part of the default issuer's chain, marks
+	// the chain as modified even though the default issuer itself is not.
+	time.Sleep(3 * time.Second)
+	resp, err = CBPatch(b, s, "issuer/root", map[string]interface{}{
+		"issuer_name": "old-root",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root")
+
+	for _, path := range paths {
+		resp = readSince(t, path, afterSetup)
+		require.NotEqual(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+	}
+	resp = readSince(t, "ca", afterSetup)
+	require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode])
+}
+
+func TestFetchCAContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootCert := parseCert(t, resp.Data["certificate"].(string))
+
+	read := func(t *testing.T, path string, accept string) *logical.Response {
+		t.Helper()
+		req := &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+		}
+		if accept != "" {
+			req.Headers = map[string][]string{headerAccept: {accept}}
+		}
+		resp, err := b.HandleRequest(context.Background(), req)
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+		return resp
+	}
+
+	// Without a preference for PEM, the DER encoding is returned as before.
+	for _, accept := range []string{"", "*/*", "application/pkix-cert", "application/pkix-cert, application/x-pem-file", "application/x-pem-file;q=0, */*", "text/html"} {
+		resp = read(t, "ca", accept)
+		require.Equal(t, "application/pkix-cert", resp.Data[logical.HTTPContentType], "accepting %q", accept)
+		require.Equal(t, rootCert.Raw, resp.Data[logical.HTTPRawBody], "accepting %q", accept)
+	}
+
+	for _, accept := range []string{"application/x-pem-file", "application/pem-certificate-chain", "text/html, application/X-PEM-File;q=0.5, application/pkix-cert"} {
+		resp = read(t, "ca", accept)
+		require.Equal(t, "application/pem-certificate-chain", resp.Data[logical.HTTPContentType], "accepting %q", accept)
+		require.Equal(t, rootCert, parseCert(t, string(resp.Data[logical.HTTPRawBody].([]byte))), "accepting %q", accept)
+	}
+
+	resp = read(t, "ca_chain", "application/x-pem-file")
+	require.Equal(t, "application/pem-certificate-chain", resp.Data[logical.HTTPContentType])
+	require.Equal(t, rootCert, parseCert(t, string(resp.Data[logical.HTTPRawBody].([]byte))))
+
+	for _, path := range []string{"crl", "crl/delta"} {
+		resp = read(t, path, "")
+		require.Equal(t, "application/pkix-crl", resp.Data[logical.HTTPContentType], "reading %v", path)
+		der := resp.Data[logical.HTTPRawBody].([]byte)
+
+		resp = read(t, path, "application/x-pem-file")
+		require.Equal(t, "application/x-pem-file", resp.Data[logical.HTTPContentType], "reading %v", path)
+		block, rest := pem.Decode(resp.Data[logical.HTTPRawBody].([]byte))
+		require.NotNil(t, block, "reading %v", path)
+		require.Empty(t, rest, "reading %v", path)
+		require.Equal(t, "X509 CRL", block.Type, "reading %v", path)
+		require.Equal(t, der, block.Bytes, "reading %v", path)
+	}
+
+	// Paths with an explicit encoding ignore the header.
+	resp = read(t, "ca/pem", "application/pkix-cert")
+	require.Equal(t, "application/pem-certificate-chain", resp.Data[logical.HTTPContentType])
+}
+
+func TestFetchCertRevocationStatus(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for _, cn := range []string{"valid.example.com", "revoked.example.com", "held.example.com"} {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": cn,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+	valid, revoked, held := serials[0], serials[1], serials[2]
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	revokedAt := resp.Data["revocation_time_rfc3339"]
+
+	resp, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": held})
+	requireSuccessNonNilResponse(t, resp, err, "hold")
+
+	// Unknown serial numbers are reported as not revoked.
+	for _, serial := range []string{valid, "01:02:03"} {
+		resp, err = CBRead(b, s, "cert/"+serial+"/revoked")
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/revoked")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial+"/revoked"), logical.ReadOperation), resp, true)
+		require.Equal(t, map[string]interface{}{"revoked": false}, resp.Data)
+	}
+
+	resp, err = CBRead(b, s, "cert/"+revoked+"/revoked")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+revoked+"/revoked")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+revoked+"/revoked"), logical.ReadOperation), resp, true)
+	require.Equal(t, true, resp.Data["revoked"])
+	require.Equal(t, revokedAt, resp.Data["revocation_time_rfc3339"])
+	require.Equal(t, "unspecified", resp.Data["revocation_reason_string"])
+	require.NotContains(t, resp.Data, "certificate")
+
+	resp, err = CBRead(b, s, "cert/"+held+"/revoked")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+held+"/revoked")
+	require.Equal(t, true, resp.Data["revoked"])
+	require.Equal(t, "certificateHold", resp.Data["revocation_reason_string"])
+}
+
+func TestFetchCertStatus(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"issuer_name": "root",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootId := resp.Data["issuer_id"].(issuerID).String()
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "other example.com",
+		"issuer_name": "other",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	otherId := resp.Data["issuer_id"].(issuerID).String()
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+		"issuer_ref":     "root",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "roles/other", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+		"issuer_ref":     "other",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/other")
+
+	issue := func(t *testing.T, role string, data map[string]interface{}) (string, string) {
+		t.Helper()
+		resp, err := CBWrite(b, s, "issue/"+role, data)
+		requireSuccessNonNilResponse(t, resp, err, "issue/"+role)
+		return resp.Data["serial_number"].(string), resp.Data["certificate"].(string)
+	}
+
+	readStatus := func(t *testing.T, serial string) *logical.Response {
+		t.Helper()
+		resp, err := CBRead(b, s, "cert/"+serial+"/status")
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/status")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial+"/status"), logical.ReadOperation), resp, true)
+		return resp
+	}
+
+	valid, validPem := issue(t, "example", map[string]interface{}{"common_name": "valid.example.com"})
+	resp = readStatus(t, valid)
+	require.Equal(t, validPem, resp.Data["certificate"])
+	require.Equal(t, rootId, resp.Data["issuer_id"])
+	require.Equal(t, true, resp.Data["chain_valid"])
+	require.Equal(t, false, resp.Data["revoked"])
+	require.Equal(t, false, resp.Data["expired"])
+	require.Empty(t, resp.Data["problems"])
+
+	revoked, _ := issue(t, "example", map[string]interface{}{"common_name": "revoked.example.com"})
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	resp = readStatus(t, revoked)
+	require.Equal(t, true, resp.Data["chain_valid"])
+	require.Equal(t, true, resp.Data["revoked"])
+	require.NotEmpty(t, resp.Data["revocation_time_rfc3339"])
+	require.Equal(t, []string{"certificate has been revoked"}, resp.Data["problems"])
+
+	expired, _ := issue(t, "example", map[string]interface{}{"common_name": "expired.example.com", "ttl": "2s"})
+	time.Sleep(3 * time.Second)
+	resp = readStatus(t, expired)
+	require.Equal(t, true, resp.Data["chain_valid"])
+	require.Equal(t, true, resp.Data["expired"])
+	require.Len(t, resp.Data["problems"], 1)
+	require.Contains(t, resp.Data["problems"].([]string)[0], "certificate expired at")
+
+	// Certificates of deleted issuers no longer verify.
+	orphaned, _ := issue(t, "other", map[string]interface{}{"common_name": "orphaned.example.com"})
+	resp, err = CBDelete(b, s, "issuer/other")
+	require.NoError(t, err)
+	resp = readStatus(t, orphaned)
+	require.Equal(t, otherId, resp.Data["issuer_id"])
+	require.Equal(t, false, resp.Data["chain_valid"])
+	require.Equal(t, []string{fmt.Sprintf("issuer %v which signed the certificate has been deleted", otherId)}, resp.Data["problems"])
+
+	// Without an issuance record, the issuer is unknown rather than
+	// searched for.
+	legacy, _ := issue(t, "example", map[string]interface{}{"common_name": "legacy.example.com"})
+	require.NoError(t, s.Delete(context.Background(), certIssuancePrefix+normalizeSerial(legacy)))
+	resp = readStatus(t, legacy)
+	require.NotContains(t, resp.Data, "issuer_id")
+	require.Equal(t, false, resp.Data["chain_valid"])
+	require.Equal(t, []string{"the issuer of the certificate is unknown, as it was not recorded at issuance"}, resp.Data["problems"])
+
+	resp, err = CBRead(b, s, "cert/00:11:22/status")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.Data[logical.HTTPStatusCode])
+}
+
+func TestFetchRevocationEntry(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootId := resp.Data["issuer_id"].(issuerID).String()
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	issue := func(t *testing.T) (string, []byte) {
+		t.Helper()
+		resp, err := CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "test.example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		return resp.Data["serial_number"].(string), parseCert(t, resp.Data["certificate"].(string)).Raw
+	}
+
+	readEntry := func(t *testing.T, serial string) *logical.Response {
+		t.Helper()
+		resp, err := CBRead(b, s, "cert/"+serial+"/revocation")
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/revocation")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial+"/revocation"), logical.ReadOperation), resp, true)
+		return resp
+	}
+
+	valid, _ := issue(t)
+	resp = readEntry(t, valid)
+	require.Equal(t, map[string]interface{}{"revoked": false}, resp.Data)
+
+	revoked, der := issue(t)
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": revoked})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	resp = readEntry(t, revoked)
+	require.Equal(t, true, resp.Data["revoked"])
+	require.Equal(t, base64.StdEncoding.EncodeToString(der), resp.Data["certificate_bytes"])
+	require.Equal(t, rootId, resp.Data["issuer_id"])
+	revocationTimeUTC := resp.Data["revocation_time_utc"].(time.Time)
+	require.False(t, revocationTimeUTC.IsZero())
+	require.Equal(t, revocationTimeUTC.Unix(), resp.Data["revocation_time"])
+	require.Equal(t, false, resp.Data["on_hold"])
+	require.Equal(t, 0, resp.Data["revocation_reason"])
+	require.Equal(t, true, resp.Data["reason_recorded"])
+	require.Equal(t, false, resp.Data["ocsp_only"])
+
+	held, _ := issue(t)
+	resp, err = CBWrite(b, s, "hold", map[string]interface{}{"serial_number": held})
+	requireSuccessNonNilResponse(t, resp, err, "hold")
+	resp = readEntry(t, held)
+	require.Equal(t, true, resp.Data["on_hold"])
+}
+
+func TestFetchCertBothEncodings(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+		"ttl":            "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "test.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": serial})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	resp, err = CBRead(b, s, "cert/"+serial+"/raw")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/raw")
+	der := resp.Data[logical.HTTPRawBody].([]byte)
+
+	resp, err = CBRead(b, s, "cert/"+serial+"/raw/pem")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/raw/pem")
+	pemCert := string(resp.Data[logical.HTTPRawBody].([]byte))
+
+	resp, err = CBRead(b, s, "cert/"+serial+"/both")
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/both")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial+"/both"), logical.ReadOperation), resp, true)
+	require.Equal(t, pemCert, resp.Data["certificate_pem"])
+	require.Equal(t, base64.StdEncoding.EncodeToString(der), resp.Data["certificate_der_base64"])
+	require.NotZero(t, resp.Data["revocation_time"])
+	require.Equal(t, "unspecified", resp.Data["revocation_reason_string"])
+
+	resp, err = CBRead(b, s, "cert/01:02:03/both")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.Data[logical.HTTPStatusCode])
+}
+
+func TestFetchExpiredCA(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "1s",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	// Sleep long enough to expire the root.
+	time.Sleep(2 * time.Second)
+
+	// By default, the expired CA is served as-is.
+	resp, err := CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Empty(t, resp.Warnings)
+
+	resp, err = CBRead(b, s, "ca/pem")
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Data[logical.HTTPStatusCode])
+
+	resp, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"expired_ca_warning":      true,
+		"expired_ca_grace_period": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuers")
+	require.Equal(t, true, resp.Data["expired_ca_warning"])
+	require.Equal(t, "1h", resp.Data["expired_ca_grace_period"])
+	require.NotEmpty(t, resp.Data["default"])
+
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Len(t, resp.Warnings, 1)
+	require.Contains(t, resp.Warnings[0], "CA certificate expired")
+
+	resp, err = CBRead(b, s, "cert/ca_chain")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca_chain")
+	require.Len(t, resp.Warnings, 1)
+
+	// Still within the grace period.
+	resp, err = CBRead(b, s, "ca/pem")
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Data[logical.HTTPStatusCode])
+	require.NotEmpty(t, resp.Data[logical.HTTPRawBody])
+
+	// Once the grace period has elapsed, raw fetches are refused.
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"expired_ca_grace_period": "0s",
+	})
+	require.NoError(t, err)
+
+	for _, path := range []string{"ca", "ca/pem", "ca_chain", "cert/ca/raw"} {
+		resp, err = CBRead(b, s, path)
+		require.NoError(t, err, path)
+		require.Equal(t, 410, resp.Data[logical.HTTPStatusCode], path)
+		require.Empty(t, resp.Data[logical.HTTPRawBody], path)
+	}
+
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"expired_ca_grace_period": "not-a-duration",
+	})
+	require.Error(t, err)
+}
+
+func TestFetchCAExpiryWarning(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "30h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	issuerId := resp.Data["issuer_id"].(issuerID).String()
+
+	// Without a threshold, no warning is given.
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Empty(t, resp.Warnings)
+
+	resp, err = CBPatch(b, s, "issuer/"+issuerId, map[string]interface{}{
+		"expiry_warning_threshold": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer patch")
+	require.Equal(t, int64(3600), resp.Data["expiry_warning_threshold"])
+
+	// The issuer expires after the threshold.
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Empty(t, resp.Warnings)
+
+	resp, err = CBPatch(b, s, "issuer/"+issuerId, map[string]interface{}{
+		"expiry_warning_threshold": "30d",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer patch")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issuer/"+issuerId), logical.PatchOperation), resp, true)
+
+	for _, path := range []string{"cert/ca", "cert/ca_chain"} {
+		resp, err = CBRead(b, s, path)
+		requireSuccessNonNilResponse(t, resp, err, path)
+		require.Equal(t, []string{"issuing CA expires in 1 days"}, resp.Warnings, path)
+	}
+
+	// Raw responses carry neither the warning nor any other changes.
+	for _, path := range []string{"ca", "ca/pem", "ca_chain", "cert/ca/raw"} {
+		resp, err = CBRead(b, s, path)
+		require.NoError(t, err, path)
+		require.Equal(t, 200, resp.Data[logical.HTTPStatusCode], path)
+		require.NotEmpty(t, resp.Data[logical.HTTPRawBody], path)
+		require.Empty(t, resp.Warnings, path)
+	}
+
+	// The threshold is kept by patches of other fields, and is reset when
+	// the issuer is updated without it.
+	resp, err = CBPatch(b, s, "issuer/"+issuerId, map[string]interface{}{
+		"issuer_name": "root",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer patch")
+	require.Equal(t, int64(30*86400), resp.Data["expiry_warning_threshold"])
+
+	resp, err = CBWrite(b, s, "issuer/"+issuerId, map[string]interface{}{
+		"issuer_name":             "root",
+		"leaf_not_after_behavior": "err",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer update")
+	require.Equal(t, int64(0), resp.Data["expiry_warning_threshold"])
+
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Empty(t, resp.Warnings)
+
+	_, err = CBPatch(b, s, "issuer/"+issuerId, map[string]interface{}{
+		"expiry_warning_threshold": "-1h",
+	})
+	require.Error(t, err)
+
+	// Raw fetches log the warning at most once per issuer per interval.
+	now := time.Now()
+	id := issuerID("unlogged-issuer")
+	require.True(t, b.shouldLogCAExpiryWarning(id, now))
+	require.False(t, b.shouldLogCAExpiryWarning(id, now.Add(time.Minute)))
+	require.True(t, b.shouldLogCAExpiryWarning(id, now.Add(caExpiryWarningLogInterval)))
+}
+
+func TestFetchCertExtensions(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "config/urls", map[string]interface{}{
+		"ocsp_servers": "http://ocsp.example.com",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allowed_domains":    "example.com",
+		"allow_subdomains":   true,
+		"ttl":                "1h",
+		"key_type":           "ec",
+		"policy_identifiers": "1.2.3.4",
+	})
+	require.NoError(t, err)
+
+	resp, err := CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name": "leaf.example.com",
+		"ip_sans":     "127.0.0.1",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/test")
+	serial := resp.Data["serial_number"].(string)
+	leaf := parseCert(t, resp.Data["certificate"].(string))
+
+	// Extensions are not returned by default.
+	resp, err = CBRead(b, s, "cert/"+serial)
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	require.NotContains(t, resp.Data, "extensions")
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"include_extensions": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+
+	extensions := resp.Data["extensions"].([]map[string]interface{})
+	require.Len(t, extensions, len(leaf.Extensions))
+
+	byName := map[string]map[string]interface{}{}
+	for index, ext := range extensions {
+		require.Equal(t, leaf.Extensions[index].Id.String(), ext["oid"])
+		require.Equal(t, leaf.Extensions[index].Critical, ext["critical"])
+		if name, ok := ext["name"]; ok {
+			byName[name.(string)] = ext
+		}
+	}
+
+	require.Contains(t, byName["key_usage"]["value"], "DigitalSignature")
+	require.Equal(t, []string{"ServerAuth", "ClientAuth"}, byName["ext_key_usage"]["value"])
+	require.NotContains(t, byName, "basic_constraints")
+	require.Equal(t, certutil.GetHexFormatted(leaf.SubjectKeyId, ":"), byName["subject_key_identifier"]["value"])
+	require.Equal(t, certutil.GetHexFormatted(leaf.AuthorityKeyId, ":"), byName["authority_key_identifier"]["value"])
+	require.Equal(t, []string{"1.2.3.4"}, byName["certificate_policies"]["value"])
+
+	sans := byName["subject_alt_name"]["value"].(map[string]interface{})
+	require.Equal(t, []string{"leaf.example.com"}, sans["dns_names"])
+	require.Equal(t, []string{"127.0.0.1"}, sans["ip_addresses"])
+
+	aia := byName["authority_info_access"]["value"].(map[string]interface{})
+	require.Equal(t, []string{"http://ocsp.example.com"}, aia["ocsp_servers"])
+
+	// The CA itself can be inspected too.
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/ca", map[string]interface{}{
+		"include_extensions": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	found := false
+	for _, ext := range resp.Data["extensions"].([]map[string]interface{}) {
+		if ext["name"] == "basic_constraints" {
+			found = true
+			require.Equal(t, true, ext["value"].(map[string]interface{})["is_ca"])
+		}
+	}
+	require.True(t, found)
+}
+
+func TestFetchCertURLs(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	// The root predates the URL configuration, so embeds none.
+	resp, err := CBReq(b, s, logical.ReadOperation, "cert/ca", map[string]interface{}{
+		"include_urls": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Equal(t, []string{}, resp.Data["ocsp_servers"])
+	require.Equal(t, []string{}, resp.Data["issuing_certificates"])
+	require.Equal(t, []string{}, resp.Data["crl_distribution_points"])
+
+	_, err = CBWrite(b, s, "config/urls", map[string]interface{}{
+		"ocsp_servers":            "http://ocsp.example.com",
+		"issuing_certificates":    "http://ca.example.com/ca",
+		"crl_distribution_points": "http://ca.example.com/crl",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"ttl":              "1h",
+		"key_type":         "ec",
+	})
+	require.NoError(t, err)
+
+	resp, err = CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name": "leaf.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/test")
+	serial := resp.Data["serial_number"].(string)
+
+	// URLs are not returned by default.
+	resp, err = CBRead(b, s, "cert/"+serial)
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	require.NotContains(t, resp.Data, "ocsp_servers")
+	require.NotContains(t, resp.Data, "issuing_certificates")
+	require.NotContains(t, resp.Data, "crl_distribution_points")
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"include_urls": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+	require.Equal(t, []string{"http://ocsp.example.com"}, resp.Data["ocsp_servers"])
+	require.Equal(t, []string{"http://ca.example.com/ca"}, resp.Data["issuing_certificates"])
+	require.Equal(t, []string{"http://ca.example.com/crl"}, resp.Data["crl_distribution_points"])
+
+	// They can be compared directly against the mount's configuration.
+	urls, err := CBRead(b, s, "config/urls")
+	requireSuccessNonNilResponse(t, urls, err, "config/urls")
+	for _, field := range []string{"ocsp_servers", "issuing_certificates", "crl_distribution_points"} {
+		require.Equal(t, urls.Data[field], resp.Data[field], "field %v", field)
+	}
+}
+
+func TestFetchCertMissingBehavior(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allow_any_name": true,
+		"ttl":            "1h",
+		"key_type":       "ec",
+	})
+	require.NoError(t, err)
+
+	resp, err := CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name": "leaf.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/test")
+	serial := resp.Data["serial_number"].(string)
+
+	const unknownSerial = "01:02:03:04"
+
+	// By default, unknown serials return a 404 with an explicit error.
+	requireNotFound := func(t *testing.T, resp *logical.Response, err error) {
+		t.Helper()
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusNotFound, resp.Data[logical.HTTPStatusCode])
+		require.Equal(t, "application/json", resp.Data[logical.HTTPContentType])
+
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(resp.Data[logical.HTTPRawBody].(string)), &body))
+		require.Equal(t, "certificate with serial "+unknownSerial+" not found", body.Data["error"])
 	}
 
-	// Check that each expected DNS name is contained within the actual DNS names
-	for _, expectedDNSName := range expectedDetails["dns_names"].([]string) {
-		require.Contains(t, actualDNSNamesStr, expectedDNSName, "Expected DNS name not found")
+	resp, err = CBRead(b, s, "cert/"+unknownSerial)
+	requireNotFound(t, resp, err)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+unknownSerial, map[string]interface{}{
+		"missing_behavior": "notfound",
+	})
+	requireNotFound(t, resp, err)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+unknownSerial, map[string]interface{}{
+		"missing_behavior": "empty-ok",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+unknownSerial)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+unknownSerial), logical.ReadOperation), resp, true)
+	require.Equal(t, map[string]interface{}{"found": false}, resp.Data)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"missing_behavior": "empty-ok",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+	require.Equal(t, true, resp.Data["found"])
+	require.NotEmpty(t, resp.Data["certificate"])
+
+	_, err = CBReq(b, s, logical.ReadOperation, "cert/"+serial, map[string]interface{}{
+		"missing_behavior": "ignore",
+	})
+	require.Error(t, err)
+
+	// The raw paths keep returning an empty 204 response.
+	resp, err = CBRead(b, s, "cert/"+unknownSerial+"/raw")
+	require.NoError(t, err)
+	require.Equal(t, 204, resp.Data[logical.HTTPStatusCode])
+}
+
+func TestFetchLatestCert(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allow_any_name": true,
+		"ttl":            "1h",
+		"key_type":       "ec",
+	})
+	require.NoError(t, err)
+
+	issue := func(data map[string]interface{}) string {
+		resp, err := CBWrite(b, s, "issue/test", data)
+		requireSuccessNonNilResponse(t, resp, err, "issue/test")
+		// NotBefore has second granularity; keep issuances distinguishable.
+		time.Sleep(1100 * time.Millisecond)
+		return resp.Data["serial_number"].(string)
 	}
 
-	// Convert key_bits to int before checking
-	keyBits, err := certData["key_bits"].(json.Number).Int64()
-	require.NoError(t, err, "Failed to convert key_bits to int")
-	require.Equal(t, expectedDetails["key_bits"], int(keyBits), "Mismatch in key bits")
+	older := issue(map[string]interface{}{"common_name": "app.example.com"})
+	newer := issue(map[string]interface{}{"common_name": "app.example.com"})
+	sanOnly := issue(map[string]interface{}{
+		"common_name": "other.example.com",
+		"alt_names":   "app.example.com",
+	})
 
-	// Check the rest of the details match
-	require.Equal(t, expectedDetails["common_name"], certData["common_name"], "Mismatch in common name")
-	require.Equal(t, expectedDetails["issuer"], certData["issuer"], "Mismatch in issuer")
-	require.Equal(t, expectedDetails["key_type"], certData["key_type"], "Mismatch in key type")
-	require.Equal(t, expectedDetails["not_after"], certData["not_after"], "Mismatch in not after")
-	require.Equal(t, expectedDetails["not_before"], certData["not_before"], "Mismatch in not before")
+	resp, err := CBReq(b, s, logical.ReadOperation, "certs/latest", map[string]interface{}{
+		"common_name": "app.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/latest")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/latest"), logical.ReadOperation), resp, true)
+	require.Equal(t, newer, resp.Data["serial_number"])
+	require.Equal(t, newer, serialFromCert(parseCert(t, resp.Data["certificate"].(string))))
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "certs/latest", map[string]interface{}{
+		"common_name": "app.example.com",
+		"match_sans":  true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/latest")
+	require.Equal(t, sanOnly, resp.Data["serial_number"])
+
+	// Revoked certificates are skipped in favor of older valid ones.
+	_, err = CBWrite(b, s, "revoke", map[string]interface{}{"serial_number": newer})
+	require.NoError(t, err)
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "certs/latest", map[string]interface{}{
+		"common_name": "app.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "certs/latest")
+	require.Equal(t, older, resp.Data["serial_number"])
+
+	resp, err = CBReq(b, s, logical.ReadOperation, "certs/latest", map[string]interface{}{
+		"common_name": "missing.example.com",
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	_, err = CBReq(b, s, logical.ReadOperation, "certs/latest", map[string]interface{}{})
+	require.Error(t, err)
+}
+
+func TestFindCertsBySKI(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	root := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var leaf *x509.Certificate
+	var leafSerial string
+	for i := 0; i < 3; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		leaf = parseCert(t, resp.Data["certificate"].(string))
+		leafSerial = resp.Data["serial_number"].(string)
+	}
+
+	find := func(t *testing.T, ski string) []string {
+		resp, err := CBReq(b, s, logical.ReadOperation, "certs/find", map[string]interface{}{
+			"ski": ski,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/find")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/find"), logical.ReadOperation), resp, true)
+		return resp.Data["serial_numbers"].([]string)
+	}
+
+	// Both contiguous and colon-separated forms are accepted.
+	require.Equal(t, []string{leafSerial}, find(t, hex.EncodeToString(leaf.SubjectKeyId)))
+	require.Equal(t, []string{leafSerial}, find(t, certutil.GetHexFormatted(leaf.SubjectKeyId, ":")))
+	require.Equal(t, []string{serialFromCert(root)}, find(t, strings.ToUpper(hex.EncodeToString(root.SubjectKeyId))))
+	require.Empty(t, find(t, "00:11:22:33"))
+
+	for _, ski := range []string{"", "xyz", "1a:2"} {
+		resp, err = CBReq(b, s, logical.ReadOperation, "certs/find", map[string]interface{}{
+			"ski": ski,
+		})
+		require.Error(t, err, "ski %q", ski)
+	}
+}
+
+func TestFetchCAWithoutIssuer(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// JSON paths return a descriptive error.
+	for _, path := range []string{"cert/ca", "cert/ca_chain"} {
+		_, err := CBRead(b, s, path)
+		require.Error(t, err, path)
+		require.Contains(t, err.Error(), "no CA configured: this mount has no issuers")
+	}
+
+	// Raw paths return a 404 describing the problem.
+	for _, path := range []string{"ca", "ca/pem", "ca_chain", "cert/ca/raw", "cert/ca/raw/pem"} {
+		resp, err := CBRead(b, s, path)
+		require.NoError(t, err, path)
+		require.NotNil(t, resp, path)
+		require.Equal(t, http.StatusNotFound, resp.Data[logical.HTTPStatusCode], path)
+		require.Contains(t, string(resp.Data[logical.HTTPRawBody].([]byte)), "no CA configured", path)
+	}
+
+	// Issuers without a default are described as such.
+	var issuerIds []issuerID
+	for _, name := range []string{"root-a", "root-b"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name,
+			"ttl":         "8h",
+			"key_type":    "ec",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		issuerIds = append(issuerIds, resp.Data["issuer_id"].(issuerID))
+	}
+
+	_, err := CBDelete(b, s, "issuer/"+issuerIds[0].String())
+	require.NoError(t, err)
+
+	_, err = CBRead(b, s, "cert/ca")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no default or fallback issuer; set one via config/issuers")
+
+	// A fallback issuer is served while no default is set.
+	resp, err := CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"fallback_issuer": issuerIds[1].String(),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/issuers")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("config/issuers"), logical.UpdateOperation), resp, true)
+	require.Equal(t, issuerID(""), resp.Data["default"])
+	require.Equal(t, issuerIds[1], resp.Data["fallback_issuer"])
+
+	resp, err = CBRead(b, s, "issuer/"+issuerIds[1].String()+"/json")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/json")
+	fallbackCert := resp.Data["certificate"].(string)
+
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Equal(t, strings.TrimSpace(fallbackCert), resp.Data["certificate"])
+	require.NotEmpty(t, resp.Warnings)
+
+	resp, err = CBRead(b, s, "ca_chain")
+	requireSuccessNonNilResponse(t, resp, err, "ca_chain")
+	require.Equal(t, 200, resp.Data[logical.HTTPStatusCode])
+
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"fallback_issuer": "default",
+	})
+	require.Error(t, err)
+
+	// The default issuer takes precedence once set.
+	root, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root-c",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, root, err, "root/generate/internal")
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": root.Data["issuer_id"].(issuerID).String(),
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.Equal(t, strings.TrimSpace(root.Data["certificate"].(string)), resp.Data["certificate"])
+	require.Empty(t, resp.Warnings)
+
+	// Deleting the fallback issuer clears it.
+	_, err = CBDelete(b, s, "issuer/"+issuerIds[1].String())
+	require.NoError(t, err)
+	resp, err = CBRead(b, s, "config/issuers")
+	requireSuccessNonNilResponse(t, resp, err, "config/issuers")
+	require.Equal(t, issuerID(""), resp.Data["fallback_issuer"])
+}
+
+func TestFetchCTExport(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	_, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"ttl":         "8h",
+		"key_type":    "ec",
+	})
+	require.NoError(t, err)
+
+	_, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allow_any_name": true,
+		"ttl":            "1h",
+		"key_type":       "ec",
+	})
+	require.NoError(t, err)
+
+	issued := map[string]string{}
+	for _, name := range []string{"a.example.com", "b.example.com"} {
+		resp, err := CBWrite(b, s, "issue/test", map[string]interface{}{
+			"common_name": name,
+			"ip_sans":     "10.0.0.1",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/test")
+		issued[resp.Data["serial_number"].(string)] = resp.Data["certificate"].(string)
+	}
+
+	var exported []map[string]interface{}
+	after := ""
+	pages := 0
+	for {
+		resp, err := CBReq(b, s, logical.ReadOperation, "certs/ct-export", map[string]interface{}{
+			"after": after,
+			"limit": 2,
+		})
+		requireSuccessNonNilResponse(t, resp, err, "certs/ct-export")
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("certs/ct-export"), logical.ReadOperation), resp, true)
+		pages++
+
+		for _, entry := range resp.Data["entries"].([]interface{}) {
+			exported = append(exported, entry.(map[string]interface{}))
+		}
+
+		after = resp.Data["next_cursor"].(string)
+		if after == "" {
+			break
+		}
+		require.Less(t, pages, 5)
+	}
+	require.Equal(t, 2, pages)
+	require.Len(t, exported, 3)
+
+	seen := 0
+	for _, entry := range exported {
+		certPem, ok := issued[entry["serial_number"].(string)]
+		if !ok {
+			require.Equal(t, true, entry["is_ca"])
+			continue
+		}
+		seen++
+
+		cert := parseCert(t, certPem)
+		der, err := base64.StdEncoding.DecodeString(entry["leaf_cert"].(string))
+		require.NoError(t, err)
+		require.Equal(t, cert.Raw, der)
+		require.Equal(t, cert.DNSNames, entry["dns_names"])
+		require.Equal(t, []string{"10.0.0.1"}, entry["ip_addresses"])
+		require.Equal(t, cert.NotAfter.UTC().Format(time.RFC3339), entry["not_after"])
+		require.Equal(t, false, entry["is_ca"])
+	}
+	require.Equal(t, 2, seen)
+
+	_, err = CBReq(b, s, logical.ReadOperation, "certs/ct-export", map[string]interface{}{
+		"limit": -1,
+	})
+	require.Error(t, err)
+}
+
+func TestFetchIssuerRevocationConfig(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root example.com",
+		"issuer_name": "root-a",
+		"key_name":    "root-key",
+		"key_type":    "ec",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	issuerA := resp.Data["issuer_id"].(issuerID)
+
+	_, err = CBWrite(b, s, "config/urls", map[string]interface{}{
+		"crl_distribution_points": "http://crl.example.com/crl",
+		"ocsp_servers":            "http://ocsp.example.com",
+	})
+	require.NoError(t, err)
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"auto_rebuild": true,
+		"enable_delta": true,
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "issuer/root-a/revocation-config")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root-a/revocation-config")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issuer/root-a/revocation-config"), logical.ReadOperation), resp, true)
+	require.Equal(t, issuerA, resp.Data["issuer_id"])
+	require.Equal(t, "mount", resp.Data["aia_source"])
+	require.Equal(t, []string{"http://crl.example.com/crl"}, resp.Data["crl_distribution_points"])
+	require.Equal(t, []string{"http://ocsp.example.com"}, resp.Data["ocsp_servers"])
+	require.Equal(t, []string{}, resp.Data["issuing_certificates"])
+	require.Equal(t, true, resp.Data["auto_rebuild"])
+	require.Equal(t, true, resp.Data["enable_delta"])
+	require.Equal(t, "15m", resp.Data["delta_rebuild_interval"])
+	require.Equal(t, true, resp.Data["crl_signing"])
+	require.Equal(t, true, resp.Data["ocsp_signing"])
+	require.Equal(t, issuerA, resp.Data["ocsp_signer_issuer_id"])
+	require.Equal(t, false, resp.Data["ocsp_signer_delegated"])
+	require.Empty(t, resp.Warnings)
+
+	// Per-issuer URLs take precedence over the mount's.
+	_, err = CBPatch(b, s, "issuer/root-a", map[string]interface{}{
+		"ocsp_servers": "http://ocsp-a.example.com",
+		"usage":        "read-only,issuing-certificates,crl-signing",
+	})
+	require.NoError(t, err)
+
+	resp, err = CBRead(b, s, "issuer/root-a/revocation-config")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root-a/revocation-config")
+	require.Equal(t, "issuer", resp.Data["aia_source"])
+	require.Equal(t, []string{"http://ocsp-a.example.com"}, resp.Data["ocsp_servers"])
+	require.Equal(t, false, resp.Data["ocsp_signing"])
+	require.Equal(t, "", resp.Data["ocsp_signer_issuer_id"])
+	require.NotEmpty(t, resp.Warnings)
+
+	// A reissued issuer sharing the subject and key signs OCSP responses in
+	// its stead.
+	resp, err = CBWrite(b, s, "issuers/generate/root/existing", map[string]interface{}{
+		"common_name": "root example.com",
+		"issuer_name": "root-b",
+		"key_ref":     "root-key",
+		"ttl":         "8h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuers/generate/root/existing")
+	issuerB := resp.Data["issuer_id"].(issuerID)
+
+	resp, err = CBRead(b, s, "issuer/root-a/revocation-config")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root-a/revocation-config")
+	require.Equal(t, issuerB, resp.Data["ocsp_signer_issuer_id"])
+	require.Equal(t, true, resp.Data["ocsp_signer_delegated"])
+	require.Empty(t, resp.Warnings)
+
+	_, err = CBRead(b, s, "issuer/missing/revocation-config")
+	require.Error(t, err)
+}
+
+func TestFetchIssuerInfo(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// Without any issuer, there is no default to resolve.
+	resp, err := CBRead(b, s, "issuer/default/info")
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "no default issuer is configured")
+
+	issuers := map[string]issuerID{}
+	for _, name := range []string{"root-a", "root-b"} {
+		resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"ttl":         "8h",
+			"key_type":    "ec",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		issuers[name] = resp.Data["issuer_id"].(issuerID)
+	}
+
+	// The first issuer generated became the default.
+	resp, err = CBRead(b, s, "issuer/default/info")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/default/info")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("issuer/default/info"), logical.ReadOperation), resp, true)
+	require.Equal(t, issuers["root-a"], resp.Data["issuer_id"])
+	require.Equal(t, "root-a", resp.Data["issuer_name"])
+	require.Equal(t, true, resp.Data["is_default"])
+	require.Equal(t, "ec", resp.Data["key_type"])
+	require.Equal(t, 256, resp.Data["key_bits"])
+
+	cert := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, "root-a example.com", cert.Subject.CommonName)
+	require.Equal(t, cert.NotBefore, resp.Data["not_before"])
+	require.Equal(t, cert.NotAfter, resp.Data["not_after"])
+
+	// Rotating the default changes what default resolves to.
+	_, err = CBWrite(b, s, "config/issuers", map[string]interface{}{
+		"default": "root-b",
+	})
+	require.NoError(t, err)
+	resp, err = CBRead(b, s, "issuer/default/info")
+	requireSuccessNonNilResponse(t, resp, err, "issuer/default/info")
+	require.Equal(t, issuers["root-b"], resp.Data["issuer_id"])
+
+	// Other issuers may be referenced by name or ID.
+	for _, ref := range []string{"root-a", string(issuers["root-a"])} {
+		resp, err = CBRead(b, s, "issuer/"+ref+"/info")
+		requireSuccessNonNilResponse(t, resp, err, "issuer/"+ref+"/info")
+		require.Equal(t, issuers["root-a"], resp.Data["issuer_id"])
+		require.Equal(t, false, resp.Data["is_default"])
+	}
+
+	_, err = CBRead(b, s, "issuer/missing/info")
+	require.Error(t, err)
+}
+
+func TestFetchIssuerRaw(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	read := func(t *testing.T, path string, headers map[string][]string) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.ReadOperation,
+			Path:       path,
+			Storage:    s,
+			MountPoint: "pki/",
+			Headers:    headers,
+		})
+		require.NoError(t, err, "reading %v", path)
+		require.NotNil(t, resp, "reading %v", path)
+		return resp
+	}
+
+	// Unresolvable references are user errors.
+	resp := read(t, "issuer/default/pem", nil)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "no default issuer is configured")
+
+	certs := map[string]*x509.Certificate{}
+	for _, name := range []string{"root-a", "root-b"} {
+		resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+			"common_name": name + " example.com",
+			"issuer_name": name,
+			"ttl":         "8h",
+			"key_type":    "ec",
+		})
+		requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+		certs[name] = parseCert(t, resp.Data["certificate"].(string))
+	}
+
+	resp = read(t, "issuer/missing/der", nil)
+	require.True(t, resp.IsError())
+
+	// Each encoding returns the named issuer, not the default.
+	resp = read(t, "issuer/root-b/der", nil)
+	require.Equal(t, "application/pkix-cert", resp.Data[logical.HTTPContentType])
+	require.Equal(t, certs["root-b"].Raw, resp.Data[logical.HTTPRawBody])
+	resp = read(t, "issuer/root-b/pem", nil)
+	require.Equal(t, "application/pem-certificate-chain", resp.Data[logical.HTTPContentType])
+	require.Equal(t, certs["root-b"].Raw, parseCert(t, string(resp.Data[logical.HTTPRawBody].([]byte))).Raw)
+
+	// If-Modified-Since follows the modification time of the named issuer.
+	time.Sleep(2 * time.Second)
+	since := map[string][]string{headerIfModifiedSince: {time.Now().Format(time.RFC1123)}}
+	for _, path := range []string{"issuer/root-b/der", "issuer/root-b/pem"} {
+		resp = read(t, path, since)
+		require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode], "reading %v", path)
+	}
+
+	time.Sleep(2 * time.Second)
+	resp, err := CBPatch(b, s, "issuer/root-a", map[string]interface{}{"issuer_name": "old-root-a"})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root-a")
+	resp = read(t, "issuer/root-b/der", since)
+	require.Equal(t, http.StatusNotModified, resp.Data[logical.HTTPStatusCode])
+	resp = read(t, "issuer/old-root-a/der", since)
+	require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+}
+
+func TestFetchCertFullChain(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	// Two roots, with an intermediate issued by the second, all on the
+	// same mount.
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root one example.com",
+		"issuer_name": "root-one",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root two example.com",
+		"issuer_name": "root-two",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootTwo := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "intermediate/generate/internal", map[string]interface{}{
+		"common_name": "intermediate example.com",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/generate/internal")
+
+	resp, err = CBWrite(b, s, "issuer/root-two/sign-intermediate", map[string]interface{}{
+		"csr":    resp.Data["csr"],
+		"format": "pem_bundle",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issuer/root-two/sign-intermediate")
+	intermediate := parseCert(t, resp.Data["certificate"].(string))
+
+	resp, err = CBWrite(b, s, "intermediate/set-signed", map[string]interface{}{
+		"certificate": resp.Data["certificate"],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "intermediate/set-signed")
+	intermediateID := resp.Data["imported_issuers"].([]string)[0]
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"issuer_ref":       intermediateID,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "leaf.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	serial := resp.Data["serial_number"].(string)
+	leaf := parseCert(t, resp.Data["certificate"].(string))
+
+	readChain := func(t *testing.T) []*x509.Certificate {
+		resp, err := CBRead(b, s, "cert/"+serial+"/fullchain/pem")
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial+"/fullchain/pem")
+		require.Equal(t, "application/pem-certificate-chain", resp.Data[logical.HTTPContentType])
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+
+		var chain []*x509.Certificate
+		rest := resp.Data[logical.HTTPRawBody].([]byte)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			chain = append(chain, cert)
+		}
+		return chain
+	}
+
+	// The leaf comes first, followed by the chain of the issuer which
+	// signed it rather than that of the default issuer.
+	chain := readChain(t)
+	require.Len(t, chain, 3)
+	require.Equal(t, leaf.Raw, chain[0].Raw)
+	require.Equal(t, intermediate.Raw, chain[1].Raw)
+	require.Equal(t, rootTwo.Raw, chain[2].Raw)
+
+	// Without an issuance record, the issuer is unknown rather than
+	// searched for.
+	resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+		"common_name": "legacy.example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/example")
+	legacy := resp.Data["serial_number"].(string)
+	require.NoError(t, s.Delete(context.Background(), certIssuancePrefix+normalizeSerial(legacy)))
+	_, err = CBRead(b, s, "cert/"+legacy+"/fullchain/pem")
+	require.ErrorContains(t, err, "is unknown or no longer exists")
+
+	// Revocation records the issuer, which is then used directly.
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serial,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	require.Equal(t, chain, readChain(t))
+
+	// Unknown serials are not found.
+	resp, err = CBRead(b, s, "cert/00:11:22/fullchain/pem")
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestFetchCertIssuerID(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root one example.com",
+		"issuer_name": "root-one",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root two example.com",
+		"issuer_name": "root-two",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootTwoID := string(resp.Data["issuer_id"].(issuerID))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"issuer_ref":       "root-two",
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for i := 0; i < 2; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+
+	// Valid certificates report the issuer which signed them, rather than
+	// the default issuer.
+	resp, err = CBRead(b, s, "cert/"+serials[0])
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serials[0])
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serials[0]), logical.ReadOperation), resp, true)
+	require.Equal(t, rootTwoID, resp.Data["issuer_id"])
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serials[1],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	resp, err = CBRead(b, s, "cert/"+serials[1])
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serials[1])
+	require.Equal(t, rootTwoID, resp.Data["issuer_id"])
+
+	// The recorded issuer is reported even once it is gone.
+	resp, err = CBDelete(b, s, "issuer/root-two")
+	require.NoError(t, err)
+	resp, err = CBRead(b, s, "cert/"+serials[0])
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serials[0])
+	require.Equal(t, rootTwoID, resp.Data["issuer_id"])
+
+	// Certificates issued before the issuer was recorded report none.
+	require.NoError(t, s.Delete(context.Background(), certIssuancePrefix+normalizeSerial(serials[0])))
+	resp, err = CBRead(b, s, "cert/"+serials[0])
+	requireSuccessNonNilResponse(t, resp, err, "cert/"+serials[0])
+	require.NotContains(t, resp.Data, "issuer_id")
+	require.NotContains(t, resp.Data, "issuer_present")
+}
+
+func TestFetchCertIssuerPresent(t *testing.T) {
+	t.Parallel()
+
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root one example.com",
+		"issuer_name": "root-one",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootOneSerial := resp.Data["serial_number"].(string)
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root two example.com",
+		"issuer_name": "root-two",
+		"key_type":    "ec",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "root/generate/internal")
+	rootTwoID := string(resp.Data["issuer_id"].(issuerID))
+
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"issuer_ref":       "root-two",
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "roles/example")
+
+	var serials []string
+	for i := 0; i < 2; i++ {
+		resp, err = CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": fmt.Sprintf("test%d.example.com", i),
+		})
+		requireSuccessNonNilResponse(t, resp, err, "issue/example")
+		serials = append(serials, resp.Data["serial_number"].(string))
+	}
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serials[1],
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	read := func(t *testing.T, serial string) *logical.Response {
+		t.Helper()
+		resp, err := CBRead(b, s, "cert/"+serial)
+		requireSuccessNonNilResponse(t, resp, err, "cert/"+serial)
+		schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("cert/"+serial), logical.ReadOperation), resp, true)
+		return resp
+	}
+
+	for _, serial := range append([]string{rootOneSerial}, serials...) {
+		require.Equal(t, true, read(t, serial).Data["issuer_present"], "serial %v", serial)
+	}
+
+	// Once the issuer is deleted, its certificates are flagged and still
+	// report its ID.
+	_, err = CBDelete(b, s, "issuer/root-two")
+	require.NoError(t, err)
+
+	resp = read(t, serials[0])
+	require.Equal(t, false, resp.Data["issuer_present"])
+	require.Equal(t, rootTwoID, resp.Data["issuer_id"])
+
+	resp = read(t, serials[1])
+	require.Equal(t, false, resp.Data["issuer_present"])
+	require.Equal(t, rootTwoID, resp.Data["issuer_id"])
+
+	require.Equal(t, true, read(t, rootOneSerial).Data["issuer_present"])
+
+	// Neither CA nor CRL fetches report it.
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err, "cert/ca")
+	require.NotContains(t, resp.Data, "issuer_present")
 }
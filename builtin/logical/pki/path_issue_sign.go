@@ -7,6 +7,10 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
@@ -73,11 +77,21 @@ func buildPathIssue(b *backend, pattern string, displayAttrs *framework.DisplayA
 								Description: `Certificate Chain`,
 								Required:    false,
 							},
+							"ca_chain_fingerprints": {
+								Type:        framework.TypeStringSlice,
+								Description: `SHA-256 fingerprints of the certificates in ca_chain, in order`,
+								Required:    false,
+							},
 							"serial_number": {
 								Type:        framework.TypeString,
 								Description: `Serial Number`,
 								Required:    true,
 							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Identifier of the issuer which signed the certificate`,
+								Required:    false,
+							},
 							"not_before": {
 								Type:        framework.TypeInt64,
 								Description: `Starting time of validity`,
@@ -98,6 +112,21 @@ func buildPathIssue(b *backend, pattern string, displayAttrs *framework.DisplayA
 								Description: `Private key type`,
 								Required:    false,
 							},
+							"metadata": {
+								Type:        framework.TypeMap,
+								Description: `Issuance metadata: serial number, expiration, issuing CA subject, issuance time and the resolved role and issuer`,
+								Required:    false,
+							},
+							structuredWarningsParam: {
+								Type:        framework.TypeSlice,
+								Description: `Warnings as objects with code, message and field keys, when requested`,
+								Required:    false,
+							},
+							policyDecisionsField: {
+								Type:        framework.TypeSlice,
+								Description: `The role policy checks performed on the request, when explain is set`,
+								Required:    false,
+							},
 						},
 					}},
 				},
@@ -109,6 +138,7 @@ func buildPathIssue(b *backend, pattern string, displayAttrs *framework.DisplayA
 	}
 
 	ret.Fields = addNonCACommonFields(map[string]*framework.FieldSchema{})
+	ret.Fields = addIssuerOverrideField(ret.Fields)
 
 	ret.Fields["key_bits"] = &framework.FieldSchema{
 		Type:    framework.TypeInt,
@@ -188,11 +218,21 @@ func buildPathSign(b *backend, pattern string, displayAttrs *framework.DisplayAt
 								Description: `Certificate Chain`,
 								Required:    false,
 							},
+							"ca_chain_fingerprints": {
+								Type:        framework.TypeStringSlice,
+								Description: `SHA-256 fingerprints of the certificates in ca_chain, in order`,
+								Required:    false,
+							},
 							"serial_number": {
 								Type:        framework.TypeString,
 								Description: `Serial Number`,
 								Required:    true,
 							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Identifier of the issuer which signed the certificate`,
+								Required:    false,
+							},
 							"not_before": {
 								Type:        framework.TypeInt64,
 								Description: `Starting time of validity`,
@@ -213,6 +253,21 @@ func buildPathSign(b *backend, pattern string, displayAttrs *framework.DisplayAt
 								Description: `Private key type`,
 								Required:    false,
 							},
+							"metadata": {
+								Type:        framework.TypeMap,
+								Description: `Issuance metadata: serial number, expiration, issuing CA subject, issuance time and the resolved role and issuer`,
+								Required:    false,
+							},
+							structuredWarningsParam: {
+								Type:        framework.TypeSlice,
+								Description: `Warnings as objects with code, message and field keys, when requested`,
+								Required:    false,
+							},
+							policyDecisionsField: {
+								Type:        framework.TypeSlice,
+								Description: `The role policy checks performed on the request, when explain is set`,
+								Required:    false,
+							},
 						},
 					}},
 				},
@@ -224,6 +279,7 @@ func buildPathSign(b *backend, pattern string, displayAttrs *framework.DisplayAt
 	}
 
 	ret.Fields = addNonCACommonFields(map[string]*framework.FieldSchema{})
+	ret.Fields = addIssuerOverrideField(ret.Fields)
 
 	ret.Fields["csr"] = &framework.FieldSchema{
 		Type:        framework.TypeString,
@@ -231,6 +287,41 @@ func buildPathSign(b *backend, pattern string, displayAttrs *framework.DisplayAt
 		Description: `PEM-format CSR to be signed.`,
 	}
 
+	ret.Fields["issuer_subject"] = &framework.FieldSchema{
+		Type: framework.TypeString,
+		Description: `Subject DN, in RFC 4514 string form, of the issuer
+to sign with. Only allowed on the sign/:role path, when the role's
+issuer_selection is "subject".`,
+	}
+
+	return ret
+}
+
+func pathSignPublicKey(b *backend) *framework.Path {
+	pattern := "sign-public-key/" + framework.GenericNameRegex("role")
+
+	displayAttrs := &framework.DisplayAttributes{
+		OperationPrefix: operationPrefixPKI,
+		OperationVerb:   "sign",
+		OperationSuffix: "public-key-with-role",
+	}
+
+	// Apart from taking a bare public key in place of a CSR, this behaves
+	// as the sign path does.
+	ret := buildPathSign(b, pattern, displayAttrs)
+	ret.Operations[logical.UpdateOperation].(*framework.PathOperation).Callback = b.metricsWrap("sign-public-key", roleRequired, b.pathSignPublicKey)
+	ret.HelpSynopsis = pathSignPublicKeyHelpSyn
+	ret.HelpDescription = pathSignPublicKeyHelpDesc
+
+	delete(ret.Fields, "csr")
+	delete(ret.Fields, "issuer_subject")
+	ret.Fields["public_key"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Default:     "",
+		Description: `PEM-format public key to be certified.`,
+		Required:    true,
+	}
+
 	return ret
 }
 
@@ -286,11 +377,21 @@ func buildPathIssuerSignVerbatim(b *backend, pattern string, displayAttrs *frame
 								Description: `Certificate Chain`,
 								Required:    false,
 							},
+							"ca_chain_fingerprints": {
+								Type:        framework.TypeStringSlice,
+								Description: `SHA-256 fingerprints of the certificates in ca_chain, in order`,
+								Required:    false,
+							},
 							"serial_number": {
 								Type:        framework.TypeString,
 								Description: `Serial Number`,
 								Required:    true,
 							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Identifier of the issuer which signed the certificate`,
+								Required:    false,
+							},
 							"not_before": {
 								Type:        framework.TypeInt64,
 								Description: `Starting time of validity`,
@@ -311,6 +412,21 @@ func buildPathIssuerSignVerbatim(b *backend, pattern string, displayAttrs *frame
 								Description: `Private key type`,
 								Required:    false,
 							},
+							"metadata": {
+								Type:        framework.TypeMap,
+								Description: `Issuance metadata: serial number, expiration, issuing CA subject, issuance time and the resolved role and issuer`,
+								Required:    false,
+							},
+							structuredWarningsParam: {
+								Type:        framework.TypeSlice,
+								Description: `Warnings as objects with code, message and field keys, when requested`,
+								Required:    false,
+							},
+							policyDecisionsField: {
+								Type:        framework.TypeSlice,
+								Description: `The role policy checks performed on the request, when explain is set`,
+								Required:    false,
+							},
 						},
 					}},
 				},
@@ -378,7 +494,8 @@ func (b *backend) pathIssue(ctx context.Context, req *logical.Request, data *fra
 
 	resp, err := b.pathIssueSignCert(ctx, req, data, role, false, false)
 	if addWarning && resp != nil {
-		resp.AddWarning("parameters key_type and key_bits ignored as role had specific values")
+		addCodedWarning(resp, newWarning(warningCodeKeyParamsIgnored, "key_type", "parameters key_type and key_bits ignored as role had specific values"))
+		resp = addStructuredWarnings(resp, data)
 	}
 	return resp, err
 }
@@ -389,6 +506,16 @@ func (b *backend) pathSign(ctx context.Context, req *logical.Request, data *fram
 	return b.pathIssueSignCert(ctx, req, data, role, true, false)
 }
 
+// pathSignPublicKey issues a certificate for a bare public key, subject to
+// role restrictions; the role must explicitly allow this.
+func (b *backend) pathSignPublicKey(ctx context.Context, req *logical.Request, data *framework.FieldData, role *roleEntry) (*logical.Response, error) {
+	if !role.AllowPublicKeySigning {
+		return logical.ErrorResponse(fmt.Sprintf("role %q does not allow signing public keys; set allow_public_key_signing to permit it", role.Name)), nil
+	}
+
+	return b.pathIssueSignCert(ctx, req, data, role, true, false)
+}
+
 // pathSignVerbatim issues a certificate from a submitted CSR, *not* subject to
 // role restrictions
 func (b *backend) pathSignVerbatim(ctx context.Context, req *logical.Request, data *framework.FieldData, role *roleEntry) (*logical.Response, error) {
@@ -415,7 +542,7 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 	//    allows users with access to those paths to manually choose their
 	//    issuer in desired scenarios).
 	var issuerName string
-	if strings.HasPrefix(req.Path, "sign-verbatim/") || strings.HasPrefix(req.Path, "sign/") || strings.HasPrefix(req.Path, "issue/") {
+	if strings.HasPrefix(req.Path, "sign-verbatim/") || strings.HasPrefix(req.Path, "sign/") || strings.HasPrefix(req.Path, "sign-public-key/") || strings.HasPrefix(req.Path, "issue/") {
 		issuerName = role.Issuer
 		if len(issuerName) == 0 {
 			issuerName = defaultRef
@@ -429,15 +556,62 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		}
 	}
 
+	sc := b.makeStorageContext(ctx, req.Storage)
+
+	// On the legacy sign/:role and issue/:role paths, callers may override
+	// the role's issuer with another one permitted by the role.
+	if strings.HasPrefix(req.Path, "sign/") || strings.HasPrefix(req.Path, "sign-public-key/") || strings.HasPrefix(req.Path, "issue/") {
+		if requestedRaw, ok := data.GetOk(issuerRefParam); ok {
+			if requested := strings.TrimSpace(requestedRaw.(string)); len(requested) > 0 {
+				if err := validateIssuerOverride(sc, role, issuerName, requested); err != nil {
+					return logical.ErrorResponse(err.Error()), nil
+				}
+				issuerName = requested
+			}
+		}
+	}
+
+	if strings.HasPrefix(req.Path, "sign/") && role.IssuerSelection == issuerSelectionSubject {
+		if _, overridden := data.GetOk(issuerRefParam); !overridden {
+			selected, err := selectIssuerBySubject(sc, role, issuerName, data)
+			if err != nil {
+				switch err.(type) {
+				case errutil.UserError:
+					return logical.ErrorResponse(err.Error()), nil
+				default:
+					return nil, fmt.Errorf("error selecting issuer by subject: %w", err)
+				}
+			}
+			issuerName = selected
+		}
+	} else if subject, ok := data.GetOk("issuer_subject"); ok && len(subject.(string)) > 0 {
+		return logical.ErrorResponse(`issuer_subject may only be used on the sign/:role path with a role whose issuer_selection is "subject"`), nil
+	}
+
 	format := getFormat(data)
 	if format == "" {
 		return logical.ErrorResponse(
 			`the "format" path parameter must be "pem", "der", or "pem_bundle"`), nil
 	}
 
+	// Requests scoped by a registration authority must present its
+	// credential; the issued names are checked against it below.
+	var raNamespaces []string
+	if role.RequireRACredential {
+		var err error
+		raNamespaces, err = fetchRANamespaces(sc, req, role)
+		if err != nil {
+			switch err.(type) {
+			case errutil.UserError:
+				return logical.ErrorResponse(err.Error()), nil
+			default:
+				return nil, fmt.Errorf("error validating RA credential: %w", err)
+			}
+		}
+	}
+
 	var caErr error
-	sc := b.makeStorageContext(ctx, req.Storage)
-	signingBundle, caErr := sc.fetchCAInfo(issuerName, IssuanceUsage)
+	signingBundle, issuerId, caErr := sc.fetchCAInfoWithIssuer(issuerName, IssuanceUsage)
 	if caErr != nil {
 		switch caErr.(type) {
 		case errutil.UserError:
@@ -454,17 +628,26 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		apiData: data,
 		role:    role,
 	}
+	if explain, ok := data.GetOk(explainParam); ok && explain.(bool) {
+		input.trace = &policyTrace{}
+	}
 	var parsedBundle *certutil.ParsedCertBundle
 	var err error
-	var warnings []string
-	if useCSR {
+	var warnings []structuredWarning
+	switch {
+	case strings.HasPrefix(req.Path, "sign-public-key/"):
+		parsedBundle, warnings, err = signPublicKey(b, input, signingBundle)
+	case useCSR:
 		parsedBundle, warnings, err = signCert(b, input, signingBundle, false, useCSRValues)
-	} else {
+	default:
 		parsedBundle, warnings, err = generateCert(sc, input, signingBundle, false, rand.Reader)
 	}
 	if err != nil {
 		switch err.(type) {
 		case errutil.UserError:
+			if input.trace != nil {
+				return policyRejectionResponse(err.Error(), input.trace)
+			}
 			return logical.ErrorResponse(err.Error()), nil
 		case errutil.InternalError:
 			return nil, err
@@ -473,6 +656,17 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		}
 	}
 
+	if role.RequireRACredential {
+		err := checkRANamespaces(parsedBundle.Certificate, raNamespaces)
+		input.trace.record("ra_namespace", "ra_namespaces", strings.Join(raNamespaces, ","), err == nil)
+		if err != nil {
+			if input.trace != nil {
+				return policyRejectionResponse(err.Error(), input.trace)
+			}
+			return logical.ErrorResponse(err.Error()), nil
+		}
+	}
+
 	signingCB, err := signingBundle.ToCertBundle()
 	if err != nil {
 		return nil, fmt.Errorf("error converting raw signing bundle to cert bundle: %w", err)
@@ -490,6 +684,28 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		"expiration":    int64(parsedBundle.Certificate.NotAfter.Unix()),
 		"serial_number": cb.SerialNumber,
 	}
+	if !b.useLegacyBundleCaStorage() {
+		respData["issuer_id"] = issuerId.String()
+	}
+
+	// Gather the issuance details in one place, so clients can log
+	// issuance events without picking apart the top-level fields.
+	metadata := map[string]interface{}{
+		"serial_number":         cb.SerialNumber,
+		"expiration":            int64(parsedBundle.Certificate.NotAfter.Unix()),
+		"issuing_ca_subject":    signingBundle.Certificate.Subject.String(),
+		"issuance_time_rfc3339": time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(role.Name) > 0 {
+		metadata["role_name"] = role.Name
+	}
+	if !b.useLegacyBundleCaStorage() {
+		metadata["issuer_id"] = issuerId.String()
+	}
+	respData["metadata"] = metadata
+	if input.trace != nil {
+		respData[policyDecisionsField] = input.trace.toResponseData()
+	}
 
 	switch format {
 	case "pem":
@@ -530,6 +746,10 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 
+	if caChainGen.containsChain() {
+		respData["ca_chain_fingerprints"] = caChainGen.fingerprints()
+	}
+
 	var resp *logical.Response
 	switch {
 	case role.GenerateLease == nil:
@@ -567,22 +787,235 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 			return nil, fmt.Errorf("unable to store certificate locally: %w", err)
 		}
 		b.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
+
+		if err := sc.writeCertIssuance(cb.SerialNumber, &certIssuanceEntry{
+			IssuerID:     issuerId,
+			Role:         role.Name,
+			RACredential: len(role.RANamespaces) > 0,
+		}); err != nil {
+			return nil, fmt.Errorf("unable to store issuance record: %w", err)
+		}
+
+		if role.OcspOnlyRevocation {
+			if err := sc.writeOcspOnlyRevocationMarker(cb.SerialNumber); err != nil {
+				return nil, fmt.Errorf("unable to store OCSP-only revocation marker: %w", err)
+			}
+		}
+	}
+
+	if role.EscrowPrivateKey && !role.NoStore && len(parsedBundle.PrivateKeyBytes) > 0 {
+		if err := sc.writeEscrowedKey(parsedBundle); err != nil {
+			return nil, fmt.Errorf("unable to escrow private key: %w", err)
+		}
+		resp.AddWarning("the private key of this certificate has been escrowed by the mount, per the role's escrow_private_key")
 	}
 
 	if useCSR {
 		if role.UseCSRCommonName && data.Get("common_name").(string) != "" {
-			resp.AddWarning("the common_name field was provided but the role is set with \"use_csr_common_name\" set to true")
+			addCodedWarning(resp, newWarning(warningCodeCSRCommonNameIgnored, "common_name", "the common_name field was provided but the role is set with \"use_csr_common_name\" set to true"))
 		}
 		if role.UseCSRSANs && data.Get("alt_names").(string) != "" {
-			resp.AddWarning("the alt_names field was provided but the role is set with \"use_csr_sans\" set to true")
+			addCodedWarning(resp, newWarning(warningCodeCSRSANsIgnored, "alt_names", "the alt_names field was provided but the role is set with \"use_csr_sans\" set to true"))
 		}
 	}
 
 	resp = addWarnings(resp, warnings)
+	resp = addStructuredWarnings(resp, data)
 
 	return resp, nil
 }
 
+// validateIssuerOverride checks whether the requested issuer may be used in
+// place of the role's issuer: either it is the role's issuer, or it is
+// permitted by the role's allowed_issuers.
+func validateIssuerOverride(sc *storageContext, role *roleEntry, roleIssuer string, requested string) error {
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return errors.New("cannot override the issuer until the PKI migration has completed")
+	}
+
+	requestedId, err := sc.resolveIssuerReference(requested)
+	if err != nil {
+		return fmt.Errorf("unable to resolve requested issuer %q: %w", requested, err)
+	}
+
+	if roleIssuerId, err := sc.resolveIssuerReference(roleIssuer); err == nil && roleIssuerId == requestedId {
+		return nil
+	}
+
+	for _, allowed := range role.AllowedIssuers {
+		if allowed == "*" {
+			return nil
+		}
+
+		// Entries which no longer resolve (e.g., deleted issuers) can't
+		// match anything.
+		allowedId, err := sc.resolveIssuerReference(allowed)
+		if err == nil && allowedId == requestedId {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("issuer %q is not allowed by this role", requested)
+}
+
+// selectIssuerBySubject resolves the issuer for a role using subject-based
+// issuer selection. The subject comes from the issuer_subject parameter or,
+// when absent, from the directoryName of the CSR's requested Authority Key
+// Identifier extension; with neither, the role's issuer is used.
+//
+// Only issuers permitted by the role (see validateIssuerOverride) and capable
+// of issuance are candidates. When several share the subject, as after a
+// rotation, unexpired issuers are preferred, then the mount's default issuer,
+// then the one expiring last.
+func selectIssuerBySubject(sc *storageContext, role *roleEntry, roleIssuer string, data *framework.FieldData) (string, error) {
+	subject := strings.TrimSpace(data.Get("issuer_subject").(string))
+	if len(subject) == 0 {
+		pemBlock, _ := pem.Decode([]byte(data.Get("csr").(string)))
+		if pemBlock == nil {
+			return "", errutil.UserError{Err: "csr contains no data"}
+		}
+		csr, err := x509.ParseCertificateRequest(pemBlock.Bytes)
+		if err != nil {
+			return "", errutil.UserError{Err: fmt.Sprintf("certificate request could not be parsed: %v", err)}
+		}
+
+		subject, err = intendedIssuerFromCSR(csr)
+		if err != nil {
+			return "", err
+		}
+		if len(subject) == 0 {
+			return roleIssuer, nil
+		}
+	}
+
+	if sc.Backend.useLegacyBundleCaStorage() {
+		return "", errutil.UserError{Err: "cannot select issuers by subject until the PKI migration has completed"}
+	}
+
+	issuers, err := sc.listIssuers()
+	if err != nil {
+		return "", err
+	}
+
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		id   issuerID
+		cert *x509.Certificate
+	}
+
+	now := time.Now()
+	var best *candidate
+	better := func(a, b *candidate) bool {
+		aExpired, bExpired := now.After(a.cert.NotAfter), now.After(b.cert.NotAfter)
+		if aExpired != bExpired {
+			return !aExpired
+		}
+		if aDefault, bDefault := a.id == config.DefaultIssuerId, b.id == config.DefaultIssuerId; aDefault != bDefault {
+			return aDefault
+		}
+		if !a.cert.NotAfter.Equal(b.cert.NotAfter) {
+			return a.cert.NotAfter.After(b.cert.NotAfter)
+		}
+		return a.id < b.id
+	}
+
+	for _, id := range issuers {
+		issuer, err := sc.fetchIssuerById(id)
+		if err != nil {
+			return "", err
+		}
+		if issuer.EnsureUsage(IssuanceUsage) != nil {
+			continue
+		}
+
+		cert, err := issuer.GetCertificate()
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(cert.Subject.String(), subject) {
+			continue
+		}
+		if validateIssuerOverride(sc, role, roleIssuer, string(id)) != nil {
+			continue
+		}
+
+		this := &candidate{id: id, cert: cert}
+		if best == nil || better(this, best) {
+			best = this
+		}
+	}
+
+	if best == nil {
+		return "", errutil.UserError{Err: fmt.Sprintf("no issuer permitted by this role has subject %q", subject)}
+	}
+
+	return string(best.id), nil
+}
+
+// intendedIssuerFromCSR returns the issuer DN a CSR asks to be signed by, as
+// carried in the authorityCertIssuer field of a requested Authority Key
+// Identifier extension. PKCS#10 has no dedicated field for this; an empty
+// string is returned when the CSR doesn't carry one.
+func intendedIssuerFromCSR(csr *x509.CertificateRequest) (string, error) {
+	for _, ext := range csr.Extensions {
+		if !ext.Id.Equal(akOid) {
+			continue
+		}
+
+		// AuthorityKeyIdentifier ::= SEQUENCE {
+		//     keyIdentifier             [0] KeyIdentifier OPTIONAL,
+		//     authorityCertIssuer       [1] GeneralNames OPTIONAL,
+		//     authorityCertSerialNumber [2] CertificateSerialNumber OPTIONAL }
+		var aki asn1.RawValue
+		if rest, err := asn1.Unmarshal(ext.Value, &aki); err != nil || len(rest) > 0 {
+			return "", errutil.UserError{Err: "unable to parse the CSR's requested authority key identifier extension"}
+		}
+
+		var certIssuer []byte
+		for fields := aki.Bytes; len(fields) > 0; {
+			var field asn1.RawValue
+			var err error
+			fields, err = asn1.Unmarshal(fields, &field)
+			if err != nil {
+				return "", errutil.UserError{Err: "unable to parse the CSR's requested authority key identifier extension"}
+			}
+			if field.Class == asn1.ClassContextSpecific && field.Tag == 1 {
+				certIssuer = field.Bytes
+			}
+		}
+
+		rest := certIssuer
+		for len(rest) > 0 {
+			var generalName asn1.RawValue
+			var err error
+			rest, err = asn1.Unmarshal(rest, &generalName)
+			if err != nil {
+				return "", errutil.UserError{Err: "unable to parse the CSR's requested authority certificate issuer"}
+			}
+
+			// directoryName [4] Name
+			if generalName.Class != asn1.ClassContextSpecific || generalName.Tag != 4 {
+				continue
+			}
+
+			var rdns pkix.RDNSequence
+			if rest, err := asn1.Unmarshal(generalName.Bytes, &rdns); err != nil || len(rest) > 0 {
+				return "", errutil.UserError{Err: "unable to parse the CSR's requested authority certificate issuer"}
+			}
+
+			var name pkix.Name
+			name.FillFromRDNSequence(&rdns)
+			return name.String(), nil
+		}
+	}
+
+	return "", nil
+}
+
 type caChainOutput struct {
 	chain []*certutil.CertBlock
 }
@@ -627,6 +1060,17 @@ func (cac *caChainOutput) derEncodedChain() []string {
 	return derCaChain
 }
 
+// fingerprints returns the colon-separated SHA-256 fingerprint of each
+// certificate in the chain, in the same order as the encoded chain.
+func (cac *caChainOutput) fingerprints() []string {
+	var fingerprints []string
+	for _, caCert := range cac.chain {
+		sum := sha256.Sum256(caCert.Bytes)
+		fingerprints = append(fingerprints, certutil.GetHexFormatted(sum[:], ":"))
+	}
+	return fingerprints
+}
+
 const pathIssueHelpSyn = `
 Request a certificate using a certain role with the provided details.
 `
@@ -641,6 +1085,20 @@ that does not expose a private key, generate a CSR locally and use the
 sign path instead.
 `
 
+const pathSignPublicKeyHelpSyn = `
+Request a certificate for a public key using a certain role, without a CSR.
+`
+
+const pathSignPublicKeyHelpDesc = `
+This path allows requesting a certificate for a PEM-encoded public key when
+the client can't produce a CSR. The subject and SANs come from the request
+parameters and are validated against the role as on the sign path.
+
+As no CSR is signed with the corresponding private key, OpenBao can't verify
+that the requester holds it; the role must set allow_public_key_signing for
+this path to be used.
+`
+
 const pathSignHelpSyn = `
 Request certificates using a certain role with the provided details.
 `
@@ -646,6 +646,11 @@ func pathRevokeIssuer(b *backend) *framework.Path {
 								Description: `RFC formatted time of revocation`,
 								Required:    false,
 							},
+							"expiry_warning_threshold": {
+								Type:        framework.TypeInt64,
+								Description: `Seconds before expiry from which fetches of this issuer as the CA warn about it`,
+								Required:    false,
+							},
 						},
 					}},
 				},
@@ -0,0 +1,218 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathMigrateLegacy(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "legacy/migrate",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "migrate",
+			OperationSuffix: "legacy-bundle",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"dry_run": {
+				Type: framework.TypeBool,
+				Description: `When true, only report whether the legacy CA
+bundle is represented in the issuers store, without migrating it.`,
+				Default: false,
+			},
+			"set_default": {
+				Type: framework.TypeBool,
+				Description: `When true, make the issuer holding the legacy
+CA certificate the mount's default issuer, so that the ca and ca_chain fetch
+paths serve it as they did before migration.`,
+				Default: false,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathMigrateLegacyWrite,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"legacy_bundle_found": {
+								Type:        framework.TypeBool,
+								Description: `Whether a legacy CA bundle exists in storage`,
+								Required:    true,
+							},
+							"migrated": {
+								Type:        framework.TypeBool,
+								Description: `Whether this request created an issuer or key from the legacy CA bundle`,
+								Required:    true,
+							},
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Issuer holding the legacy CA certificate, if any`,
+								Required:    true,
+							},
+							"key_id": {
+								Type:        framework.TypeString,
+								Description: `Key holding the legacy CA private key, if any`,
+								Required:    true,
+							},
+							"default_issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Default issuer of the mount after this request`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+				// Migrating writes issuers and keys to storage; read more
+				// about why these flags are set in backend.go.
+				ForwardPerformanceStandby:   true,
+				ForwardPerformanceSecondary: true,
+			},
+		},
+
+		HelpSynopsis:    pathMigrateLegacyHelpSyn,
+		HelpDescription: pathMigrateLegacyHelpDesc,
+	}
+}
+
+func (b *backend) pathMigrateLegacyWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	dryRun := data.Get("dry_run").(bool)
+	setDefault := data.Get("set_default").(bool)
+
+	// Migrating flips the storage version used by every other request, so
+	// hold the lock for a consistent view.
+	b.issuersLock.Lock()
+	defer b.issuersLock.Unlock()
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	info, err := getMigrationInfo(ctx, req.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read legacy migration state: %w", err)
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"legacy_bundle_found": info.legacyBundle != nil,
+			"migrated":            false,
+			"issuer_id":           "",
+			"key_id":              "",
+		},
+	}
+
+	if info.legacyBundle == nil {
+		if !dryRun && info.isRequired {
+			// Nothing to convert, but the migration log is still written so
+			// the mount leaves legacy mode.
+			if err := migrateStorage(ctx, b, req.Storage); err != nil {
+				return nil, err
+			}
+			b.updatePkiStorageVersion(ctx, false)
+		}
+		resp.AddWarning("No legacy CA bundle exists in storage; nothing to migrate.")
+		return b.finishMigrateLegacyResponse(sc, resp)
+	}
+
+	issuerId, keyId, err := sc.findLegacyBundleEntries(info.legacyBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	represented := (len(info.legacyBundle.Certificate) == 0 || len(issuerId) > 0) &&
+		(len(info.legacyBundle.PrivateKey) == 0 || len(keyId) > 0)
+	if !represented && dryRun {
+		resp.AddWarning("The legacy CA bundle is not fully represented in the issuers store; it would be migrated.")
+	}
+
+	if !represented && !dryRun {
+		migrationName := fmt.Sprintf("legacy-migrated-%d", time.Now().Unix())
+		anIssuer, aKey, err := sc.writeCaBundle(info.legacyBundle, migrationName, migrationName)
+		if err != nil {
+			return nil, err
+		}
+		issuerId, keyId = anIssuer.ID, aKey.ID
+		resp.Data["migrated"] = true
+
+		b.Logger().Info("migrated legacy CA bundle to the issuers store", "issuer id", issuerId, "key id", keyId)
+
+		err = setLegacyBundleMigrationLog(ctx, req.Storage, &legacyBundleMigrationLog{
+			Hash:             info.legacyBundleHash,
+			Created:          time.Now(),
+			CreatedIssuer:    issuerId,
+			CreatedKey:       keyId,
+			MigrationVersion: latestMigrationVersion,
+		})
+		if err != nil {
+			return nil, err
+		}
+		b.updatePkiStorageVersion(ctx, false)
+	}
+
+	if !dryRun && setDefault && len(issuerId) > 0 {
+		if err := sc.updateDefaultIssuerId(issuerId); err != nil {
+			return nil, err
+		}
+	}
+
+	resp.Data["issuer_id"] = issuerId.String()
+	resp.Data["key_id"] = keyId.String()
+
+	if resp.Data["migrated"].(bool) {
+		// Revocations of certificates issued by the legacy CA are kept in
+		// storage; rebuilding associates them with the migrated issuer so
+		// its CRLs list them. The legacy CRL itself is left untouched.
+		warnings, err := b.crlBuilder.rebuild(sc, true)
+		if err != nil {
+			return nil, fmt.Errorf("migrated the legacy CA bundle, but failed to rebuild CRLs: %w", err)
+		}
+		for index, warning := range warnings {
+			resp.AddWarning(fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning))
+		}
+	}
+
+	return b.finishMigrateLegacyResponse(sc, resp)
+}
+
+func (b *backend) finishMigrateLegacyResponse(sc *storageContext, resp *logical.Response) (*logical.Response, error) {
+	resp.Data["default_issuer_id"] = ""
+	if b.useLegacyBundleCaStorage() {
+		return resp, nil
+	}
+
+	config, err := sc.getIssuersConfig()
+	if err != nil {
+		return nil, err
+	}
+	resp.Data["default_issuer_id"] = config.DefaultIssuerId.String()
+	return resp, nil
+}
+
+const pathMigrateLegacyHelpSyn = `
+Migrate the legacy single-issuer CA bundle to the issuers store.
+`
+
+const pathMigrateLegacyHelpDesc = `
+Mounts created before multiple issuers were supported stored their CA
+certificate, chain and key as a single bundle. This bundle is migrated to an
+issuer and key when the mount is initialized; this endpoint re-runs that
+migration on demand, for instance when the migrated issuer has since been
+deleted while the legacy bundle remains.
+
+The request is idempotent: when an issuer and key matching the legacy bundle
+already exist, nothing is written. With dry_run set, only the report is
+returned. With set_default set, the issuer holding the legacy certificate
+becomes the default issuer, served by the ca and ca_chain fetch paths.
+
+The legacy bundle and its CRL are left in storage. After migrating, the CRLs
+are rebuilt so the migrated issuer's CRL lists existing revocations.
+`
@@ -29,13 +29,17 @@ import (
 const (
 	ocspReqParam            = "req"
 	ocspResponseContentType = "application/ocsp-response"
-	maximumRequestSize      = 2048 // A normal simple request is 87 bytes, so give us some buffer
+
+	// A normal simple request is 87 bytes, so this leaves plenty of room for
+	// nonces, multiple requests, and signed requests.
+	defaultOcspMaxRequestBytes = 64 * 1024
 )
 
 type ocspRespInfo struct {
 	serialNumber      *big.Int
 	ocspStatus        int
 	revocationTimeUTC *time.Time
+	revocationReason  int
 	issuerID          issuerID
 }
 
@@ -135,7 +139,7 @@ func (b *backend) ocspHandler(ctx context.Context, request *logical.Request, dat
 		return OcspUnauthorizedResponse, nil
 	}
 
-	derReq, err := fetchDerEncodedRequest(request, data)
+	derReq, err := fetchDerEncodedRequest(request, data, cfg.OcspMaxRequestBytes)
 	if err != nil {
 		return OcspMalformedResponse, nil
 	}
@@ -145,7 +149,7 @@ func (b *backend) ocspHandler(ctx context.Context, request *logical.Request, dat
 		return OcspMalformedResponse, nil
 	}
 
-	ocspStatus, err := getOcspStatus(sc, ocspReq)
+	ocspStatus, err := getOcspStatus(sc, cfg, ocspReq)
 	if err != nil {
 		return logAndReturnInternalError(b, err), nil
 	}
@@ -230,7 +234,7 @@ func generateUnknownResponse(cfg *crlConfig, sc *storageContext, ocspReq *ocsp.R
 	}
 }
 
-func fetchDerEncodedRequest(request *logical.Request, data *framework.FieldData) ([]byte, error) {
+func fetchDerEncodedRequest(request *logical.Request, data *framework.FieldData, maxRequestBytes int) ([]byte, error) {
 	switch request.Operation {
 	case logical.ReadOperation:
 		// The param within the GET request should have a base64 encoded version of a DER request.
@@ -239,11 +243,20 @@ func fetchDerEncodedRequest(request *logical.Request, data *framework.FieldData)
 			return nil, errors.New("no base64 encoded ocsp request was found")
 		}
 
-		if len(base64Req) >= maximumRequestSize {
+		// Avoid decoding anything which could not possibly fit the limit.
+		if len(base64Req) > base64.StdEncoding.EncodedLen(maxRequestBytes) {
 			return nil, errors.New("request is too large")
 		}
 
-		return base64.StdEncoding.DecodeString(base64Req)
+		requestBytes, err := base64.StdEncoding.DecodeString(base64Req)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(requestBytes) > maxRequestBytes {
+			return nil, errors.New("request is too large")
+		}
+		return requestBytes, nil
 	case logical.UpdateOperation:
 		// POST bodies should contain the binary form of the DER request.
 		// NOTE: Writing an empty update request to Vault causes a nil request.HTTPRequest, and that object
@@ -257,12 +270,13 @@ func fetchDerEncodedRequest(request *logical.Request, data *framework.FieldData)
 		}
 		defer rawBody.Close()
 
-		requestBytes, err := io.ReadAll(io.LimitReader(rawBody, maximumRequestSize))
+		// Read one byte past the limit so oversized bodies can be detected.
+		requestBytes, err := io.ReadAll(io.LimitReader(rawBody, int64(maxRequestBytes)+1))
 		if err != nil {
 			return nil, err
 		}
 
-		if len(requestBytes) >= maximumRequestSize {
+		if len(requestBytes) > maxRequestBytes {
 			return nil, errors.New("request is too large")
 		}
 		return requestBytes, nil
@@ -280,7 +294,7 @@ func logAndReturnInternalError(b *backend, err error) *logical.Response {
 	return OcspInternalErrorResponse
 }
 
-func getOcspStatus(sc *storageContext, ocspReq *ocsp.Request) (*ocspRespInfo, error) {
+func getOcspStatus(sc *storageContext, cfg *crlConfig, ocspReq *ocsp.Request) (*ocspRespInfo, error) {
 	revEntryRaw, err := fetchCertBySerialBigInt(sc, revokedPath, ocspReq.SerialNumber)
 	if err != nil {
 		return nil, err
@@ -299,12 +313,58 @@ func getOcspStatus(sc *storageContext, ocspReq *ocsp.Request) (*ocspRespInfo, er
 
 		info.ocspStatus = ocsp.Revoked
 		info.revocationTimeUTC = &revEntry.RevocationTimeUTC
+		if revEntry.RevocationTimeUTC.IsZero() {
+			// Entries written by older versions only carry the revocation
+			// time in seconds; fall back to it as the CRL builder does.
+			revocationTime := time.Unix(revEntry.RevocationTime, 0).UTC()
+			info.revocationTimeUTC = &revocationTime
+		}
+		info.revocationReason = revEntry.RevocationReason
+		if revEntry.OnHold {
+			info.revocationReason = ocsp.CertificateHold
+		}
 		info.issuerID = revEntry.CertificateIssuer // This might be empty if the CRL hasn't been rebuilt
+	} else if cfg.OcspExpiredBehavior != ocspExpiredBehaviorGood {
+		if err := applyOcspExpiredBehavior(sc, cfg, &info); err != nil {
+			return nil, err
+		}
 	}
 
 	return &info, nil
 }
 
+// applyOcspExpiredBehavior updates the status of a certificate which was
+// never revoked when it has expired. Certificates which weren't stored by
+// the mount can't be known to have expired, and keep their good status.
+func applyOcspExpiredBehavior(sc *storageContext, cfg *crlConfig, info *ocspRespInfo) error {
+	certEntry, err := fetchCertBySerialBigInt(sc, "certs/", info.serialNumber)
+	if err != nil {
+		return err
+	}
+	if certEntry == nil {
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(certEntry.Value)
+	if err != nil {
+		return fmt.Errorf("unable to parse stored certificate: %w", err)
+	}
+	if !time.Now().After(cert.NotAfter) {
+		return nil
+	}
+
+	switch cfg.OcspExpiredBehavior {
+	case ocspExpiredBehaviorUnknown:
+		info.ocspStatus = ocsp.Unknown
+	case ocspExpiredBehaviorRevoked:
+		revocationTime := cert.NotAfter.UTC()
+		info.ocspStatus = ocsp.Revoked
+		info.revocationTimeUTC = &revocationTime
+		info.revocationReason = ocsp.Unspecified
+	}
+	return nil
+}
+
 func lookupOcspIssuer(sc *storageContext, req *ocsp.Request, optRevokedIssuer issuerID) (*certutil.ParsedCertBundle, *issuerEntry, error) {
 	reqHash := req.HashAlgorithm
 	if !reqHash.Available() {
@@ -467,7 +527,7 @@ func genResponse(cfg *crlConfig, caBundle *certutil.ParsedCertBundle, info *ocsp
 
 	if info.ocspStatus == ocsp.Revoked {
 		template.RevokedAt = *info.revocationTimeUTC
-		template.RevocationReason = ocsp.Unspecified
+		template.RevocationReason = info.revocationReason
 	}
 
 	return ocsp.CreateResponse(caBundle.Certificate, caBundle.Certificate, template, caBundle.PrivateKey)
@@ -7,8 +7,10 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -143,6 +145,60 @@ func TestOcsp_MalformedRequests(t *testing.T) {
 	}
 }
 
+// Verify that requests larger than the configured ocsp_max_request_bytes are
+// rejected as malformed, for both GET and POST requests.
+func TestOcsp_MaxRequestBytes(t *testing.T) {
+	t.Parallel()
+	for _, reqType := range []string{"get", "post"} {
+		reqType := reqType
+		t.Run(reqType, func(t *testing.T) {
+			t.Parallel()
+			b, s, testEnv := setupOcspEnv(t, "ec")
+
+			resp, err := CBRead(b, s, "config/crl")
+			requireSuccessNonNilResponse(t, resp, err)
+			require.Equal(t, defaultOcspMaxRequestBytes, resp.Data["ocsp_max_request_bytes"])
+
+			_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+				"ocsp_max_request_bytes": 0,
+			})
+			require.Error(t, err)
+
+			ocspReq := generateRequest(t, crypto.SHA256, testEnv.leafCertIssuer1, testEnv.issuer1)
+			send := func() *logical.Response {
+				var resp *logical.Response
+				var err error
+				switch reqType {
+				case "get":
+					resp, err = sendOcspGetRequest(b, s, ocspReq)
+				case "post":
+					resp, err = sendOcspPostRequest(b, s, ocspReq)
+				}
+				require.NoError(t, err)
+				requireFieldsSetInResp(t, resp, "http_content_type", "http_status_code", "http_raw_body")
+				return resp
+			}
+
+			// One byte too small.
+			resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+				"ocsp_max_request_bytes": len(ocspReq) - 1,
+			})
+			requireSuccessNonNilResponse(t, resp, err)
+			resp = send()
+			require.Equal(t, 400, resp.Data["http_status_code"])
+			require.Equal(t, ocsp.MalformedRequestErrorResponse, resp.Data["http_raw_body"].([]byte))
+
+			// Exactly at the limit.
+			resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+				"ocsp_max_request_bytes": len(ocspReq),
+			})
+			requireSuccessNonNilResponse(t, resp, err)
+			resp = send()
+			require.Equal(t, 200, resp.Data["http_status_code"])
+		})
+	}
+}
+
 // Validate that we properly handle a revocation entry that contains an issuer ID that no longer exists,
 // the best we can do in this use case is to respond back with the default issuer that we don't know
 // the issuer that they are requesting (we can't guarantee that the client is actually requesting a serial
@@ -468,6 +524,115 @@ func TestOcsp_HigherLevel(t *testing.T) {
 	require.Equal(t, certToRevoke.SerialNumber, ocspResp.SerialNumber)
 }
 
+// Verify the revocation reason and time stored with a revocation are
+// surfaced in OCSP responses.
+func TestOcsp_RevocationReason(t *testing.T) {
+	t.Parallel()
+	b, s, testEnv := setupOcspEnv(t, "ec")
+
+	requireRevokedInfo := func(leaf, issuer *x509.Certificate, reason int) *ocsp.Response {
+		t.Helper()
+
+		resp, err := SendOcspRequest(t, b, s, "post", leaf, issuer, crypto.SHA256)
+		requireSuccessNonNilResponse(t, resp, err, "ocsp post request")
+		require.Equal(t, 200, resp.Data["http_status_code"])
+
+		ocspResp, err := ocsp.ParseResponse(resp.Data["http_raw_body"].([]byte), issuer)
+		require.NoError(t, err, "parsing ocsp response")
+		require.Equal(t, ocsp.Revoked, ocspResp.Status)
+		require.Equal(t, reason, ocspResp.RevocationReason)
+		require.False(t, ocspResp.RevokedAt.IsZero())
+		return ocspResp
+	}
+
+	keyHash := sha256.Sum256(testEnv.leafCertIssuer1.RawSubjectPublicKeyInfo)
+	resp, err := CBWrite(b, s, "revoke/by-public-key", map[string]interface{}{
+		"public_key_sha256": hex.EncodeToString(keyHash[:]),
+		"reason":            "key_compromise",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke/by-public-key")
+	require.Contains(t, resp.Data["revoked"], serialFromCert(testEnv.leafCertIssuer1))
+
+	ocspResp := requireRevokedInfo(testEnv.leafCertIssuer1, testEnv.issuer1, ocsp.KeyCompromise)
+	require.WithinDuration(t, time.Now(), ocspResp.RevokedAt, time.Minute)
+
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serialFromCert(testEnv.leafCertIssuer2),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+	requireRevokedInfo(testEnv.leafCertIssuer2, testEnv.issuer2, ocsp.Unspecified)
+
+	// Entries from older versions lack the precise revocation time.
+	ctx := context.Background()
+	entryKey := revokedPath + normalizeSerial(serialFromCert(testEnv.leafCertIssuer2))
+	entry, err := s.Get(ctx, entryKey)
+	require.NoError(t, err)
+	var revInfo revocationInfo
+	require.NoError(t, entry.DecodeJSON(&revInfo))
+	revInfo.RevocationTimeUTC = time.Time{}
+	entry, err = logical.StorageEntryJSON(entryKey, revInfo)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, entry))
+
+	ocspResp = requireRevokedInfo(testEnv.leafCertIssuer2, testEnv.issuer2, ocsp.Unspecified)
+	require.Equal(t, revInfo.RevocationTime, ocspResp.RevokedAt.Unix())
+}
+
+func TestOcsp_ExpiredBehavior(t *testing.T) {
+	t.Parallel()
+	b, s, testEnv := setupOcspEnv(t, "ec")
+
+	resp, err := CBWrite(b, s, "issue/test0", map[string]interface{}{
+		"common_name": "expired.foobar.com",
+		"ttl":         "2s",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "issue/test0")
+	expiredCert := parseCert(t, resp.Data["certificate"].(string))
+	time.Sleep(time.Until(expiredCert.NotAfter) + time.Second)
+
+	requireStatus := func(cert *x509.Certificate, expected int) *ocsp.Response {
+		t.Helper()
+
+		resp, err := SendOcspRequest(t, b, s, "post", cert, testEnv.issuer1, crypto.SHA256)
+		requireSuccessNonNilResponse(t, resp, err, "ocsp post request")
+		require.Equal(t, 200, resp.Data["http_status_code"])
+
+		ocspResp, err := ocsp.ParseResponse(resp.Data["http_raw_body"].([]byte), testEnv.issuer1)
+		require.NoError(t, err, "parsing ocsp response")
+		require.Equal(t, expected, ocspResp.Status)
+		return ocspResp
+	}
+
+	// By default, expired certificates are reported as good.
+	resp, err = CBRead(b, s, "config/crl")
+	requireSuccessNonNilResponse(t, resp, err, "config/crl")
+	require.Equal(t, "good", resp.Data["ocsp_expired_behavior"])
+	requireStatus(expiredCert, ocsp.Good)
+
+	resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"ocsp_expired_behavior": "unknown",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "config/crl")
+	require.Equal(t, "unknown", resp.Data["ocsp_expired_behavior"])
+	requireStatus(expiredCert, ocsp.Unknown)
+	requireStatus(testEnv.leafCertIssuer1, ocsp.Good)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"ocsp_expired_behavior": "revoked-expired",
+	})
+	require.NoError(t, err)
+	ocspResp := requireStatus(expiredCert, ocsp.Revoked)
+	require.Equal(t, ocsp.Unspecified, ocspResp.RevocationReason)
+	require.True(t, expiredCert.NotAfter.Equal(ocspResp.RevokedAt), "expected revocation at %v, got %v", expiredCert.NotAfter, ocspResp.RevokedAt)
+	requireStatus(testEnv.leafCertIssuer1, ocsp.Good)
+
+	resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"ocsp_expired_behavior": "expired",
+	})
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+}
+
 // TestOcsp_NextUpdate make sure that we are setting the appropriate values
 // for the NextUpdate field within our responses.
 func TestOcsp_NextUpdate(t *testing.T) {
@@ -635,6 +800,61 @@ type ocspTestEnv struct {
 	keyId2 keyID
 }
 
+func TestOcsp_VerifyResponse(t *testing.T) {
+	t.Parallel()
+	b, s, testEnv := setupOcspEnv(t, "ec")
+
+	resp, err := SendOcspRequest(t, b, s, "post", testEnv.leafCertIssuer1, testEnv.issuer1, crypto.SHA1)
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, 200, resp.Data["http_status_code"])
+	goodResp := base64.StdEncoding.EncodeToString(resp.Data["http_raw_body"].([]byte))
+
+	resp, err = CBWrite(b, s, "ocsp/verify", map[string]interface{}{
+		"ocsp_response": goodResp,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "ocsp/verify")
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("ocsp/verify"), logical.UpdateOperation), resp, true)
+	require.Equal(t, serialFromCert(testEnv.leafCertIssuer1), resp.Data["serial_number"])
+	require.Equal(t, "good", resp.Data["status"])
+	require.Equal(t, "good", resp.Data["expected_status"])
+	require.Equal(t, true, resp.Data["status_matches"])
+	require.Equal(t, true, resp.Data["signature_valid"])
+	require.Equal(t, testEnv.issuerId1.String(), resp.Data["issuer_id"])
+	require.Equal(t, false, resp.Data["expired"])
+
+	// Once revoked, the previously good response no longer matches.
+	resp, err = CBWrite(b, s, "revoke", map[string]interface{}{
+		"serial_number": serialFromCert(testEnv.leafCertIssuer1),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "revoke")
+
+	resp, err = CBWrite(b, s, "ocsp/verify", map[string]interface{}{
+		"ocsp_response": goodResp,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "ocsp/verify")
+	require.Equal(t, "good", resp.Data["status"])
+	require.Equal(t, "revoked", resp.Data["expected_status"])
+	require.Equal(t, false, resp.Data["status_matches"])
+	require.Equal(t, true, resp.Data["signature_valid"])
+
+	// A response with a corrupted signature does not verify.
+	rawResp, err := base64.StdEncoding.DecodeString(goodResp)
+	require.NoError(t, err)
+	rawResp[len(rawResp)-1] ^= 0xFF
+	resp, err = CBWrite(b, s, "ocsp/verify", map[string]interface{}{
+		"ocsp_response": base64.StdEncoding.EncodeToString(rawResp),
+	})
+	requireSuccessNonNilResponse(t, resp, err, "ocsp/verify")
+	require.Equal(t, false, resp.Data["signature_valid"])
+	require.NotContains(t, resp.Data, "issuer_id")
+
+	// Garbage is rejected.
+	_, err = CBWrite(b, s, "ocsp/verify", map[string]interface{}{
+		"ocsp_response": "bm90IGFuIG9jc3AgcmVzcG9uc2U=",
+	})
+	require.Error(t, err)
+}
+
 func setupOcspEnv(t *testing.T, keyType string) (*backend, logical.Storage, *ocspTestEnv) {
 	return setupOcspEnvWithCaKeyConfig(t, keyType, 0, 0, 12*time.Hour)
 }
@@ -0,0 +1,155 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathReindex(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "reindex",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "reindex",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathReindexWrite,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"issuers": {
+								Type:        framework.TypeInt,
+								Description: `Number of issuers indexed`,
+								Required:    true,
+							},
+							"keys": {
+								Type:        framework.TypeInt,
+								Description: `Number of keys indexed`,
+								Required:    true,
+							},
+							"certs": {
+								Type:        framework.TypeInt,
+								Description: `Number of stored certificates indexed`,
+								Required:    true,
+							},
+							"revoked_certs": {
+								Type:        framework.TypeInt,
+								Description: `Number of revoked certificates indexed`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+				// Rebuilding the issuers' chains writes to storage; read more
+				// about why these flags are set in backend.go.
+				ForwardPerformanceStandby:   true,
+				ForwardPerformanceSecondary: true,
+			},
+		},
+
+		HelpSynopsis:    pathReindexHelpSyn,
+		HelpDescription: pathReindexHelpDesc,
+	}
+}
+
+func (b *backend) pathReindexWrite(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("cannot reindex until migration has completed"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+
+	// Drop any cached configuration so it is re-read from storage on next
+	// use.
+	b.crlBuilder.markConfigDirty()
+	if err := b.crlBuilder.reloadConfigIfRequired(sc); err != nil {
+		return nil, fmt.Errorf("failed to reload CRL configuration: %w", err)
+	}
+	b.acmeState.markConfigDirty()
+
+	issuers, keys, err := b.reindexIssuers(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Certificates are counted without holding the issuers lock, as there
+	// may be many of them.
+	certs, err := countStorageEntries(ctx, req.Storage, "certs/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+
+	revokedCerts, err := countStorageEntries(ctx, req.Storage, revokedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing revoked certs: %w", err)
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"issuers":       issuers,
+			"keys":          keys,
+			"certs":         certs,
+			"revoked_certs": revokedCerts,
+		},
+	}
+
+	// Finally, recompute the stored certificate counts used for metrics.
+	if err := b.initializeStoredCertificateCounts(ctx); err != nil {
+		b.Logger().Error("Could not reinitialize stored certificate counts", "error", err)
+		b.certCountError = err.Error()
+		resp.AddWarning(fmt.Sprintf("unable to recount stored certificates: %v", err))
+	}
+
+	// Updated chains affect what is served on the CRL and fetch paths.
+	b.crlBuilder.invalidateCRLBuildTime()
+
+	return resp, nil
+}
+
+// reindexIssuers rebuilds the issuers' chains under the issuers lock,
+// returning the number of issuers and keys in storage.
+func (b *backend) reindexIssuers(sc *storageContext) (int, int, error) {
+	b.issuersLock.Lock()
+	defer b.issuersLock.Unlock()
+
+	issuers, err := sc.listIssuers()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list issuers: %w", err)
+	}
+
+	keys, err := sc.listKeys()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	// Issuers' chains are derived from the other issuers in storage, so they
+	// may be stale if issuers were modified out-of-band.
+	if err := sc.rebuildIssuersChains(nil); err != nil {
+		return 0, 0, fmt.Errorf("failed to rebuild issuers' chains: %w", err)
+	}
+
+	return len(issuers), len(keys), nil
+}
+
+const pathReindexHelpSyn = `
+Rebuild the in-memory PKI state from storage.
+`
+
+const pathReindexHelpDesc = `
+This endpoint reloads the cached CRL and ACME configuration, rebuilds the
+chains of all issuers, and recounts stored certificates from storage. It is
+useful after bulk imports or storage-level restores which modified storage
+out-of-band. The number of issuers, keys, and certificates indexed is
+returned.
+`
@@ -0,0 +1,415 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	defaultRenewExpiringWindow = 7 * 24 * time.Hour
+	defaultRenewExpiringLimit  = 100
+	maxRenewExpiringLimit      = 1000
+)
+
+func pathRenewExpiringCerts(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/renew-expiring",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "renew",
+			OperationSuffix: "expiring-certs",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"role": {
+				Type: framework.TypeString,
+				Description: `The role to renew certificates with. Only
+certificates recorded as issued under this role are considered.`,
+				Required: true,
+			},
+			"expiring_within": {
+				Type: framework.TypeDurationSecond,
+				Description: `Renew certificates expiring within this
+duration from now. Defaults to 7 days.`,
+				Default: int(defaultRenewExpiringWindow.Seconds()),
+			},
+			"ttl": {
+				Type: framework.TypeDurationSecond,
+				Description: `The requested TTL of the renewed certificates;
+defaults to the role's.`,
+			},
+			"dry_run": {
+				Type: framework.TypeBool,
+				Description: `If true, report which certificates would be
+renewed or skipped without issuing anything.`,
+				Default: false,
+			},
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional serial number to resume after, as returned in next_after; not required to exist.`,
+			},
+			"limit": {
+				Type: framework.TypeInt,
+				Description: `Maximum number of certificates to renew or skip
+in this batch. Defaults to 100; larger values are clamped to 1000.`,
+				Default: defaultRenewExpiringLimit,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathRenewExpiringWrite,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"renewed": {
+								Type:        framework.TypeMap,
+								Description: `Map of renewed serial numbers to the serial numbers of their replacements; empty values on dry runs`,
+								Required:    true,
+							},
+							"certificates": {
+								Type:        framework.TypeMap,
+								Description: `Map of new serial numbers to their certificate, private key, private key type and expiration`,
+								Required:    false,
+							},
+							"skipped": {
+								Type:        framework.TypeMap,
+								Description: `Map of serial numbers which could not be renewed to the reason why`,
+								Required:    true,
+							},
+							"dry_run": {
+								Type:        framework.TypeBool,
+								Description: `Whether this was a dry run`,
+								Required:    true,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next batch; empty once all certificates were examined`,
+								Required:    true,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further certificates may remain after this batch`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathRenewExpiringHelpSyn,
+		HelpDescription: pathRenewExpiringHelpDesc,
+	}
+}
+
+func (b *backend) pathRenewExpiringWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	dryRun := data.Get("dry_run").(bool)
+	if !dryRun && b.System().ReplicationState().HasState(consts.ReplicationPerformanceStandby) {
+		return nil, logical.ErrReadOnly
+	}
+
+	roleName := data.Get("role").(string)
+	if len(roleName) == 0 {
+		return logical.ErrorResponse("missing required role"), nil
+	}
+
+	window := time.Duration(data.Get("expiring_within").(int)) * time.Second
+	if window <= 0 {
+		return logical.ErrorResponse("expiring_within must be positive"), nil
+	}
+
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = defaultRenewExpiringLimit
+	}
+	if limit > maxRenewExpiringLimit {
+		limit = maxRenewExpiringLimit
+	}
+
+	// Renew the whole batch within a single transaction if available, so
+	// that storage reflects either all or none of the renewals.
+	originalStorage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		txn, err := txnStorage.BeginTx(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer txn.Rollback(ctx)
+		req.Storage = txn
+	}
+	defer func() { req.Storage = originalStorage }()
+
+	role, err := b.getRole(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", roleName)), nil
+	}
+
+	issuerName := role.Issuer
+	if len(issuerName) == 0 {
+		issuerName = defaultRef
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	signingBundle, issuerId, err := sc.fetchCAInfoWithIssuer(issuerName, IssuanceUsage)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(fmt.Sprintf("could not fetch the CA certificate (was one set?): %s", err)), nil
+		default:
+			return nil, fmt.Errorf("error fetching CA certificate: %w", err)
+		}
+	}
+
+	candidates, nextAfter, err := findExpiringCerts(sc, role.Name, time.Now().Add(window), after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := pathIssue(b).Fields
+	renewed := make(map[string]interface{}, len(candidates))
+	certificates := make(map[string]interface{}, len(candidates))
+	skipped := map[string]interface{}{}
+	for _, candidate := range candidates {
+		serial := serialFromCert(candidate)
+
+		input, err := buildRenewalInput(req, schema, role, candidate, data)
+		if err != nil {
+			skipped[serial] = err.Error()
+			continue
+		}
+
+		if dryRun {
+			// Only evaluate the role's policy.
+			if _, _, err := generateCreationBundle(b, input, signingBundle, nil); err != nil {
+				if _, ok := err.(errutil.UserError); !ok {
+					return nil, err
+				}
+				skipped[serial] = err.Error()
+				continue
+			}
+			renewed[serial] = ""
+			continue
+		}
+
+		parsedBundle, _, err := generateCert(sc, input, signingBundle, false, rand.Reader)
+		if err != nil {
+			if _, ok := err.(errutil.UserError); !ok {
+				return nil, fmt.Errorf("error renewing certificate %s: %w", serial, err)
+			}
+			skipped[serial] = err.Error()
+			continue
+		}
+
+		cb, err := parsedBundle.ToCertBundle()
+		if err != nil {
+			return nil, fmt.Errorf("error converting raw cert bundle to cert bundle: %w", err)
+		}
+
+		if !role.NoStore {
+			if err := storeCertificate(sc, parsedBundle, &certIssuanceEntry{IssuerID: issuerId, Role: role.Name, RACredential: len(role.RANamespaces) > 0}); err != nil {
+				return nil, err
+			}
+			if role.EscrowPrivateKey {
+				if err := sc.writeEscrowedKey(parsedBundle); err != nil {
+					return nil, fmt.Errorf("unable to escrow private key: %w", err)
+				}
+			}
+		}
+
+		renewed[serial] = cb.SerialNumber
+		certificates[cb.SerialNumber] = map[string]interface{}{
+			"certificate":      cb.Certificate,
+			"private_key":      cb.PrivateKey,
+			"private_key_type": cb.PrivateKeyType,
+			"expiration":       parsedBundle.Certificate.NotAfter.Unix(),
+		}
+	}
+
+	if txn, ok := req.Storage.(logical.Transaction); ok && req.Storage != originalStorage && !dryRun {
+		if err := txn.Commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	respData := map[string]interface{}{
+		"renewed":    renewed,
+		"skipped":    skipped,
+		"dry_run":    dryRun,
+		"next_after": nextAfter,
+		"has_more":   nextAfter != "",
+	}
+	if !dryRun {
+		respData["certificates"] = certificates
+	}
+
+	return &logical.Response{Data: respData}, nil
+}
+
+// findExpiringCerts returns up to limit stored, unrevoked leaf certificates
+// recorded as issued under roleName which are still valid but expire before
+// cutoff, starting after the normalized serial after. When the limit is
+// reached, it also returns the serial to resume after. Certificates issued
+// before the mount recorded their role are never selected.
+func findExpiringCerts(sc *storageContext, roleName string, cutoff time.Time, after string, limit int) ([]*x509.Certificate, string, error) {
+	now := time.Now()
+
+	var expiring []*x509.Certificate
+	for {
+		entries, err := sc.Storage.ListPage(sc.Context, "certs/", after, 1000)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1]
+
+		for _, entry := range entries {
+			issuance, err := sc.fetchCertIssuance(entry)
+			if err != nil {
+				return nil, "", err
+			}
+			if issuance == nil || issuance.Role != roleName {
+				continue
+			}
+
+			certEntry, err := sc.Storage.Get(sc.Context, "certs/"+entry)
+			if err != nil {
+				return nil, "", err
+			}
+			if certEntry == nil || len(certEntry.Value) == 0 {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(certEntry.Value)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to parse certificate for %s: %w", denormalizeSerial(entry), err)
+			}
+
+			if cert.IsCA || now.After(cert.NotAfter) || cert.NotAfter.After(cutoff) {
+				continue
+			}
+
+			revInfo, err := sc.fetchRevocationInfo(serialFromCert(cert))
+			if err != nil {
+				return nil, "", err
+			}
+			if revInfo != nil {
+				continue
+			}
+
+			if len(expiring) == limit {
+				return expiring, serialFromCert(expiring[len(expiring)-1]), nil
+			}
+			expiring = append(expiring, cert)
+		}
+	}
+
+	return expiring, "", nil
+}
+
+// buildRenewalInput prepares an issuance request reproducing the common name
+// and SANs of cert under role. Other subject fields come from the role, as
+// with any issuance.
+func buildRenewalInput(req *logical.Request, schema map[string]*framework.FieldSchema, role *roleEntry, cert *x509.Certificate, data *framework.FieldData) (*inputBundle, error) {
+	var altNames []string
+	altNames = append(altNames, cert.DNSNames...)
+	altNames = append(altNames, cert.EmailAddresses...)
+
+	var ipSans []string
+	for _, ip := range cert.IPAddresses {
+		ipSans = append(ipSans, ip.String())
+	}
+
+	var uriSans []string
+	for _, uri := range cert.URIs {
+		uriSans = append(uriSans, uri.String())
+	}
+
+	raw := map[string]interface{}{
+		"common_name":          cert.Subject.CommonName,
+		"alt_names":            strings.Join(altNames, ","),
+		"ip_sans":              ipSans,
+		"uri_sans":             uriSans,
+		"exclude_cn_from_sans": true,
+	}
+	if ttl, ok := data.GetOk("ttl"); ok {
+		raw["ttl"] = ttl
+	}
+
+	// Roles permitting any key type can't choose one for us; keep the
+	// renewed certificate's key type and size.
+	renewalRole := *role
+	if renewalRole.KeyType == "any" {
+		switch pub := cert.PublicKey.(type) {
+		case *rsa.PublicKey:
+			renewalRole.KeyType, renewalRole.KeyBits = "rsa", pub.N.BitLen()
+		case *ecdsa.PublicKey:
+			renewalRole.KeyType, renewalRole.KeyBits = "ec", pub.Curve.Params().BitSize
+		case ed25519.PublicKey:
+			renewalRole.KeyType, renewalRole.KeyBits = "ed25519", 0
+		default:
+			return nil, fmt.Errorf("unsupported key type %T for renewal under a role with key type any", cert.PublicKey)
+		}
+
+		var err error
+		renewalRole.KeyBits, renewalRole.SignatureBits, err = certutil.ValidateDefaultOrValueKeyTypeSignatureLength(renewalRole.KeyType, renewalRole.KeyBits, renewalRole.SignatureBits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &inputBundle{
+		req: req,
+		apiData: &framework.FieldData{
+			Raw:    raw,
+			Schema: schema,
+		},
+		role: &renewalRole,
+	}, nil
+}
+
+const pathRenewExpiringHelpSyn = `
+Re-issue stored certificates which are about to expire.
+`
+
+const pathRenewExpiringHelpDesc = `
+This endpoint finds stored, unrevoked certificates issued under the role which
+expire within the expiring_within window, and re-issues each under the role
+with a fresh key, keeping its common name and Subject Alternative Names.
+Certificates the role's current policy no longer permits are skipped and
+reported along with the reason. With dry_run, nothing is issued.
+
+At most limit certificates are handled per request; when more may remain,
+next_after gives the value of after for the next batch. Each batch is written
+within a single storage transaction when available. Certificates issued before
+the mount recorded the role issuing them are never renewed.
+`
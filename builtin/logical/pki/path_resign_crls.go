@@ -317,6 +317,17 @@ func (b *backend) pathUpdateSignRevocationListHandler(ctx context.Context, reque
 	}
 
 	sc := b.makeStorageContext(ctx, request.Storage)
+	cfg, err := b.crlBuilder.getConfigWithUpdate(sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading CRL config: %w", err)
+	}
+
+	for i, revokedCert := range revokedCerts {
+		if err := validateRevocationTime(cfg, revokedCert.RevocationTime); err != nil {
+			return logical.ErrorResponse("invalid revocation_time on entry %d: %v", i, err), nil
+		}
+	}
+
 	caBundle, err := getCaBundle(sc, issuerRef)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), nil
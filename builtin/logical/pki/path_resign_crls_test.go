@@ -510,3 +510,54 @@ func extractSerialsFromCrl(t *testing.T, crl *x509.RevocationList) map[string]ti
 	}
 	return serials
 }
+
+func TestSignRevocationList_RevocationTimeSkew(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "test.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"max_revocation_time_skew": "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, "1h", resp.Data["max_revocation_time_skew"])
+
+	signWithTime := func(revTime time.Time) (*logical.Response, error) {
+		return CBWrite(b, s, "issuer/default/sign-revocation-list", map[string]interface{}{
+			"crl_number":  "1",
+			"next_update": "12h",
+			"revoked_certs": []map[string]interface{}{
+				{
+					"serial_number":   "37:60:16:e4:85:d5:96:38:3a:ed:31:06:8d:ed:7a:46:d4:22:63:d8",
+					"revocation_time": revTime.Format(time.RFC3339),
+				},
+			},
+		})
+	}
+
+	resp, err = signWithTime(time.Now().Add(-30 * time.Minute))
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = signWithTime(time.Now().Add(2 * time.Hour))
+	require.ErrorContains(t, err, "in the future")
+
+	_, err = signWithTime(time.Now().Add(-2 * time.Hour))
+	require.ErrorContains(t, err, "in the past")
+
+	// A zero skew disables the check entirely.
+	resp, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"max_revocation_time_skew": "0s",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = signWithTime(time.Now().Add(-48 * time.Hour))
+	requireSuccessNonNilResponse(t, resp, err)
+
+	_, err = CBWrite(b, s, "config/crl", map[string]interface{}{
+		"max_revocation_time_skew": "-1h",
+	})
+	require.Error(t, err)
+}
@@ -4,17 +4,23 @@
 package pki
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/subtle"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/openbao/openbao/sdk/v2/helper/consts"
 
@@ -22,6 +28,7 @@ import (
 	"github.com/openbao/openbao/sdk/v2/helper/certutil"
 	"github.com/openbao/openbao/sdk/v2/helper/errutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/crypto/ocsp"
 )
 
 func pathListCertsRevoked(b *backend) *framework.Path {
@@ -67,6 +74,82 @@ func pathListCertsRevoked(b *backend) *framework.Path {
 	}
 }
 
+func pathListCertsRevokedDetailed(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "certs/revoked/detailed/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "revoked-certs-detailed",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+			"include_next_cursor": {
+				Type: framework.TypeBool,
+				Description: `Whether to return next_cursor, the value of after
+for the following page. It is empty once all entries were returned.`,
+				Default: false,
+			},
+			"reason": {
+				Type: framework.TypeString,
+				Description: `Optional revocation reason, as its numeric RFC 5280
+code or its name (such as keyCompromise), to list only certificates revoked
+for that reason. Certificates without a recorded reason are then omitted.
+When set, limit applies to the matching certificates.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathListRevokedCertsDetailedHandler,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers of the revoked certificates`,
+								Required:    false,
+							},
+							"key_info": {
+								Type:        framework.TypeMap,
+								Description: `Revocation details of each certificate, by serial number`,
+								Required:    false,
+							},
+							"next_cursor": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page, when include_next_cursor is set`,
+								Required:    false,
+							},
+							"next_after": {
+								Type:        framework.TypeString,
+								Description: `Value of after for the next page; empty once the listing is exhausted`,
+								Required:    false,
+							},
+							"has_more": {
+								Type:        framework.TypeBool,
+								Description: `Whether further entries may remain after this page`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathListRevokedDetailedHelpSyn,
+		HelpDescription: pathListRevokedDetailedHelpDesc,
+	}
+}
+
 func pathRevoke(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: `revoke`,
@@ -191,6 +274,187 @@ be in PEM format.`,
 	}
 }
 
+// revocationReasons maps the reason names accepted by revoke/by-public-key
+// to their RFC 5280 reason codes. certificateHold and removeFromCRL are
+// managed through the hold and release endpoints instead.
+var revocationReasons = map[string]int{
+	"unspecified":            ocsp.Unspecified,
+	"key_compromise":         ocsp.KeyCompromise,
+	"affiliation_changed":    ocsp.AffiliationChanged,
+	"superseded":             ocsp.Superseded,
+	"cessation_of_operation": ocsp.CessationOfOperation,
+	"privilege_withdrawn":    ocsp.PrivilegeWithdrawn,
+}
+
+func pathRevokeByPublicKey(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `revoke/by-public-key`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "revoke",
+			OperationSuffix: "by-public-key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"public_key": {
+				Type: framework.TypeString,
+				Description: `Public key whose certificates should be revoked,
+in PEM format.`,
+			},
+			"public_key_sha256": {
+				Type: framework.TypeString,
+				Description: `Hex-encoded SHA-256 hash of the DER-encoded
+SubjectPublicKeyInfo whose certificates should be revoked; may be used
+instead of public_key.`,
+			},
+			"reason": {
+				Type: framework.TypeString,
+				Description: `Revocation reason recorded on the CRL and in OCSP
+responses: one of unspecified, key_compromise, affiliation_changed,
+superseded, cessation_of_operation or privilege_withdrawn.`,
+				Default: "key_compromise",
+			},
+			"dry_run": {
+				Type: framework.TypeBool,
+				Description: `If true, report which certificates would be
+revoked without revoking them.`,
+				Default: false,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.metricsWrap("revoke", noRole, b.pathRevokeByPublicKeyWrite),
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"revoked": {
+								Type:        framework.TypeStringSlice,
+								Description: `Serial numbers of the certificates revoked, or which would be revoked on dry runs`,
+								Required:    true,
+							},
+							"skipped": {
+								Type:        framework.TypeMap,
+								Description: `Map of serial numbers of matching certificates which were not revoked to the reason why`,
+								Required:    true,
+							},
+							"dry_run": {
+								Type:        framework.TypeBool,
+								Description: `Whether this was a dry run`,
+								Required:    true,
+							},
+							"parse_errors": {
+								Type:        framework.TypeMap,
+								Description: `Errors of stored certificates which failed to parse and so could not be matched, by serial number`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathRevokeByPublicKeyHelpSyn,
+		HelpDescription: pathRevokeByPublicKeyHelpDesc,
+	}
+}
+
+func pathHoldCert(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `hold`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "hold",
+			OperationSuffix: "certificate",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial_number": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+				Required: true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.metricsWrap("hold", noRole, b.pathHoldWrite),
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"revocation_time": {
+								Type:        framework.TypeInt64,
+								Description: `Revocation Time`,
+								Required:    false,
+							},
+							"revocation_time_rfc3339": {
+								Type:        framework.TypeTime,
+								Description: `Revocation Time`,
+								Required:    false,
+							},
+							"state": {
+								Type:        framework.TypeString,
+								Description: `Revocation State`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathHoldHelpSyn,
+		HelpDescription: pathHoldHelpDesc,
+	}
+}
+
+func pathReleaseCert(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `release`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "release",
+			OperationSuffix: "certificate",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial_number": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+				Required: true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.metricsWrap("release", noRole, b.pathReleaseWrite),
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"state": {
+								Type:        framework.TypeString,
+								Description: `Revocation State`,
+								Required:    false,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathReleaseHelpSyn,
+		HelpDescription: pathReleaseHelpDesc,
+	}
+}
+
 func pathRotateCRL(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: `crl/rotate`,
@@ -563,6 +827,290 @@ func (b *backend) pathRevokeWrite(ctx context.Context, req *logical.Request, dat
 	return revokeCert(sc, config, cert)
 }
 
+func (b *backend) pathRevokeByPublicKeyWrite(ctx context.Context, req *logical.Request, data *framework.FieldData, _ *roleEntry) (*logical.Response, error) {
+	keyHash, err := publicKeyHashFromRequest(data)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	reasonName := data.Get("reason").(string)
+	reason, ok := revocationReasons[reasonName]
+	if !ok {
+		return logical.ErrorResponse(fmt.Sprintf("unknown revocation reason %q", reasonName)), nil
+	}
+
+	dryRun := data.Get("dry_run").(bool)
+	if !dryRun && b.System().ReplicationState().HasState(consts.ReplicationPerformanceStandby) {
+		return nil, logical.ErrReadOnly
+	}
+
+	b.revokeStorageLock.Lock()
+	defer b.revokeStorageLock.Unlock()
+
+	// Revoke all matching certificates within a single transaction if
+	// available, so that either all or none of them are revoked.
+	originalStorage := req.Storage
+	if txnStorage, ok := req.Storage.(logical.TransactionalStorage); ok {
+		txn, err := txnStorage.BeginTx(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer txn.Rollback(ctx)
+		req.Storage = txn
+	}
+	defer func() { req.Storage = originalStorage }()
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.Backend.crlBuilder.getConfigWithUpdate(sc)
+	if err != nil {
+		return nil, fmt.Errorf("error revoking by public key: failed reading config: %w", err)
+	}
+
+	issuerIDCertMap, err := fetchIssuerMapForRevocationChecking(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, parseErrors, err := findCertsByPublicKey(sc, keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &logical.Response{}
+	revoked := []string{}
+	skipped := map[string]interface{}{}
+
+	// Write every revocation entry first and update the CRLs once
+	// afterwards, rather than once per certificate.
+	var crlSerials []string
+	for _, cert := range matches {
+		serial := serialFromCert(cert)
+
+		if isIssuerCert(cert, issuerIDCertMap) {
+			skipped[serial] = "certificate is an issuer; revoke it via its issuer's revoke endpoint instead"
+			continue
+		}
+
+		revInfo, err := sc.fetchRevocationInfo(serial)
+		if err != nil {
+			return nil, err
+		}
+		if revInfo != nil && !revInfo.OnHold {
+			skipped[serial] = "certificate is already revoked"
+			continue
+		}
+
+		if revInfo == nil && cert.NotAfter.Before(time.Now().Add(2*time.Second)) && !config.AllowExpiredCertRevocation {
+			skipped[serial] = "certificate has expired"
+			continue
+		}
+
+		revoked = append(revoked, serial)
+		if dryRun {
+			continue
+		}
+
+		revResp, updateCRLs, err := writeRevocationEntry(sc, config, cert, false, reason)
+		if err != nil {
+			return nil, fmt.Errorf("error revoking certificate %s: %w", serial, err)
+		}
+		if revResp != nil {
+			if revResp.IsError() {
+				return revResp, nil
+			}
+			for _, warning := range revResp.Warnings {
+				resp.AddWarning(fmt.Sprintf("%s: %s", serial, warning))
+			}
+		}
+		if updateCRLs {
+			crlSerials = append(crlSerials, serial)
+		}
+	}
+
+	if !dryRun {
+		crlResp, err := updateCRLsAfterRevocationChanges(sc, config, resp, crlSerials)
+		if err != nil {
+			return nil, err
+		}
+		if crlResp.IsError() {
+			return crlResp, nil
+		}
+	}
+
+	if txn, ok := req.Storage.(logical.Transaction); ok && req.Storage != originalStorage && !dryRun {
+		if err := txn.Commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp.Data = map[string]interface{}{
+		"revoked": revoked,
+		"skipped": skipped,
+		"dry_run": dryRun,
+	}
+	if len(parseErrors) > 0 {
+		resp.Data["parse_errors"] = parseErrors
+		resp.AddWarning(fmt.Sprintf("%d stored certificates failed to parse and could not be checked against the public key; see parse_errors", len(parseErrors)))
+	}
+	return resp, nil
+}
+
+// publicKeyHashFromRequest returns the SHA-256 hash of the SubjectPublicKeyInfo
+// identified by either the public_key or public_key_sha256 field.
+func publicKeyHashFromRequest(data *framework.FieldData) ([]byte, error) {
+	keyPem := data.Get("public_key").(string)
+	keyHashHex := data.Get("public_key_sha256").(string)
+
+	switch {
+	case len(keyPem) == 0 && len(keyHashHex) == 0:
+		return nil, errors.New("either public_key or public_key_sha256 must be provided")
+	case len(keyPem) > 0 && len(keyHashHex) > 0:
+		return nil, errors.New("only one of public_key or public_key_sha256 may be provided")
+	case len(keyHashHex) > 0:
+		keyHash, err := hex.DecodeString(strings.ReplaceAll(keyHashHex, ":", ""))
+		if err != nil || len(keyHash) != sha256.Size {
+			return nil, errors.New("public_key_sha256 must be a hex-encoded SHA-256 hash")
+		}
+		return keyHash, nil
+	}
+
+	block, _ := pem.Decode([]byte(keyPem))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("public_key must be a PEM-encoded PUBLIC KEY block")
+	}
+
+	// Re-encode the parsed key so that the hash matches the encoding used
+	// by issued certificates.
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public_key: %w", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public_key: %w", err)
+	}
+
+	keyHash := sha256.Sum256(der)
+	return keyHash[:], nil
+}
+
+// findCertsByPublicKey returns the stored certificates whose
+// SubjectPublicKeyInfo hashes to keyHash, ordered by serial number, along
+// with the errors of stored certificates which failed to parse, by serial.
+func findCertsByPublicKey(sc *storageContext, keyHash []byte) ([]*x509.Certificate, map[string]interface{}, error) {
+	var matches []*x509.Certificate
+	parseErrors := make(map[string]interface{})
+	after := ""
+	for {
+		entries, err := sc.Storage.ListPage(sc.Context, "certs/", after, 1000)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1]
+
+		for _, entry := range entries {
+			certEntry, err := sc.Storage.Get(sc.Context, "certs/"+entry)
+			if err != nil {
+				return nil, nil, err
+			}
+			if certEntry == nil || len(certEntry.Value) == 0 {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(certEntry.Value)
+			if err != nil {
+				parseErrors[denormalizeSerial(entry)] = err.Error()
+				continue
+			}
+
+			certKeyHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			if bytes.Equal(certKeyHash[:], keyHash) {
+				matches = append(matches, cert)
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].SerialNumber.Cmp(matches[j].SerialNumber) < 0
+	})
+	return matches, parseErrors, nil
+}
+
+func isIssuerCert(cert *x509.Certificate, issuerIDCertMap map[issuerID]*x509.Certificate) bool {
+	for _, issuerCert := range issuerIDCertMap {
+		if bytes.Equal(issuerCert.Raw, cert.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *backend) pathHoldWrite(ctx context.Context, req *logical.Request, data *framework.FieldData, _ *roleEntry) (*logical.Response, error) {
+	serial := data.Get("serial_number").(string)
+	if len(serial) == 0 {
+		return logical.ErrorResponse("The serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.Backend.crlBuilder.getConfigWithUpdate(sc)
+	if err != nil {
+		return nil, fmt.Errorf("error holding serial: %s: failed reading config: %w", serial, err)
+	}
+
+	certEntry, err := fetchCertBySerial(sc, "certs/", serial)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	if certEntry == nil {
+		return logical.ErrorResponse(fmt.Sprintf("certificate with serial %s not found.", serial)), nil
+	}
+
+	cert, err := x509.ParseCertificate(certEntry.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate: %w", err)
+	}
+
+	if b.System().ReplicationState().HasState(consts.ReplicationPerformanceStandby) {
+		return nil, logical.ErrReadOnly
+	}
+
+	b.revokeStorageLock.Lock()
+	defer b.revokeStorageLock.Unlock()
+
+	return revokeOrHoldCert(sc, config, cert, true, ocsp.CertificateHold)
+}
+
+func (b *backend) pathReleaseWrite(ctx context.Context, req *logical.Request, data *framework.FieldData, _ *roleEntry) (*logical.Response, error) {
+	serial := data.Get("serial_number").(string)
+	if len(serial) == 0 {
+		return logical.ErrorResponse("The serial number must be provided"), nil
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+	config, err := sc.Backend.crlBuilder.getConfigWithUpdate(sc)
+	if err != nil {
+		return nil, fmt.Errorf("error releasing serial: %s: failed reading config: %w", serial, err)
+	}
+
+	if b.System().ReplicationState().HasState(consts.ReplicationPerformanceStandby) {
+		return nil, logical.ErrReadOnly
+	}
+
+	b.revokeStorageLock.Lock()
+	defer b.revokeStorageLock.Unlock()
+
+	return releaseCert(sc, config, serial)
+}
+
 func (b *backend) pathRotateCRLRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
 	b.revokeStorageLock.RLock()
 	defer b.revokeStorageLock.RUnlock()
@@ -646,6 +1194,98 @@ func (b *backend) pathListRevokedCertsHandler(ctx context.Context, request *logi
 	return logical.ListResponse(revokedCerts), nil
 }
 
+func (b *backend) pathListRevokedCertsDetailedHandler(ctx context.Context, request *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, request.Storage)
+
+	after, err := normalizeSerialCursor(data.Get("after").(string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	filtering := false
+	var reasonFilter int
+	if reasonValue := data.Get("reason").(string); reasonValue != "" {
+		reasonFilter, err = parseRevocationReason(reasonValue)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+		filtering = true
+	}
+
+	// When filtering, the limit applies to the matching certificates, so
+	// keep reading pages until it is reached or storage is exhausted.
+	keys := []string{}
+	keyInfo := make(map[string]interface{})
+	var nextCursor string
+	cursor := after
+	for {
+		revokedCerts, err := sc.listRevokedCertsPage(cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		full := false
+		for _, serial := range revokedCerts {
+			cursor = serial
+
+			entry, err := sc.Storage.Get(ctx, revokedPath+serial)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching revocation entry for serial %s: %w", serial, err)
+			}
+			if entry == nil {
+				// Removed, for instance by tidy, since it was listed.
+				continue
+			}
+
+			var revInfo revocationInfo
+			if err := entry.DecodeJSON(&revInfo); err != nil {
+				return nil, fmt.Errorf("error decoding revocation entry for serial %s: %w", serial, err)
+			}
+
+			if filtering {
+				reason, recorded := revocationReasonOf(&revInfo)
+				if !recorded || reason != reasonFilter {
+					continue
+				}
+			}
+
+			info := map[string]interface{}{
+				"certificate_issuer": revInfo.CertificateIssuer.String(),
+			}
+			addRevocationDetails(info, &revInfo)
+
+			keys = append(keys, denormalizeSerial(serial))
+			keyInfo[denormalizeSerial(serial)] = info
+
+			if filtering && limit > 0 && len(keys) == limit {
+				nextCursor = denormalizeSerial(serial)
+				full = true
+				break
+			}
+		}
+
+		if !filtering {
+			// Entries removed since being listed still advance the cursor.
+			nextCursor = certListNextCursor(revokedCerts, limit)
+			if nextCursor != "" {
+				nextCursor = denormalizeSerial(nextCursor)
+			}
+			break
+		}
+		if full || limit <= 0 || len(revokedCerts) < limit {
+			break
+		}
+	}
+
+	resp := logical.ListResponseWithInfo(keys, keyInfo)
+	addCertListPagination(resp, data, nextCursor)
+	return resp, nil
+}
+
 const pathRevokeHelpSyn = `
 Revoke a certificate by serial number or with explicit certificate.
 
@@ -658,6 +1298,39 @@ This allows certificates to be revoke. A root token or corresponding
 private key is required.
 `
 
+const pathRevokeByPublicKeyHelpSyn = `
+Revoke all certificates sharing a public key.
+`
+
+const pathRevokeByPublicKeyHelpDesc = `
+This finds every stored certificate whose SubjectPublicKeyInfo matches the
+given public key (or its SHA-256 hash) and revokes each with the supplied
+reason, keyCompromise by default. Issuers, already-revoked and expired
+certificates are skipped and reported. The revocations are written within a
+single storage transaction when available. With dry_run, nothing is revoked.
+`
+
+const pathHoldHelpSyn = `
+Place a certificate on hold by serial number.
+`
+
+const pathHoldHelpDesc = `
+This adds the certificate to the CRL with the certificateHold reason. Unlike
+a revocation, a hold may later be lifted with the /release endpoint; revoking
+a held certificate makes its revocation permanent.
+`
+
+const pathReleaseHelpSyn = `
+Release a held certificate by serial number.
+`
+
+const pathReleaseHelpDesc = `
+This removes a certificate previously placed on hold from the CRL, restoring
+it to a valid state. When delta CRLs are enabled, the certificate is listed
+on the delta CRL with the removeFromCRL reason until the next complete CRL is
+built.
+`
+
 const pathRotateCRLHelpSyn = `
 Force a rebuild of the CRL.
 `
@@ -681,3 +1354,14 @@ List all revoked serial numbers within the local cluster
 const pathListRevokedHelpDesc = `
 Returns a list of serial numbers for revoked certificates in the local cluster.
 `
+
+const pathListRevokedDetailedHelpSyn = `
+List revoked certificates within the local cluster, with their revocation details.
+`
+
+const pathListRevokedDetailedHelpDesc = `
+Returns the serial numbers of revoked certificates in the local cluster, along
+with key_info holding the revocation time, reason when recorded, and the ID of
+the issuer each was revoked under. The reason parameter restricts the listing
+to certificates revoked for a given reason.
+`
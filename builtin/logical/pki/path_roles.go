@@ -189,6 +189,12 @@ Any valid URI is accepted, these values support globbing.`,
 			Description: `If set, an array of allowed other names to put in SANs. These values support globbing and must be in the format <oid>;<type>:<value>. Currently only "utf8" is a valid type. All values, including globbing values, must use this syntax, with the exception being a single "*" which allows any OID and any value (but type must still be utf8).`,
 		},
 
+		"allowed_directory_name_sans": {
+			Type:        framework.TypeStringSlice,
+			Required:    true,
+			Description: `If set, an array of allowed directory names to put in SANs. These values support globbing and are matched against the normalized RFC 4514 form of the requested DN. A single "*" allows any directory name.`,
+		},
+
 		"allowed_serial_numbers": {
 			Type:        framework.TypeCommaStringSlice,
 			Required:    true,
@@ -344,6 +350,12 @@ this value in certificates issued by this role.`,
 this value in certificates issued by this role.`,
 		},
 
+		"subject_dn_order": {
+			Type: framework.TypeCommaStringSlice,
+			Description: `If set, the order in which subject attribute
+types are emitted in certificates issued by this role.`,
+		},
+
 		"generate_lease": {
 			Type: framework.TypeBool,
 			Description: `
@@ -414,6 +426,67 @@ The value format should be given in UTC format YYYY-MM-ddTHH:MM:SSZ.`,
 			Description: `Reference to the issuer used to sign requests
 serviced by this role.`,
 		},
+		"allowed_issuers": {
+			Type: framework.TypeCommaStringSlice,
+			Description: `An array of issuers, by name or identifier, which
+callers may request in place of issuer_ref on the sign and issue paths.
+A single "*" allows any issuer.`,
+		},
+		"ra_namespaces": {
+			Type: framework.TypeCommaStringSlice,
+			Description: `If set, certificates issued by this role are
+registration authority credentials for these DNS namespaces.`,
+		},
+		"ra_extension_oid": {
+			Type:        framework.TypeString,
+			Description: `OID of the extension carrying RA namespaces.`,
+		},
+		"require_ra_credential": {
+			Type: framework.TypeBool,
+			Description: `If true, requests against this role must present
+an RA credential and are limited to its namespaces.`,
+		},
+		"issuer_selection": {
+			Type:        framework.TypeString,
+			Description: `How the signing issuer is chosen on the sign path: "role" or "subject".`,
+		},
+		"ttl_issuer_fraction": {
+			Type:        framework.TypeFloat,
+			Description: `If set, the default TTL as a fraction of the issuer's remaining validity.`,
+		},
+		"smime_capabilities": {
+			Type:        framework.TypeCommaStringSlice,
+			Description: `S/MIME capabilities advertised in issued certificates, in order of preference.`,
+		},
+		"idn_handling": {
+			Type:        framework.TypeString,
+			Description: `How internationalized domain names are handled: "preserve", "a_label" or "a_label_single_script".`,
+		},
+		"allow_public_key_signing": {
+			Type: framework.TypeBool,
+			Description: `If true, certificates may be issued for bare public
+keys, without proof of possession, through sign-public-key/:role.`,
+		},
+		"escrow_private_key": {
+			Type:        framework.TypeBool,
+			Description: `If true, private keys generated by the mount under this role are escrowed and retrievable by privileged callers.`,
+		},
+		"enforce_cabf_ttl": {
+			Type:        framework.TypeBool,
+			Description: `If true, the validity of issued certificates is capped at the CA/Browser Forum maximum configured in config/issuance.`,
+		},
+		"cabf_ttl_behavior": {
+			Type:        framework.TypeString,
+			Description: `Whether requests exceeding the CA/Browser Forum maximum validity are truncated to it, "truncate", or rejected, "err".`,
+		},
+		"allowed_san_types": {
+			Type:        framework.TypeCommaStringSlice,
+			Description: `If set, the only types of Subject Alternative Names which may be issued under this role.`,
+		},
+		"ocsp_only_revocation": {
+			Type:        framework.TypeBool,
+			Description: `If true, revocations of certificates issued under this role are reported by OCSP but not listed on CRLs.`,
+		},
 	}
 
 	return &framework.Path{
@@ -565,6 +638,14 @@ Any valid URI is accepted, these values support globbing.`,
 				},
 			},
 
+			"allowed_directory_name_sans": {
+				Type:        framework.TypeStringSlice,
+				Description: `If set, an array of allowed directory names to put in SANs. These values support globbing and are matched against the normalized RFC 4514 form of the requested DN. A single "*" allows any directory name.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Allowed Directory Name Subject Alternative Names",
+				},
+			},
+
 			"allowed_serial_numbers": {
 				Type:        framework.TypeCommaStringSlice,
 				Description: `If set, an array of allowed serial numbers to put in Subject. These values support globbing.`,
@@ -754,6 +835,18 @@ this value in certificates issued by this role.`,
 this value in certificates issued by this role.`,
 			},
 
+			"subject_dn_order": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `If set, the order in which subject attribute
+types (such as CN, C, O, OU, L, ST, STREET, POSTALCODE, and SERIALNUMBER) are
+emitted in certificates issued by this role. Listed types come first, in the
+given order; any others follow in the default order. Defaults to the default
+order (C, O, OU, L, ST, STREET, POSTALCODE, SERIALNUMBER, CN).`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Subject DN Order",
+				},
+			},
+
 			"generate_lease": {
 				Type: framework.TypeBool,
 				Description: `
@@ -840,6 +933,170 @@ The value format should be given in UTC format YYYY-MM-ddTHH:MM:SSZ.`,
 serviced by this role.`,
 				Default: defaultRef,
 			},
+			"allowed_issuers": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `An array of issuers, by name or identifier, which
+callers may request in place of issuer_ref on the sign and issue paths.
+A single "*" allows any issuer.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Allowed Issuers",
+				},
+			},
+			"ra_namespaces": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `If set, certificates issued by this role are
+registration authority (RA) credentials: they carry an extension, identified
+by ra_extension_oid, listing these DNS namespaces. Holders presenting such a
+credential as a TLS client certificate may then request certificates within
+these namespaces from roles with require_ra_credential set.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "RA Namespaces",
+				},
+			},
+			"ra_extension_oid": {
+				Type: framework.TypeString,
+				Description: `OID of the certificate extension which carries
+RA namespaces. Required when ra_namespaces or require_ra_credential is set.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "RA Extension OID",
+				},
+			},
+			"require_ra_credential": {
+				Type: framework.TypeBool,
+				Description: `If true, requests against this role must
+present, as a TLS client certificate, a valid and unrevoked RA credential
+issued by this mount; the requested common name and DNS SANs must fall
+within the credential's namespaces. Defaults to false.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Require RA Credential",
+				},
+			},
+			"issuer_selection": {
+				Type: framework.TypeString,
+				Description: `How the issuer signing CSRs on the sign/:role path
+is chosen. With "role" (the default), the role's issuer_ref is used unless
+overridden by the request's issuer_ref. With "subject", the issuer is resolved
+from the request's issuer_subject or, failing that, from the directoryName in
+the CSR's requested Authority Key Identifier extension; the matching issuer
+must be the role's issuer or permitted by allowed_issuers.`,
+				Default:       issuerSelectionRole,
+				AllowedValues: []interface{}{issuerSelectionRole, issuerSelectionSubject},
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Issuer Selection",
+				},
+			},
+			"ttl_issuer_fraction": {
+				Type: framework.TypeFloat,
+				Description: `If set, in the range (0, 1], the default TTL
+of issued certificates is this fraction of the signing issuer's remaining
+validity at issuance time, instead of ttl. Requested TTLs still override it
+but, unless the issuer's leaf_not_after_behavior is "permit", are capped at
+the issuer's expiration rather than rejected. Cannot be combined with ttl.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "TTL Issuer Fraction",
+				},
+			},
+			"smime_capabilities": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `If set, issued certificates carry a non-critical
+SMIMECapabilities extension (RFC 4262) advertising these capabilities, in
+order of preference. Each entry is an OID, optionally followed by parameters
+as "<oid>;INTEGER:<decimal>" or "<oid>;DER:<hex>". Requires
+email_protection_flag; combine with allowed email SANs to issue S/MIME
+certificates.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "S/MIME Capabilities",
+				},
+			},
+			"idn_handling": {
+				Type: framework.TypeString,
+				Description: `How internationalized domain names (IDNs) are
+handled. With "preserve" (the default), DNS SANs are converted to their
+A-label (punycode) form but the common name is used as requested. With
+"a_label", IDNs in the common name, DNS SANs and email addresses are all
+converted to A-labels before being validated against allowed_domains, which
+may themselves be given in either form. "a_label_single_script" additionally
+rejects labels mixing scripts, as confusable names often do.`,
+				Default:       idnHandlingPreserve,
+				AllowedValues: []interface{}{idnHandlingPreserve, idnHandlingALabel, idnHandlingALabelSingleScript},
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "IDN Handling",
+				},
+			},
+			"allow_public_key_signing": {
+				Type: framework.TypeBool,
+				Description: `If true, the sign-public-key/:role endpoint may
+issue certificates under this role for a bare public key, without a CSR.
+As no proof of possession of the private key is verified, the requester may
+obtain a certificate for a key they don't hold. Defaults to false.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Allow Public Key Signing",
+				},
+			},
+			"escrow_private_key": {
+				Type: framework.TypeBool,
+				Description: `If true, private keys generated by the mount when
+issuing certificates under this role are stored, seal-wrapped where
+available, and may later be retrieved through cert/:serial/private-key. Keys
+of CSRs signed under the role are never escrowed, as the mount doesn't hold
+them. Anyone able to read that endpoint can impersonate the certificate's
+holder, so only enable this for roles which require key escrow, and restrict
+access to the endpoint accordingly. Incompatible with no_store. Defaults to
+false.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Escrow Private Key",
+				},
+			},
+			"enforce_cabf_ttl": {
+				Type: framework.TypeBool,
+				Description: `If true, the validity of certificates issued under
+this role, from their Not Before to their Not After, never exceeds the
+CA/Browser Forum maximum for publicly-trusted TLS certificates, regardless of
+the requested ttl or not_after. The maximum defaults to 398 days and is
+configured mount-wide through cabf_max_validity in config/issuance. Defaults
+to false.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Enforce CA/Browser Forum TTL",
+				},
+			},
+			"cabf_ttl_behavior": {
+				Type: framework.TypeString,
+				Description: `How requests exceeding the CA/Browser Forum maximum
+validity are handled when enforce_cabf_ttl is set. With "truncate" (the
+default), the certificate's validity is truncated to the maximum and a warning
+is returned. With "err", the request is rejected.`,
+				Default:       cabfTTLBehaviorTruncate,
+				AllowedValues: []interface{}{cabfTTLBehaviorTruncate, cabfTTLBehaviorErr},
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "CA/Browser Forum TTL Behavior",
+				},
+			},
+			"allowed_san_types": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `If set, the only types of Subject Alternative Names
+(GeneralNames) which may be issued under this role: any of "dns", "ip",
+"email", "uri", "upn", "registeredid", "directoryname" and "othername", where
+"upn" covers User Principal Name other SANs and "othername" all other other
+SANs. Requests including a SAN of any other type, whether given as a parameter
+or in a CSR, are rejected; the common name is only copied into the SANs when
+its type is allowed. This applies in addition to the role's other SAN
+restrictions. When empty, the default, all types are allowed.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Allowed SAN Types",
+				},
+			},
+			"ocsp_only_revocation": {
+				Type: framework.TypeBool,
+				Description: `If true, certificates issued under this role are
+reported as revoked by the OCSP responder once revoked, but are never listed
+on the mount's CRLs, keeping CRLs small for high volumes of short-lived
+certificates. Relying parties which only check CRLs won't see these
+revocations. Only applies to certificates issued while the option is set.
+Incompatible with no_store. Defaults to false.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "OCSP-only Revocation",
+				},
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -1121,6 +1378,7 @@ func (b *backend) pathRoleCreate(ctx context.Context, req *logical.Request, data
 		Province:                      data.Get("province").([]string),
 		StreetAddress:                 data.Get("street_address").([]string),
 		PostalCode:                    data.Get("postal_code").([]string),
+		SubjectDNOrder:                data.Get("subject_dn_order").([]string),
 		GenerateLease:                 new(bool),
 		NoStore:                       data.Get("no_store").(bool),
 		RequireCN:                     data.Get("require_cn").(bool),
@@ -1133,6 +1391,20 @@ func (b *backend) pathRoleCreate(ctx context.Context, req *logical.Request, data
 		NotBefore:                     data.Get("not_before").(string),
 		NotAfter:                      data.Get("not_after").(string),
 		Issuer:                        data.Get("issuer_ref").(string),
+		AllowedIssuers:                data.Get("allowed_issuers").([]string),
+		RANamespaces:                  data.Get("ra_namespaces").([]string),
+		RAExtensionOID:                data.Get("ra_extension_oid").(string),
+		RequireRACredential:           data.Get("require_ra_credential").(bool),
+		IssuerSelection:               data.Get("issuer_selection").(string),
+		TTLIssuerFraction:             data.Get("ttl_issuer_fraction").(float64),
+		SMIMECapabilities:             data.Get("smime_capabilities").([]string),
+		IDNHandling:                   data.Get("idn_handling").(string),
+		AllowPublicKeySigning:         data.Get("allow_public_key_signing").(bool),
+		EscrowPrivateKey:              data.Get("escrow_private_key").(bool),
+		EnforceCABFTTL:                data.Get("enforce_cabf_ttl").(bool),
+		CABFTTLBehavior:               data.Get("cabf_ttl_behavior").(string),
+		AllowedSANTypes:               data.Get("allowed_san_types").([]string),
+		OcspOnlyRevocation:            data.Get("ocsp_only_revocation").(bool),
 		Name:                          name,
 	}
 
@@ -1148,6 +1420,12 @@ func (b *backend) pathRoleCreate(ctx context.Context, req *logical.Request, data
 	}
 	entry.AllowedOtherSANs = allowedOtherSANs
 
+	allowedDirectoryNameSANs := data.Get("allowed_directory_name_sans").([]string)
+	if err := validateAllowedDirectoryNameSANs(allowedDirectoryNameSANs); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	entry.AllowedDirectoryNameSANs = allowedDirectoryNameSANs
+
 	allowWildcardCertificates, present := data.GetOk("allow_wildcard_certificates")
 	if !present {
 		// While not the most secure default, when AllowWildcardCertificates isn't
@@ -1204,6 +1482,15 @@ func validateRole(b *backend, entry *roleEntry, ctx context.Context, s logical.S
 		), nil
 	}
 
+	if entry.TTLIssuerFraction != 0 {
+		if !(entry.TTLIssuerFraction > 0 && entry.TTLIssuerFraction <= 1) {
+			return logical.ErrorResponse(`"ttl_issuer_fraction" value must be greater than 0 and at most 1`), nil
+		}
+		if entry.TTL > 0 {
+			return logical.ErrorResponse(`only one of "ttl" and "ttl_issuer_fraction" may be set`), nil
+		}
+	}
+
 	if entry.KeyBits, entry.SignatureBits, err = certutil.ValidateDefaultOrValueKeyTypeSignatureLength(entry.KeyType, entry.KeyBits, entry.SignatureBits); err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
@@ -1224,6 +1511,50 @@ func validateRole(b *backend, entry *roleEntry, ctx context.Context, s logical.S
 		}
 	}
 
+	if _, err := parseSubjectDNOrder(entry.SubjectDNOrder); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if err := validateRARoleConfig(entry); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if err := validateSMIMERoleConfig(entry); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if err := validateIDNHandling(entry); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if err := validateAllowedSANTypes(entry); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	if entry.EscrowPrivateKey && entry.NoStore {
+		return logical.ErrorResponse(`"escrow_private_key" requires certificates to be stored; it can't be combined with "no_store"`), nil
+	}
+
+	if entry.OcspOnlyRevocation && entry.NoStore {
+		return logical.ErrorResponse(`"ocsp_only_revocation" requires certificates to be stored; it can't be combined with "no_store"`), nil
+	}
+
+	switch entry.CABFTTLBehavior {
+	case "":
+		entry.CABFTTLBehavior = cabfTTLBehaviorTruncate
+	case cabfTTLBehaviorTruncate, cabfTTLBehaviorErr:
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("invalid cabf_ttl_behavior %q: must be %q or %q", entry.CABFTTLBehavior, cabfTTLBehaviorTruncate, cabfTTLBehaviorErr)), nil
+	}
+
+	switch entry.IssuerSelection {
+	case "":
+		entry.IssuerSelection = issuerSelectionRole
+	case issuerSelectionRole, issuerSelectionSubject:
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("invalid issuer_selection %q: must be %q or %q", entry.IssuerSelection, issuerSelectionRole, issuerSelectionSubject)), nil
+	}
+
 	// Ensure issuers ref is set to a non-empty value. Note that we never
 	// resolve the reference (to an issuerId) at role creation time; instead,
 	// resolve it at use time. This allows values such as `default` or other
@@ -1256,6 +1587,10 @@ func validateRole(b *backend, entry *roleEntry, ctx context.Context, s logical.S
 		return nil, errutil.UserError{Err: err.Error()}
 	}
 
+	if entry.EscrowPrivateKey {
+		resp.AddWarning("escrow_private_key is enabled: private keys generated under this role are retained by the mount and readable through cert/:serial/private-key by any caller whose policy permits it")
+	}
+
 	resp.Data = entry.ToResponseData()
 	return resp, nil
 }
@@ -1276,6 +1611,22 @@ func getTimeWithExplicitDefault(data *framework.FieldData, field string, default
 	return defaultValue
 }
 
+// validateAllowedDirectoryNameSANs ensures each allowed directory name,
+// other than the single "*" wildcard, is a syntactically valid DN.
+func validateAllowedDirectoryNameSANs(allowed []string) error {
+	if len(allowed) == 1 && allowed[0] == "*" {
+		return nil
+	}
+
+	for _, name := range allowed {
+		if _, err := parseDirectoryNameSAN(name); err != nil {
+			return fmt.Errorf("error parsing allowed_directory_name_sans: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	name := data.Get("name").(string)
 
@@ -1322,6 +1673,7 @@ func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data
 		Province:                      getWithExplicitDefault(data, "province", oldEntry.Province).([]string),
 		StreetAddress:                 getWithExplicitDefault(data, "street_address", oldEntry.StreetAddress).([]string),
 		PostalCode:                    getWithExplicitDefault(data, "postal_code", oldEntry.PostalCode).([]string),
+		SubjectDNOrder:                getWithExplicitDefault(data, "subject_dn_order", oldEntry.SubjectDNOrder).([]string),
 		GenerateLease:                 new(bool),
 		NoStore:                       getWithExplicitDefault(data, "no_store", oldEntry.NoStore).(bool),
 		RequireCN:                     getWithExplicitDefault(data, "require_cn", oldEntry.RequireCN).(bool),
@@ -1334,6 +1686,20 @@ func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data
 		NotBefore:                     data.Get("not_before").(string),
 		NotAfter:                      getWithExplicitDefault(data, "not_after", oldEntry.NotAfter).(string),
 		Issuer:                        getWithExplicitDefault(data, "issuer_ref", oldEntry.Issuer).(string),
+		AllowedIssuers:                getWithExplicitDefault(data, "allowed_issuers", oldEntry.AllowedIssuers).([]string),
+		RANamespaces:                  getWithExplicitDefault(data, "ra_namespaces", oldEntry.RANamespaces).([]string),
+		RAExtensionOID:                getWithExplicitDefault(data, "ra_extension_oid", oldEntry.RAExtensionOID).(string),
+		RequireRACredential:           getWithExplicitDefault(data, "require_ra_credential", oldEntry.RequireRACredential).(bool),
+		IssuerSelection:               getWithExplicitDefault(data, "issuer_selection", oldEntry.IssuerSelection).(string),
+		TTLIssuerFraction:             getWithExplicitDefault(data, "ttl_issuer_fraction", oldEntry.TTLIssuerFraction).(float64),
+		SMIMECapabilities:             getWithExplicitDefault(data, "smime_capabilities", oldEntry.SMIMECapabilities).([]string),
+		IDNHandling:                   getWithExplicitDefault(data, "idn_handling", oldEntry.IDNHandling).(string),
+		AllowPublicKeySigning:         getWithExplicitDefault(data, "allow_public_key_signing", oldEntry.AllowPublicKeySigning).(bool),
+		EscrowPrivateKey:              getWithExplicitDefault(data, "escrow_private_key", oldEntry.EscrowPrivateKey).(bool),
+		EnforceCABFTTL:                getWithExplicitDefault(data, "enforce_cabf_ttl", oldEntry.EnforceCABFTTL).(bool),
+		CABFTTLBehavior:               getWithExplicitDefault(data, "cabf_ttl_behavior", oldEntry.CABFTTLBehavior).(string),
+		AllowedSANTypes:               getWithExplicitDefault(data, "allowed_san_types", oldEntry.AllowedSANTypes).([]string),
+		OcspOnlyRevocation:            getWithExplicitDefault(data, "ocsp_only_revocation", oldEntry.OcspOnlyRevocation).(bool),
 	}
 
 	allowedOtherSANsData, wasSet := data.GetOk("allowed_other_sans")
@@ -1353,6 +1719,17 @@ func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data
 		entry.AllowedOtherSANs = oldEntry.AllowedOtherSANs
 	}
 
+	allowedDirectoryNameSANsData, wasSet := data.GetOk("allowed_directory_name_sans")
+	if wasSet {
+		allowedDirectoryNameSANs := allowedDirectoryNameSANsData.([]string)
+		if err := validateAllowedDirectoryNameSANs(allowedDirectoryNameSANs); err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+		entry.AllowedDirectoryNameSANs = allowedDirectoryNameSANs
+	} else {
+		entry.AllowedDirectoryNameSANs = oldEntry.AllowedDirectoryNameSANs
+	}
+
 	allowWildcardCertificates, present := data.GetOk("allow_wildcard_certificates")
 	if !present {
 		allowWildcardCertificates = *oldEntry.AllowWildcardCertificates
@@ -1529,11 +1906,13 @@ type roleEntry struct {
 	Province                      []string      `json:"province"`
 	StreetAddress                 []string      `json:"street_address"`
 	PostalCode                    []string      `json:"postal_code"`
+	SubjectDNOrder                []string      `json:"subject_dn_order"`
 	GenerateLease                 *bool         `json:"generate_lease,omitempty"`
 	NoStore                       bool          `json:"no_store"`
 	RequireCN                     bool          `json:"require_cn"`
 	CNValidations                 []string      `json:"cn_validations"`
 	AllowedOtherSANs              []string      `json:"allowed_other_sans"`
+	AllowedDirectoryNameSANs      []string      `json:"allowed_directory_name_sans"`
 	AllowedSerialNumbers          []string      `json:"allowed_serial_numbers"`
 	AllowedUserIDs                []string      `json:"allowed_user_ids"`
 	AllowedURISANs                []string      `json:"allowed_uri_sans"`
@@ -1545,6 +1924,20 @@ type roleEntry struct {
 	NotBefore                     string        `json:"not_before"`
 	NotAfter                      string        `json:"not_after"`
 	Issuer                        string        `json:"issuer"`
+	AllowedIssuers                []string      `json:"allowed_issuers"`
+	RANamespaces                  []string      `json:"ra_namespaces"`
+	RAExtensionOID                string        `json:"ra_extension_oid"`
+	RequireRACredential           bool          `json:"require_ra_credential"`
+	IssuerSelection               string        `json:"issuer_selection"`
+	TTLIssuerFraction             float64       `json:"ttl_issuer_fraction"`
+	SMIMECapabilities             []string      `json:"smime_capabilities"`
+	IDNHandling                   string        `json:"idn_handling"`
+	AllowPublicKeySigning         bool          `json:"allow_public_key_signing"`
+	EscrowPrivateKey              bool          `json:"escrow_private_key"`
+	EnforceCABFTTL                bool          `json:"enforce_cabf_ttl"`
+	CABFTTLBehavior               string        `json:"cabf_ttl_behavior"`
+	AllowedSANTypes               []string      `json:"allowed_san_types"`
+	OcspOnlyRevocation            bool          `json:"ocsp_only_revocation"`
 	// Name is only set when the role has been stored, on the fly roles have a blank name
 	Name string `json:"-"`
 }
@@ -1585,8 +1978,10 @@ func (r *roleEntry) ToResponseData() map[string]interface{} {
 		"province":                           r.Province,
 		"street_address":                     r.StreetAddress,
 		"postal_code":                        r.PostalCode,
+		"subject_dn_order":                   r.SubjectDNOrder,
 		"no_store":                           r.NoStore,
 		"allowed_other_sans":                 r.AllowedOtherSANs,
+		"allowed_directory_name_sans":        r.AllowedDirectoryNameSANs,
 		"allowed_serial_numbers":             r.AllowedSerialNumbers,
 		"allowed_user_ids":                   r.AllowedUserIDs,
 		"allowed_uri_sans":                   r.AllowedURISANs,
@@ -1598,6 +1993,20 @@ func (r *roleEntry) ToResponseData() map[string]interface{} {
 		"not_before":                         r.NotBefore,
 		"not_after":                          r.NotAfter,
 		"issuer_ref":                         r.Issuer,
+		"allowed_issuers":                    r.AllowedIssuers,
+		"ra_namespaces":                      r.RANamespaces,
+		"ra_extension_oid":                   r.RAExtensionOID,
+		"require_ra_credential":              r.RequireRACredential,
+		"issuer_selection":                   r.IssuerSelection,
+		"ttl_issuer_fraction":                r.TTLIssuerFraction,
+		"smime_capabilities":                 r.SMIMECapabilities,
+		"idn_handling":                       r.IDNHandling,
+		"allow_public_key_signing":           r.AllowPublicKeySigning,
+		"escrow_private_key":                 r.EscrowPrivateKey,
+		"enforce_cabf_ttl":                   r.EnforceCABFTTL,
+		"cabf_ttl_behavior":                  r.CABFTTLBehavior,
+		"allowed_san_types":                  r.AllowedSANTypes,
+		"ocsp_only_revocation":               r.OcspOnlyRevocation,
 	}
 	if r.MaxPathLength != nil {
 		responseData["max_path_length"] = r.MaxPathLength
@@ -1664,6 +2073,18 @@ const pathRoleHelpDesc = `This path lets you manage the roles that can be create
 
 const policyIdentifiersParam = "policy_identifiers"
 
+// Values of a role's issuer_selection.
+const (
+	issuerSelectionRole    = "role"
+	issuerSelectionSubject = "subject"
+)
+
+// Values of a role's cabf_ttl_behavior.
+const (
+	cabfTTLBehaviorTruncate = "truncate"
+	cabfTTLBehaviorErr      = "err"
+)
+
 func getPolicyIdentifier(data *framework.FieldData, defaultIdentifiers *[]string) []string {
 	policyIdentifierEntry, ok := data.GetOk(policyIdentifiersParam)
 	if !ok {
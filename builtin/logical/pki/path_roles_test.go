@@ -826,6 +826,11 @@ func TestPki_RolePatch(t *testing.T) {
 			Before:  []string{"1.2.3.4;UTF8:magic"},
 			Patched: []string{"4.3.2.1;UTF8:cigam"},
 		},
+		{
+			Field:   "allowed_directory_name_sans",
+			Before:  []string{"CN=*,O=Example"},
+			Patched: []string{"CN=*,O=Other"},
+		},
 		{
 			Field:   "allowed_serial_numbers",
 			Before:  []string{"*"},
@@ -953,6 +958,66 @@ func TestPki_RolePatch(t *testing.T) {
 			Before:  "default",
 			Patched: "missing",
 		},
+		{
+			Field:   "allowed_issuers",
+			Before:  []string{"root-a"},
+			Patched: []string{"*"},
+		},
+		{
+			Field:   "subject_dn_order",
+			Before:  []string{"CN"},
+			Patched: []string{"C", "O", "CN"},
+		},
+		{
+			Field:   "ra_extension_oid",
+			Before:  "1.2.3.4",
+			Patched: "1.2.3.5",
+		},
+		{
+			Field:   "issuer_selection",
+			Before:  "role",
+			Patched: "subject",
+		},
+		{
+			Field:   "ttl_issuer_fraction",
+			Before:  0.5,
+			Patched: 0.25,
+		},
+		{
+			Field:   "idn_handling",
+			Before:  "a_label",
+			Patched: "a_label_single_script",
+		},
+		{
+			Field:   "allow_public_key_signing",
+			Before:  true,
+			Patched: false,
+		},
+		{
+			Field:   "escrow_private_key",
+			Before:  true,
+			Patched: false,
+		},
+		{
+			Field:   "enforce_cabf_ttl",
+			Before:  true,
+			Patched: false,
+		},
+		{
+			Field:   "cabf_ttl_behavior",
+			Before:  "err",
+			Patched: "truncate",
+		},
+		{
+			Field:   "allowed_san_types",
+			Before:  []string{"dns"},
+			Patched: []string{"dns", "ip"},
+		},
+		{
+			Field:   "ocsp_only_revocation",
+			Before:  true,
+			Patched: false,
+		},
 	}
 
 	b, storage := CreateBackendWithStorage(t)
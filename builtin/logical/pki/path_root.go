@@ -221,14 +221,14 @@ func (b *backend) pathCAGenerateRoot(ctx context.Context, req *logical.Request,
 		// subject in two or fewer bytes. We're also not here to validate
 		// our certificate's ASN.1 content, so let's just assume it holds
 		// and move on.
-		resp.AddWarning("This issuer certificate was generated without a Subject; this makes it likely that issuing leaf certs with this certificate will cause TLS validation libraries to reject this certificate.")
+		addCodedWarning(resp, newWarning(warningCodeEmptySubject, "common_name", "This issuer certificate was generated without a Subject; this makes it likely that issuing leaf certs with this certificate will cause TLS validation libraries to reject this certificate."))
 	}
 
 	if len(parsedBundle.Certificate.OCSPServer) == 0 && len(parsedBundle.Certificate.IssuingCertificateURL) == 0 && len(parsedBundle.Certificate.CRLDistributionPoints) == 0 {
 		// If the operator hasn't configured any of the URLs prior to
 		// generating this issuer, we should add a warning to the response,
 		// informing them they might want to do so prior to issuing leaves.
-		resp.AddWarning("This mount hasn't configured any authority information access (AIA) fields; this may make it harder for systems to find missing certificates in the chain or to validate revocation status of certificates. Consider updating /config/urls or the newly generated issuer with this information.")
+		addCodedWarning(resp, newWarning(warningCodeAIAMissing, "", "This mount hasn't configured any authority information access (AIA) fields; this may make it harder for systems to find missing certificates in the chain or to validate revocation status of certificates. Consider updating /config/urls or the newly generated issuer with this information."))
 	}
 
 	switch format {
@@ -312,6 +312,10 @@ func (b *backend) pathCAGenerateRoot(ctx context.Context, req *logical.Request,
 	}
 	b.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
 
+	if err := sc.writeCertIssuance(cb.SerialNumber, &certIssuanceEntry{IssuerID: myIssuer.ID}); err != nil {
+		return nil, fmt.Errorf("unable to store issuance record: %w", err)
+	}
+
 	// Check whether we need to update our default issuer configuration.
 	config, err := sc.getIssuersConfig()
 	if err != nil {
@@ -333,18 +337,20 @@ func (b *backend) pathCAGenerateRoot(ctx context.Context, req *logical.Request,
 
 	// Build a fresh CRL, using our _original_ storage, to prevent this from
 	// being done inside a transaction (with limited size).
-	warnings, err = b.crlBuilder.rebuild(crlSc, true)
+	crlWarnings, err := b.crlBuilder.rebuild(crlSc, true)
 	if err != nil {
 		resp.AddWarning(err.Error())
 	}
-	for index, warning := range warnings {
-		resp.AddWarning(fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning))
+	for index, warning := range crlWarnings {
+		addCodedWarning(resp, newWarning(warningCodeCRLRebuild, "", fmt.Sprintf("Warning %d during CRL rebuild: %v", index+1, warning)))
 	}
 
 	if parsedBundle.Certificate.MaxPathLen == 0 {
-		resp.AddWarning("Max path length of the generated certificate is zero. This certificate cannot be used to issue intermediate CA certificates.")
+		addCodedWarning(resp, newWarning(warningCodeZeroMaxPathLength, "max_path_length", "Max path length of the generated certificate is zero. This certificate cannot be used to issue intermediate CA certificates."))
 	}
 
+	resp = addStructuredWarnings(resp, data)
+
 	return resp, nil
 }
 
@@ -381,6 +387,7 @@ func (b *backend) pathIssuerSignIntermediate(ctx context.Context, req *logical.R
 		SignatureBits:             data.Get("signature_bits").(int),
 		UsePSS:                    data.Get("use_pss").(bool),
 		AllowedOtherSANs:          []string{"*"},
+		AllowedDirectoryNameSANs:  []string{"*"},
 		AllowedSerialNumbers:      []string{"*"},
 		AllowedURISANs:            []string{"*"},
 		NotBefore:                 data.Get("not_before").(string),
@@ -396,7 +403,7 @@ func (b *backend) pathIssuerSignIntermediate(ctx context.Context, req *logical.R
 
 	var caErr error
 	sc := b.makeStorageContext(ctx, req.Storage)
-	signingBundle, caErr := sc.fetchCAInfo(issuerName, IssuanceUsage)
+	signingBundle, issuerId, caErr := sc.fetchCAInfoWithIssuer(issuerName, IssuanceUsage)
 	if caErr != nil {
 		switch caErr.(type) {
 		case errutil.UserError:
@@ -459,7 +466,7 @@ func (b *backend) pathIssuerSignIntermediate(ctx context.Context, req *logical.R
 	}
 
 	if signingBundle.Certificate.NotAfter.Before(parsedBundle.Certificate.NotAfter) {
-		resp.AddWarning("The expiration time for the signed certificate is after the CA's expiration time. If the new certificate is not treated as a root, validation paths with the certificate past the issuing CA's expiration time will fail.")
+		addCodedWarning(resp, newWarning(warningCodeNotAfterBeyondCA, "ttl", "The expiration time for the signed certificate is after the CA's expiration time. If the new certificate is not treated as a root, validation paths with the certificate past the issuing CA's expiration time will fail."))
 	}
 
 	if len(parsedBundle.Certificate.RawSubject) <= 2 {
@@ -472,14 +479,14 @@ func (b *backend) pathIssuerSignIntermediate(ctx context.Context, req *logical.R
 		// subject in two or fewer bytes. We're also not here to validate
 		// our certificate's ASN.1 content, so let's just assume it holds
 		// and move on.
-		resp.AddWarning("This issuer certificate was generated without a Subject; this makes it likely that issuing leaf certs with this certificate will cause TLS validation libraries to reject this certificate.")
+		addCodedWarning(resp, newWarning(warningCodeEmptySubject, "common_name", "This issuer certificate was generated without a Subject; this makes it likely that issuing leaf certs with this certificate will cause TLS validation libraries to reject this certificate."))
 	}
 
 	if len(parsedBundle.Certificate.OCSPServer) == 0 && len(parsedBundle.Certificate.IssuingCertificateURL) == 0 && len(parsedBundle.Certificate.CRLDistributionPoints) == 0 {
 		// If the operator hasn't configured any of the URLs prior to
 		// generating this issuer, we should add a warning to the response,
 		// informing them they might want to do so prior to issuing leaves.
-		resp.AddWarning("This mount hasn't configured any authority information access (AIA) fields; this may make it harder for systems to find missing certificates in the chain or to validate revocation status of certificates. Consider updating /config/urls or the newly generated issuer with this information.")
+		addCodedWarning(resp, newWarning(warningCodeAIAMissing, "", "This mount hasn't configured any authority information access (AIA) fields; this may make it harder for systems to find missing certificates in the chain or to validate revocation status of certificates. Consider updating /config/urls or the newly generated issuer with this information."))
 	}
 
 	caChain := append([]string{cb.Certificate}, cb.CAChain...)
@@ -521,11 +528,16 @@ func (b *backend) pathIssuerSignIntermediate(ctx context.Context, req *logical.R
 	}
 	b.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
 
+	if err := sc.writeCertIssuance(cb.SerialNumber, &certIssuanceEntry{IssuerID: issuerId}); err != nil {
+		return nil, fmt.Errorf("unable to store issuance record: %w", err)
+	}
+
 	if parsedBundle.Certificate.MaxPathLen == 0 {
-		resp.AddWarning("Max path length of the signed certificate is zero. This certificate cannot be used to issue intermediate CA certificates.")
+		addCodedWarning(resp, newWarning(warningCodeZeroMaxPathLength, "max_path_length", "Max path length of the signed certificate is zero. This certificate cannot be used to issue intermediate CA certificates."))
 	}
 
 	resp = addWarnings(resp, warnings)
+	resp = addStructuredWarnings(resp, data)
 
 	return resp, nil
 }
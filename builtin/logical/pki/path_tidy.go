@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -75,6 +76,7 @@ type tidyConfig struct {
 	// AutoTidy config
 	Enabled  bool          `json:"enabled"`
 	Interval time.Duration `json:"interval_duration"`
+	Schedule string        `json:"tidy_schedule"`
 
 	// Tidy Operations
 	CertStore      bool `json:"tidy_cert_store"`
@@ -104,6 +106,22 @@ func (tc *tidyConfig) IsAnyTidyEnabled() bool {
 	return tc.CertStore || tc.RevokedCerts || tc.InvalidCerts || tc.IssuerAssocs || tc.ExpiredIssuers || tc.BackupBundle || tc.TidyAcme
 }
 
+// nextAutoTidy returns when the next auto-tidy operation should run, given
+// when the last one ran. When a cron schedule is configured, it takes
+// precedence over the interval.
+func (tc *tidyConfig) nextAutoTidy(lastTidy time.Time) (time.Time, error) {
+	if len(tc.Schedule) == 0 {
+		return lastTidy.Add(tc.Interval), nil
+	}
+
+	schedule, err := parseCronSchedule(tc.Schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse tidy_schedule: %w", err)
+	}
+
+	return schedule.Next(lastTidy), nil
+}
+
 func (tc *tidyConfig) AnyTidyConfig() string {
 	return "tidy_cert_store / tidy_revoked_certs / tidy_invalid_certs / tidy_revoked_cert_issuer_associations / tidy_expired_issuers / tidy_move_legacy_ca_bundle / tidy_acme"
 }
@@ -256,6 +274,11 @@ func pathTidyCancel(b *backend) *framework.Path {
 								Description: `Time the last auto-tidy operation finished`,
 								Required:    true,
 							},
+							"next_tidy_run": {
+								Type:        framework.TypeString,
+								Description: `Time the next auto-tidy operation is scheduled to start, if auto-tidy is enabled`,
+								Required:    false,
+							},
 							"message": {
 								Type:        framework.TypeString,
 								Description: `Message of the operation`,
@@ -426,6 +449,11 @@ func pathTidyStatus(b *backend) *framework.Path {
 								Description: `Time the last auto-tidy operation finished`,
 								Required:    true,
 							},
+							"next_tidy_run": {
+								Type:        framework.TypeString,
+								Description: `Time the next auto-tidy operation is scheduled to start, if auto-tidy is enabled`,
+								Required:    false,
+							},
 							"message": {
 								Type:        framework.TypeString,
 								Description: `Message of the operation`,
@@ -510,6 +538,10 @@ func pathConfigAutoTidy(b *backend) *framework.Path {
 				Description: `Interval at which to run an auto-tidy operation. This is the time between tidy invocations (after one finishes to the start of the next). Running a manual tidy will reset this duration.`,
 				Default:     int(defaultTidyConfig.Interval / time.Second), // TypeDurationSecond currently requires the default to be an int.
 			},
+			"tidy_schedule": {
+				Type:        framework.TypeString,
+				Description: `Cron-style schedule (minute, hour, day of month, month and day of week; evaluated in UTC) at which to run an auto-tidy operation, such as "0 3 * * *". When set, this takes precedence over interval_duration; set to an empty string to use interval_duration instead.`,
+			},
 			"maintain_stored_certificate_counts": {
 				Type: framework.TypeBool,
 				Description: `This configures whether stored certificates
@@ -546,6 +578,11 @@ available on the tidy-status endpoint.`,
 								Description: `Specifies the duration between automatic tidy operation`,
 								Required:    true,
 							},
+							"tidy_schedule": {
+								Type:        framework.TypeString,
+								Description: `Specifies the cron-style schedule of automatic tidy operations, if any`,
+								Required:    true,
+							},
 							"tidy_cert_store": {
 								Type:        framework.TypeBool,
 								Description: `Specifies whether to tidy up the certificate store`,
@@ -642,6 +679,11 @@ available on the tidy-status endpoint.`,
 								Description: `Specifies the duration between automatic tidy operation`,
 								Required:    true,
 							},
+							"tidy_schedule": {
+								Type:        framework.TypeString,
+								Description: `Specifies the cron-style schedule of automatic tidy operations, if any`,
+								Required:    true,
+							},
 							"tidy_cert_store": {
 								Type:        framework.TypeBool,
 								Description: `Specifies whether to tidy up the certificate store`,
@@ -1033,11 +1075,29 @@ func (b *backend) doTidyCertStore(ctx context.Context, req *logical.Request, log
 			if err := req.Storage.Delete(ctx, "certs/"+serial); err != nil {
 				return false, fmt.Errorf("error deleting serial %q from storage: %w", serial, err)
 			}
+			if err := req.Storage.Delete(ctx, escrowedKeyPrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting escrowed key of serial %q: %w", serial, err)
+			}
+			if err := req.Storage.Delete(ctx, ocspOnlyRevocationPrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting OCSP-only revocation marker of serial %q: %w", serial, err)
+			}
+			if err := req.Storage.Delete(ctx, certIssuancePrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting issuance record of serial %q: %w", serial, err)
+			}
 			b.tidyStatusIncCertStoreCount()
 		} else if revokedResp != nil && time.Since(cert.NotAfter) > revokedSafetyBuffer {
 			if err := req.Storage.Delete(ctx, "certs/"+serial); err != nil {
 				return false, fmt.Errorf("error deleting serial %q from store when tidying revoked: %w", serial, err)
 			}
+			if err := req.Storage.Delete(ctx, escrowedKeyPrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting escrowed key of serial %q: %w", serial, err)
+			}
+			if err := req.Storage.Delete(ctx, ocspOnlyRevocationPrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting OCSP-only revocation marker of serial %q: %w", serial, err)
+			}
+			if err := req.Storage.Delete(ctx, certIssuancePrefix+serial); err != nil {
+				return false, fmt.Errorf("error deleting issuance record of serial %q: %w", serial, err)
+			}
 			// Only tidy revoked certs if requested.
 			if config.RevokedCerts {
 				if err := req.Storage.Delete(ctx, "revoked/"+serial); err != nil {
@@ -1208,6 +1268,15 @@ func (b *backend) doTidyRevocationStore(ctx context.Context, req *logical.Reques
 				if err := req.Storage.Delete(ctx, "certs/"+serial); err != nil {
 					return false, fmt.Errorf("error deleting serial %q from store when tidying revoked: %w", serial, err)
 				}
+				if err := req.Storage.Delete(ctx, escrowedKeyPrefix+serial); err != nil {
+					return false, fmt.Errorf("error deleting escrowed key of serial %q: %w", serial, err)
+				}
+				if err := req.Storage.Delete(ctx, ocspOnlyRevocationPrefix+serial); err != nil {
+					return false, fmt.Errorf("error deleting OCSP-only revocation marker of serial %q: %w", serial, err)
+				}
+				if err := req.Storage.Delete(ctx, certIssuancePrefix+serial); err != nil {
+					return false, fmt.Errorf("error deleting issuance record of serial %q: %w", serial, err)
+				}
 				rebuildCRL = true
 				storeCert = false
 				b.tidyStatusIncRevokedCertCount()
@@ -1580,7 +1649,13 @@ func (b *backend) pathTidyCancelWrite(ctx context.Context, req *logical.Request,
 	return b.pathTidyStatusRead(ctx, req, d)
 }
 
-func (b *backend) pathTidyStatusRead(_ context.Context, _ *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+func (b *backend) pathTidyStatusRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	sc := b.makeStorageContext(ctx, req.Storage)
+	autoTidyConfig, err := sc.getAutoTidyConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	b.tidyStatusLock.RLock()
 	defer b.tidyStatusLock.RUnlock()
 
@@ -1614,11 +1689,22 @@ func (b *backend) pathTidyStatusRead(_ context.Context, _ *logical.Request, _ *f
 			"acme_account_revoked_count":            nil,
 			"acme_orders_deleted_count":             nil,
 			"acme_account_safety_buffer":            nil,
+			"next_tidy_run":                         nil,
 		},
 	}
 
 	resp.Data["internal_backend_uuid"] = b.backendUUID
 
+	if autoTidyConfig.Enabled && (len(autoTidyConfig.Schedule) > 0 || autoTidyConfig.Interval > 0) {
+		nextRun, err := autoTidyConfig.nextAutoTidy(b.lastTidy)
+		if err != nil {
+			return nil, err
+		}
+		if !nextRun.IsZero() {
+			resp.Data["next_tidy_run"] = nextRun.Format(time.RFC3339)
+		}
+	}
+
 	if b.certCountEnabled.Load() {
 		resp.Data["current_cert_store_count"] = b.certCount.Load()
 		resp.Data["current_revoked_cert_count"] = b.revokedCertCount.Load()
@@ -1712,6 +1798,19 @@ func (b *backend) pathConfigAutoTidyWrite(ctx context.Context, req *logical.Requ
 		}
 	}
 
+	if scheduleRaw, ok := d.GetOk("tidy_schedule"); ok {
+		config.Schedule = strings.TrimSpace(scheduleRaw.(string))
+		if len(config.Schedule) > 0 {
+			schedule, err := parseCronSchedule(config.Schedule)
+			if err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("given tidy_schedule is not a valid cron expression: %v", err)), nil
+			}
+			if schedule.Next(time.Now()).IsZero() {
+				return logical.ErrorResponse(fmt.Sprintf("given tidy_schedule never matches; got: %v", config.Schedule)), nil
+			}
+		}
+	}
+
 	if certStoreRaw, ok := d.GetOk("tidy_cert_store"); ok {
 		config.CertStore = certStoreRaw.(bool)
 	}
@@ -2002,6 +2101,7 @@ The result includes the following fields:
 * 'acme_account_deleted_count': the number of revoked acme accounts deleted during the operation
 * 'acme_account_revoked_count': the number of acme accounts revoked during the operation
 * 'acme_orders_deleted_count': the number of acme orders deleted during the operation
+* 'next_tidy_run': the time the next auto-tidy operation is scheduled to start, if enabled
 `
 
 const pathConfigAutoTidySyn = `
@@ -2010,9 +2110,10 @@ Modifies the current configuration for automatic tidy execution.
 
 const pathConfigAutoTidyDesc = `
 This endpoint accepts parameters to a tidy operation (see /tidy) that
-will be used for automatic tidy execution. This takes two extra parameters,
-enabled (to enable or disable auto-tidy) and interval_duration (which
-controls the frequency of auto-tidy execution).
+will be used for automatic tidy execution. This takes three extra parameters,
+enabled (to enable or disable auto-tidy), interval_duration (which
+controls the frequency of auto-tidy execution), and tidy_schedule (a
+cron-style schedule which, when set, is used instead of interval_duration).
 
 Once enabled, a tidy operation will be kicked off automatically, as if it
 were executed with the posted configuration.
@@ -2027,6 +2128,7 @@ func getTidyConfigData(config tidyConfig) map[string]interface{} {
 		// This map is in the same order as tidyConfig to ensure that all fields are accounted for
 		"enabled":                                  config.Enabled,
 		"interval_duration":                        int(config.Interval / time.Second),
+		"tidy_schedule":                            config.Schedule,
 		"tidy_cert_store":                          config.CertStore,
 		"tidy_revoked_certs":                       config.RevokedCerts,
 		"tidy_invalid_certs":                       config.InvalidCerts,
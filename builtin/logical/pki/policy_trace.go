@@ -0,0 +1,105 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	explainParam         = "explain"
+	policyDecisionsField = "policy_decisions"
+)
+
+// policyDecision is the outcome of a single role policy check performed
+// while validating an issuance request.
+type policyDecision struct {
+	Check     string `json:"check"`
+	RoleField string `json:"role_field"`
+	Value     string `json:"value"`
+	Passed    bool   `json:"passed"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// policyTrace collects the policy decisions made while validating an
+// issuance request with explain set. Recording into a nil trace is a no-op,
+// so checks record their outcome unconditionally.
+type policyTrace struct {
+	decisions []policyDecision
+}
+
+func (t *policyTrace) record(check string, roleField string, value string, passed bool) {
+	t.recordWithDetail(check, roleField, value, passed, "")
+}
+
+func (t *policyTrace) recordWithDetail(check string, roleField string, value string, passed bool, detail string) {
+	if t == nil {
+		return
+	}
+
+	t.decisions = append(t.decisions, policyDecision{
+		Check:     check,
+		RoleField: roleField,
+		Value:     value,
+		Passed:    passed,
+		Detail:    detail,
+	})
+}
+
+// recordNames records the validation of names in the order they were
+// checked: names are validated until the first one rejected, badName.
+func (t *policyTrace) recordNames(check string, roleField string, names []string, badName string) {
+	for _, name := range names {
+		t.record(check, roleField, name, name != badName)
+		if name == badName {
+			return
+		}
+	}
+}
+
+func (t *policyTrace) toResponseData() []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(t.decisions))
+	for _, decision := range t.decisions {
+		entry := map[string]interface{}{
+			"check":      decision.Check,
+			"role_field": decision.RoleField,
+			"value":      decision.Value,
+			"passed":     decision.Passed,
+		}
+		if len(decision.Detail) > 0 {
+			entry["detail"] = decision.Detail
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// policyRejectionResponse returns a rejected issuance request's error along
+// with its policy decisions. Error responses lose their data on the way to
+// the client, so this is a raw 400 response mirroring the usual error body.
+func policyRejectionResponse(message string, trace *policyTrace) (*logical.Response, error) {
+	decisions := trace.decisions
+	if decisions == nil {
+		decisions = []policyDecision{}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"errors":             []string{message},
+		policyDecisionsField: decisions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPContentType: "application/json",
+			logical.HTTPRawBody:     body,
+			logical.HTTPStatusCode:  http.StatusBadRequest,
+		},
+	}, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// Registration authority (RA) credentials are certificates carrying an
+// extension, under an operator-chosen OID, which lists the DNS namespaces
+// their holder may request certificates for. The extension's value is a
+// SEQUENCE OF UTF8String. As the extension could equally be copied from a CSR
+// by sign-verbatim or sign-intermediate, only certificates the mount recorded
+// issuing under a role with ra_namespaces are accepted as credentials.
+
+// validateRARoleConfig checks the RA-related fields of a role.
+func validateRARoleConfig(entry *roleEntry) error {
+	if len(entry.RANamespaces) == 0 && !entry.RequireRACredential {
+		return nil
+	}
+
+	if len(entry.RAExtensionOID) == 0 {
+		return fmt.Errorf("ra_extension_oid must be set when ra_namespaces or require_ra_credential are used")
+	}
+	if len(entry.RANamespaces) > 0 && entry.NoStore {
+		return fmt.Errorf("ra_namespaces cannot be used with no_store, as RA credentials must be stored to be verified")
+	}
+	if _, err := certutil.StringToOid(entry.RAExtensionOID); err != nil {
+		return fmt.Errorf("%q could not be parsed as a valid oid for ra_extension_oid", entry.RAExtensionOID)
+	}
+
+	for _, namespace := range entry.RANamespaces {
+		if len(namespace) == 0 || strings.ContainsAny(namespace, "*@ ") || strings.HasPrefix(namespace, ".") {
+			return fmt.Errorf("invalid RA namespace %q: must be a DNS domain name", namespace)
+		}
+	}
+
+	return nil
+}
+
+// buildRANamespacesExtension encodes the role's RA namespaces into the
+// extension added to RA credentials it issues.
+func buildRANamespacesExtension(role *roleEntry) (*pkix.Extension, error) {
+	if len(role.RANamespaces) == 0 {
+		return nil, nil
+	}
+
+	oid, err := certutil.StringToOid(role.RAExtensionOID)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]asn1.RawValue, 0, len(role.RANamespaces))
+	for _, namespace := range role.RANamespaces {
+		namespaces = append(namespaces, asn1.RawValue{
+			Class: asn1.ClassUniversal,
+			Tag:   asn1.TagUTF8String,
+			Bytes: []byte(strings.ToLower(namespace)),
+		})
+	}
+
+	value, err := asn1.Marshal(namespaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode RA namespaces: %w", err)
+	}
+
+	return &pkix.Extension{Id: oid, Value: value}, nil
+}
+
+// fetchRANamespaces validates the RA credential presented by the client over
+// mTLS and returns the namespaces it authorizes. The credential must have been
+// issued as such by a role of this mount, match the certificate stored under
+// its serial, be currently valid and not be revoked.
+func fetchRANamespaces(sc *storageContext, req *logical.Request, role *roleEntry) ([]string, error) {
+	if req.Connection == nil || req.Connection.ConnState == nil || len(req.Connection.ConnState.PeerCertificates) == 0 {
+		return nil, errutil.UserError{Err: "this role requires an RA credential to be presented as a TLS client certificate"}
+	}
+	raCert := req.Connection.ConnState.PeerCertificates[0]
+
+	now := time.Now()
+	if now.Before(raCert.NotBefore) || now.After(raCert.NotAfter) {
+		return nil, errutil.UserError{Err: "the presented RA credential is not currently valid"}
+	}
+
+	// The presented certificate must be the one this mount stored under its
+	// serial; a foreign certificate reusing that serial does not match.
+	serial := serialFromCert(raCert)
+	certEntry, err := fetchCertBySerial(sc, "certs/", serial)
+	if err != nil {
+		return nil, err
+	}
+	if certEntry == nil || !bytes.Equal(certEntry.Value, raCert.Raw) {
+		return nil, errutil.UserError{Err: "the presented RA credential was not issued by this mount"}
+	}
+
+	issuance, err := sc.fetchCertIssuance(serial)
+	if err != nil {
+		return nil, err
+	}
+	if issuance == nil || !issuance.RACredential {
+		return nil, errutil.UserError{Err: "the presented certificate was not issued as an RA credential by this mount"}
+	}
+
+	revInfo, err := sc.fetchRevocationInfo(serial)
+	if err != nil {
+		return nil, err
+	}
+	if revInfo != nil {
+		return nil, errutil.UserError{Err: "the presented RA credential has been revoked"}
+	}
+
+	oid, err := certutil.StringToOid(role.RAExtensionOID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ext := range raCert.Extensions {
+		if !ext.Id.Equal(oid) {
+			continue
+		}
+
+		var namespaces []string
+		rest, err := asn1.Unmarshal(ext.Value, &namespaces)
+		if err != nil || len(rest) > 0 {
+			return nil, errutil.UserError{Err: "the presented RA credential has a malformed namespace extension"}
+		}
+		return namespaces, nil
+	}
+
+	return nil, errutil.UserError{Err: fmt.Sprintf("the presented certificate is not an RA credential: missing extension %v", role.RAExtensionOID)}
+}
+
+// checkRANamespaces ensures the common name and DNS SANs of the certificate
+// being issued fall within one of the RA's namespaces. Namespaces only scope
+// DNS names, so any other type of SAN is refused.
+func checkRANamespaces(cert *x509.Certificate, namespaces []string) error {
+	nonDNS, err := hasNonDNSSubjectAltName(cert)
+	if err != nil {
+		return err
+	}
+	if nonDNS {
+		return errutil.UserError{Err: "certificates scoped by an RA credential may only carry DNS Subject Alternative Names"}
+	}
+
+	names := append([]string{}, cert.DNSNames...)
+	if len(cert.Subject.CommonName) > 0 {
+		names = append(names, cert.Subject.CommonName)
+	}
+
+	for _, name := range names {
+		if !nameInRANamespaces(name, namespaces) {
+			return errutil.UserError{Err: fmt.Sprintf("name %q is not within the namespaces authorized by the presented RA credential", name)}
+		}
+	}
+
+	return nil
+}
+
+// hasNonDNSSubjectAltName reports whether the Subject Alternative Name
+// extension of cert holds any GeneralName other than a dNSName, including
+// types such as otherName which crypto/x509 doesn't parse.
+func hasNonDNSSubjectAltName(cert *x509.Certificate) (bool, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(certutil.ExtensionSubjectAltNameOID) {
+			continue
+		}
+
+		var seq asn1.RawValue
+		rest, err := asn1.Unmarshal(ext.Value, &seq)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse Subject Alternative Names: %w", err)
+		}
+		if len(rest) > 0 {
+			return false, fmt.Errorf("failed to parse Subject Alternative Names: trailing data")
+		}
+
+		for rest = seq.Bytes; len(rest) > 0; {
+			var name asn1.RawValue
+			rest, err = asn1.Unmarshal(rest, &name)
+			if err != nil {
+				return false, fmt.Errorf("failed to parse Subject Alternative Names: %w", err)
+			}
+			// dNSName is [2] IA5String.
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 2 {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func nameInRANamespaces(name string, namespaces []string) bool {
+	name = strings.ToLower(strings.TrimPrefix(name, "*."))
+	for _, namespace := range namespaces {
+		namespace = strings.ToLower(namespace)
+		if name == namespace || strings.HasSuffix(name, "."+namespace) {
+			return true
+		}
+	}
+	return false
+}
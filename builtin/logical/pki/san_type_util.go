@@ -0,0 +1,86 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
+)
+
+// Values of a role's allowed_san_types, naming GeneralName types.
+const (
+	sanTypeDNS           = "dns"
+	sanTypeIP            = "ip"
+	sanTypeEmail         = "email"
+	sanTypeURI           = "uri"
+	sanTypeUPN           = "upn"
+	sanTypeRegisteredID  = "registeredid"
+	sanTypeDirectoryName = "directoryname"
+	sanTypeOtherName     = "othername"
+)
+
+var allSANTypes = []string{
+	sanTypeDNS,
+	sanTypeIP,
+	sanTypeEmail,
+	sanTypeURI,
+	sanTypeUPN,
+	sanTypeRegisteredID,
+	sanTypeDirectoryName,
+	sanTypeOtherName,
+}
+
+// upnOID identifies the Microsoft User Principal Name otherName, which
+// allowed_san_types distinguishes from other otherNames.
+const upnOID = "1.3.6.1.4.1.311.20.2.3"
+
+func validateAllowedSANTypes(entry *roleEntry) error {
+	normalized := make([]string, 0, len(entry.AllowedSANTypes))
+	for _, sanType := range entry.AllowedSANTypes {
+		sanType = strings.ToLower(strings.TrimSpace(sanType))
+		if !strutil.StrListContains(allSANTypes, sanType) {
+			return fmt.Errorf("invalid allowed_san_types value %q: must be one of %s", sanType, strings.Join(allSANTypes, ", "))
+		}
+		normalized = append(normalized, sanType)
+	}
+	entry.AllowedSANTypes = strutil.RemoveDuplicatesStable(normalized, false)
+	return nil
+}
+
+// allowsSANType reports whether the role permits SANs of the given type;
+// roles without allowed_san_types permit all of them.
+func allowsSANType(role *roleEntry, sanType string) bool {
+	return len(role.AllowedSANTypes) == 0 || strutil.StrListContains(role.AllowedSANTypes, sanType)
+}
+
+// checkSANType rejects requested SANs whose type the role doesn't permit.
+func checkSANType(data *inputBundle, sanType string, values []string) error {
+	if len(values) == 0 || len(data.role.AllowedSANTypes) == 0 {
+		return nil
+	}
+
+	allowed := allowsSANType(data.role, sanType)
+	for _, value := range values {
+		data.trace.record(sanType+"_san", "allowed_san_types", value, allowed)
+		if !allowed {
+			break
+		}
+	}
+	if !allowed {
+		return errutil.UserError{Err: fmt.Sprintf(
+			"%s subject alternative names are not allowed by this role's allowed_san_types, but %s was requested", sanType, values[0])}
+	}
+	return nil
+}
+
+// otherSANType returns the allowed_san_types type of an otherName.
+func otherSANType(oid string) string {
+	if oid == upnOID {
+		return sanTypeUPN
+	}
+	return sanTypeOtherName
+}
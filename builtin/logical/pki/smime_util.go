@@ -0,0 +1,122 @@
+// Copyright (c) 2024 OpenBao a Series of LF Projects, LLC
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+)
+
+// oidExtensionSMIMECapabilities is the SMIMECapabilities extension from
+// RFC 4262.
+var oidExtensionSMIMECapabilities = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 15}
+
+// smimeCapability is a single entry of the SMIMECapabilities extension:
+//
+//	SMIMECapability ::= SEQUENCE {
+//	    capabilityID OBJECT IDENTIFIER,
+//	    parameters ANY DEFINED BY capabilityID OPTIONAL }
+type smimeCapability struct {
+	CapabilityID asn1.ObjectIdentifier
+	Parameters   asn1.RawValue `asn1:"optional"`
+}
+
+// parseSMIMECapability parses a role's capability entry, which is either a
+// bare OID or "<oid>;<type>:<value>", with type INTEGER (a decimal integer,
+// e.g. an RC2 key size) or DER (the hex-encoded parameters).
+func parseSMIMECapability(value string) (smimeCapability, error) {
+	oidStr, params, hasParams := strings.Cut(strings.TrimSpace(value), ";")
+
+	oid, err := certutil.StringToOid(oidStr)
+	if err != nil {
+		return smimeCapability{}, fmt.Errorf("%q could not be parsed as a valid oid for an S/MIME capability", oidStr)
+	}
+
+	capability := smimeCapability{CapabilityID: oid}
+	if !hasParams {
+		return capability, nil
+	}
+
+	paramType, paramValue, ok := strings.Cut(params, ":")
+	if !ok {
+		return smimeCapability{}, fmt.Errorf("invalid parameters %q for S/MIME capability %v: expected <type>:<value>", params, oidStr)
+	}
+
+	var der []byte
+	switch strings.ToUpper(paramType) {
+	case "INTEGER":
+		number, ok := new(big.Int).SetString(paramValue, 10)
+		if !ok {
+			return smimeCapability{}, fmt.Errorf("invalid INTEGER parameter %q for S/MIME capability %v", paramValue, oidStr)
+		}
+		der, err = asn1.Marshal(number)
+		if err != nil {
+			return smimeCapability{}, err
+		}
+	case "DER":
+		der, err = hex.DecodeString(paramValue)
+		if err != nil {
+			return smimeCapability{}, fmt.Errorf("invalid DER parameter for S/MIME capability %v: %w", oidStr, err)
+		}
+
+		var check asn1.RawValue
+		if rest, err := asn1.Unmarshal(der, &check); err != nil || len(rest) > 0 {
+			return smimeCapability{}, fmt.Errorf("DER parameter for S/MIME capability %v is not a single ASN.1 value", oidStr)
+		}
+	default:
+		return smimeCapability{}, fmt.Errorf("unsupported parameter type %q for S/MIME capability %v: must be INTEGER or DER", paramType, oidStr)
+	}
+
+	capability.Parameters = asn1.RawValue{FullBytes: der}
+	return capability, nil
+}
+
+// validateSMIMERoleConfig checks the S/MIME fields of a role.
+func validateSMIMERoleConfig(entry *roleEntry) error {
+	if len(entry.SMIMECapabilities) == 0 {
+		return nil
+	}
+
+	if !entry.EmailProtectionFlag {
+		return fmt.Errorf("smime_capabilities requires email_protection_flag to be set")
+	}
+
+	for _, value := range entry.SMIMECapabilities {
+		if _, err := parseSMIMECapability(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildSMIMECapabilitiesExtension encodes the role's S/MIME capabilities, in
+// order of preference, into a non-critical extension.
+func buildSMIMECapabilitiesExtension(role *roleEntry) (*pkix.Extension, error) {
+	if len(role.SMIMECapabilities) == 0 {
+		return nil, nil
+	}
+
+	capabilities := make([]smimeCapability, 0, len(role.SMIMECapabilities))
+	for _, value := range role.SMIMECapabilities {
+		capability, err := parseSMIMECapability(value)
+		if err != nil {
+			return nil, err
+		}
+		capabilities = append(capabilities, capability)
+	}
+
+	value, err := asn1.Marshal(capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode S/MIME capabilities: %w", err)
+	}
+
+	return &pkix.Extension{Id: oidExtensionSMIMECapabilities, Critical: false, Value: value}, nil
+}
@@ -27,6 +27,7 @@ const (
 	keyPrefix             = "config/key/"
 	issuerPrefix          = "config/issuer/"
 	storageLocalCRLConfig = "crls/config"
+	combinedCRLPath       = "crls/combined"
 
 	legacyMigrationBundleLogKey = "config/legacyMigrationBundleLog"
 	legacyCertBundlePath        = "config/ca_bundle"
@@ -37,6 +38,19 @@ const (
 
 	autoTidyConfigPath = "config/auto-tidy"
 	clusterConfigPath  = "config/cluster"
+	issuanceConfigPath = "config/issuance"
+
+	// Private keys escrowed by roles with escrow_private_key, keyed by the
+	// normalized serial number of their certificate.
+	escrowedKeyPrefix = "escrow/"
+
+	// Markers of certificates issued by roles with ocsp_only_revocation,
+	// keyed by the normalized serial number of the certificate.
+	ocspOnlyRevocationPrefix = "ocsp-only/"
+
+	// Issuer and role which issued a stored certificate, keyed by the
+	// normalized serial number of the certificate.
+	certIssuancePrefix = "cert-issuance/"
 
 	// Used as a quick sanity check for a reference id lookups...
 	uuidLength = 36
@@ -167,6 +181,10 @@ type issuerEntry struct {
 	AIAURIs              *aiaConfigEntry           `json:"aia_uris,omitempty"`
 	LastModified         time.Time                 `json:"last_modified"`
 	Version              uint                      `json:"version"`
+
+	// ExpiryWarningThreshold is how long before the issuer's expiry fetches
+	// of it as the mount's CA start warning about it; zero disables this.
+	ExpiryWarningThreshold time.Duration `json:"expiry_warning_threshold,omitempty"`
 }
 
 type internalCRLConfigEntry struct {
@@ -189,6 +207,9 @@ type issuerConfigEntry struct {
 	fetchedDefault             issuerID `json:"-"`
 	DefaultIssuerId            issuerID `json:"default"`
 	DefaultFollowsLatestIssuer bool     `json:"default_follows_latest_issuer"`
+	ExpiredCAWarning           bool     `json:"expired_ca_warning"`
+	ExpiredCAGracePeriod       string   `json:"expired_ca_grace_period"`
+	FallbackIssuerId           issuerID `json:"fallback_issuer"`
 }
 
 type clusterConfigEntry struct {
@@ -196,6 +217,73 @@ type clusterConfigEntry struct {
 	AIAPath string `json:"aia_path"`
 }
 
+// issuanceConfigEntry holds mount-wide limits applied when validating
+// issuance requests against a role; zero values disable a limit, except for
+// CABFMaxValidity where zero selects defaultCABFMaxValidity. It also bounds
+// the serial numbers of a single certs/batch fetch, where zero selects
+// defaultMaxBatchFetchSerials, and the page size of certificate listings.
+type issuanceConfigEntry struct {
+	MaxValidatedSANs     int           `json:"max_validated_sans"`
+	CABFMaxValidity      time.Duration `json:"cabf_max_validity"`
+	MaxBatchFetchSerials int           `json:"max_batch_fetch_serials"`
+	MaxListPageSize      int           `json:"max_list_page_size"`
+}
+
+// defaultMaxBatchFetchSerials bounds the serial numbers of a certs/batch
+// fetch when the mount doesn't configure a limit.
+const defaultMaxBatchFetchSerials = 256
+
+// maxBatchFetchSerials returns the number of serial numbers accepted by a
+// single certs/batch fetch.
+func (c *issuanceConfigEntry) maxBatchFetchSerials() int {
+	if c.MaxBatchFetchSerials > 0 {
+		return c.MaxBatchFetchSerials
+	}
+	return defaultMaxBatchFetchSerials
+}
+
+// listPageLimit clamps the limit of a certificate listing, where -1 requests
+// every entry, to the mount's max_list_page_size.
+func (c *issuanceConfigEntry) listPageLimit(limit int) int {
+	if c.MaxListPageSize > 0 && (limit <= 0 || limit > c.MaxListPageSize) {
+		return c.MaxListPageSize
+	}
+	return limit
+}
+
+// defaultCABFMaxValidity is the maximum validity of publicly-trusted TLS
+// certificates under the CA/Browser Forum Baseline Requirements.
+const defaultCABFMaxValidity = 398 * 24 * time.Hour
+
+// cabfMaxValidity returns the validity enforced on roles with
+// enforce_cabf_ttl.
+func (c *issuanceConfigEntry) cabfMaxValidity() time.Duration {
+	if c.CABFMaxValidity > 0 {
+		return c.CABFMaxValidity
+	}
+	return defaultCABFMaxValidity
+}
+
+// escrowedKeyEntry holds the private key generated for a certificate issued
+// under a role escrowing its keys.
+type escrowedKeyEntry struct {
+	SerialNumber   string                  `json:"serial_number"`
+	PrivateKey     string                  `json:"private_key"`
+	PrivateKeyType certutil.PrivateKeyType `json:"private_key_type"`
+	NotAfter       time.Time               `json:"not_after"`
+}
+
+// certIssuanceEntry records which issuer signed a stored certificate and
+// under which role. Certificates issued before this was tracked have none.
+type certIssuanceEntry struct {
+	IssuerID issuerID `json:"issuer_id"`
+	Role     string   `json:"role"`
+
+	// Whether the mount added the RA namespaces extension itself, as
+	// opposed to copying it verbatim from a CSR.
+	RACredential bool `json:"ra_credential,omitempty"`
+}
+
 type aiaConfigEntry struct {
 	IssuingCertificates        []string `json:"issuing_certificates"`
 	CRLDistributionPoints      []string `json:"crl_distribution_points"`
@@ -738,12 +826,17 @@ func (sc *storageContext) deleteIssuer(id issuerID) (bool, error) {
 	}
 
 	wasDefault := false
-	if config.DefaultIssuerId == id {
-		wasDefault = true
-		// Overwrite the fetched default issuer as we're going to remove this
-		// entry.
-		config.fetchedDefault = issuerID("")
-		config.DefaultIssuerId = issuerID("")
+	if config.DefaultIssuerId == id || config.FallbackIssuerId == id {
+		if config.DefaultIssuerId == id {
+			wasDefault = true
+			// Overwrite the fetched default issuer as we're going to remove
+			// this entry.
+			config.fetchedDefault = issuerID("")
+			config.DefaultIssuerId = issuerID("")
+		}
+		if config.FallbackIssuerId == id {
+			config.FallbackIssuerId = issuerID("")
+		}
 		if err := sc.setIssuersConfig(config); err != nil {
 			return wasDefault, err
 		}
@@ -971,7 +1064,7 @@ func (sc *storageContext) _cleanupInternalCRLMapping(mapping *internalCRLConfigE
 		return fmt.Errorf("failed listing all CRLs: %w", err)
 	}
 	for _, crl := range list {
-		if crl == "config" || strings.HasSuffix(crl, "/") {
+		if crl == "config" || crl == "combined" || strings.HasSuffix(crl, "/") {
 			continue
 		}
 
@@ -1370,6 +1463,22 @@ func (sc *storageContext) getRevocationConfig() (*crlConfig, error) {
 	if result.Expiry == "" {
 		result.Expiry = defaultCrlConfig.Expiry
 	}
+	if result.MaxRevocationTimeSkew == "" {
+		result.MaxRevocationTimeSkew = defaultCrlConfig.MaxRevocationTimeSkew
+	}
+
+	if result.OcspMaxRequestBytes == 0 {
+		result.OcspMaxRequestBytes = defaultCrlConfig.OcspMaxRequestBytes
+	}
+	if result.BuildBatchSize == 0 {
+		result.BuildBatchSize = defaultCrlConfig.BuildBatchSize
+	}
+	if result.BuildParallelism == 0 {
+		result.BuildParallelism = defaultCrlConfig.BuildParallelism
+	}
+	if result.OcspExpiredBehavior == "" {
+		result.OcspExpiredBehavior = defaultCrlConfig.OcspExpiredBehavior
+	}
 
 	return &result, nil
 }
@@ -1472,6 +1581,141 @@ func (sc *storageContext) writeClusterConfig(config *clusterConfigEntry) error {
 	return sc.Storage.Put(sc.Context, entry)
 }
 
+func (sc *storageContext) getIssuanceConfig() (*issuanceConfigEntry, error) {
+	entry, err := sc.Storage.Get(sc.Context, issuanceConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var result issuanceConfigEntry
+	if entry == nil {
+		return &result, nil
+	}
+
+	if err = entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (sc *storageContext) writeIssuanceConfig(config *issuanceConfigEntry) error {
+	entry, err := logical.StorageEntryJSON(issuanceConfigPath, config)
+	if err != nil {
+		return err
+	}
+
+	return sc.Storage.Put(sc.Context, entry)
+}
+
+func (sc *storageContext) writeEscrowedKey(bundle *certutil.ParsedCertBundle) error {
+	cb, err := bundle.ToCertBundle()
+	if err != nil {
+		return fmt.Errorf("error converting raw cert bundle to cert bundle: %w", err)
+	}
+
+	entry, err := logical.StorageEntryJSON(escrowedKeyPrefix+normalizeSerial(cb.SerialNumber), &escrowedKeyEntry{
+		SerialNumber:   cb.SerialNumber,
+		PrivateKey:     cb.PrivateKey,
+		PrivateKeyType: cb.PrivateKeyType,
+		NotAfter:       bundle.Certificate.NotAfter,
+	})
+	if err != nil {
+		return err
+	}
+
+	return sc.Storage.Put(sc.Context, entry)
+}
+
+func (sc *storageContext) fetchEscrowedKey(serial string) (*escrowedKeyEntry, error) {
+	entry, err := sc.Storage.Get(sc.Context, escrowedKeyPrefix+normalizeSerial(serial))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result escrowedKeyEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (sc *storageContext) writeCertIssuance(serial string, issuance *certIssuanceEntry) error {
+	entry, err := logical.StorageEntryJSON(certIssuancePrefix+normalizeSerial(serial), issuance)
+	if err != nil {
+		return err
+	}
+
+	return sc.Storage.Put(sc.Context, entry)
+}
+
+func (sc *storageContext) fetchCertIssuance(serial string) (*certIssuanceEntry, error) {
+	entry, err := sc.Storage.Get(sc.Context, certIssuancePrefix+normalizeSerial(serial))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result certIssuanceEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// fetchRecordedCertIssuer returns the ID of the issuer recorded as having
+// signed the certificate, empty when none was recorded, along with that
+// issuer, nil when it has since been deleted.
+func (sc *storageContext) fetchRecordedCertIssuer(serial string) (issuerID, *issuerEntry, error) {
+	issuance, err := sc.fetchCertIssuance(serial)
+	if err != nil || issuance == nil || len(issuance.IssuerID) == 0 {
+		return "", nil, err
+	}
+
+	entry, err := sc.Storage.Get(sc.Context, issuerPrefix+issuance.IssuerID.String())
+	if err != nil {
+		return "", nil, err
+	}
+	if entry == nil {
+		return issuance.IssuerID, nil, nil
+	}
+
+	issuer, err := sc.fetchIssuerById(issuance.IssuerID)
+	if err != nil {
+		return "", nil, err
+	}
+	return issuance.IssuerID, issuer, nil
+}
+
+// writeOcspOnlyRevocationMarker records that the certificate was issued by
+// a role with ocsp_only_revocation, so its revocation is kept off the CRLs.
+func (sc *storageContext) writeOcspOnlyRevocationMarker(serial string) error {
+	// As with delta WAL entries, the presence of the entry is all that
+	// matters; no data is stored in it.
+	var marker struct{}
+	entry, err := logical.StorageEntryJSON(ocspOnlyRevocationPrefix+normalizeSerial(serial), marker)
+	if err != nil {
+		return err
+	}
+
+	return sc.Storage.Put(sc.Context, entry)
+}
+
+func (sc *storageContext) isOcspOnlyRevocation(serial string) (bool, error) {
+	entry, err := sc.Storage.Get(sc.Context, ocspOnlyRevocationPrefix+normalizeSerial(serial))
+	if err != nil {
+		return false, err
+	}
+	return entry != nil, nil
+}
+
 func (sc *storageContext) fetchRevocationInfo(serial string) (*revocationInfo, error) {
 	var revInfo *revocationInfo
 	revEntry, err := fetchCertBySerial(sc, revokedPath, serial)
@@ -155,6 +155,68 @@ func migrateStorage(ctx context.Context, b *backend, s logical.Storage) error {
 	return nil
 }
 
+// findLegacyBundleEntries returns the identifiers of the issuer and key
+// matching the legacy CA bundle's certificate and private key, left empty
+// when no such entry exists in the issuers store.
+func (sc *storageContext) findLegacyBundleEntries(bundle *certutil.CertBundle) (issuerID, keyID, error) {
+	var foundIssuer issuerID
+	var foundKey keyID
+
+	if len(bundle.Certificate) > 0 {
+		legacyCert, err := parseCertificateFromBytes([]byte(bundle.Certificate))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse legacy CA certificate: %w", err)
+		}
+
+		issuers, err := sc.listIssuers()
+		if err != nil {
+			return "", "", err
+		}
+		for _, identifier := range issuers {
+			issuer, err := sc.fetchIssuerById(identifier)
+			if err != nil {
+				return "", "", err
+			}
+			cert, err := issuer.GetCertificate()
+			if err != nil {
+				return "", "", err
+			}
+			if areCertificatesEqual(cert, legacyCert) {
+				foundIssuer = identifier
+				break
+			}
+		}
+	}
+
+	if len(bundle.PrivateKey) > 0 {
+		publicKey, err := getPublicKeyFromBytes([]byte(bundle.PrivateKey))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse legacy CA private key: %w", err)
+		}
+
+		keys, err := sc.listKeys()
+		if err != nil {
+			return "", "", err
+		}
+		for _, identifier := range keys {
+			key, err := sc.fetchKeyById(identifier)
+			if err != nil {
+				return "", "", err
+			}
+			equal, err := comparePublicKey(key, publicKey)
+			if err != nil {
+				return "", "", err
+			}
+			if equal {
+				foundKey = identifier
+				break
+			}
+		}
+	}
+
+	return foundIssuer, foundKey, nil
+}
+
 func computeHashOfLegacyBundle(bundle *certutil.CertBundle) (string, error) {
 	hasher := sha256.New()
 	// Generate an empty hash if the bundle does not exist.
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/testhelpers/schema"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	"github.com/stretchr/testify/require"
 )
@@ -869,6 +870,93 @@ func TestDeletedIssuersPostMigration(t *testing.T) {
 	require.Equal(t, postMigrationKeys, postDeletionKeys, "regression failed: expected second migration from v1->v2 to not introduce new keys")
 }
 
+func TestMigrateLegacyEndpoint(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	b, s := CreateBackendWithStorage(t)
+	sc := b.makeStorageContext(ctx, s)
+
+	// Without a legacy bundle, there is nothing to migrate.
+	resp, err := CBWrite(b, s, "legacy/migrate", nil)
+	requireSuccessNonNilResponse(t, resp, err)
+	schema.ValidateResponse(t, schema.GetResponseSchema(t, b.Route("legacy/migrate"), logical.UpdateOperation), resp, true)
+	require.False(t, resp.Data["legacy_bundle_found"].(bool))
+	require.False(t, resp.Data["migrated"].(bool))
+
+	// Migrate a legacy bundle on initialization, then delete the issuer
+	// and key it produced, leaving the legacy bundle unrepresented.
+	b.pkiStorageVersion.Store(0)
+	bundle := genCertBundle(t, b, s)
+	json, err := logical.StorageEntryJSON(legacyCertBundlePath, bundle)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, json))
+	require.NoError(t, b.initialize(ctx, &logical.InitializationRequest{Storage: s}))
+
+	issuerIds, err := sc.listIssuers()
+	require.NoError(t, err)
+	require.Len(t, issuerIds, 1)
+	keyIds, err := sc.listKeys()
+	require.NoError(t, err)
+	require.Len(t, keyIds, 1)
+	deleted, err := sc.deleteIssuer(issuerIds[0])
+	require.NoError(t, err)
+	require.True(t, deleted)
+	deleted, err = sc.deleteKey(keyIds[0])
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	// Initialization does not migrate again, as the bundle is unchanged.
+	require.NoError(t, b.initialize(ctx, &logical.InitializationRequest{Storage: s}))
+	issuerIds, err = sc.listIssuers()
+	require.NoError(t, err)
+	require.Empty(t, issuerIds)
+
+	// A dry run reports the bundle without writing anything.
+	resp, err = CBWrite(b, s, "legacy/migrate", map[string]interface{}{"dry_run": true})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.True(t, resp.Data["legacy_bundle_found"].(bool))
+	require.False(t, resp.Data["migrated"].(bool))
+	require.Empty(t, resp.Data["issuer_id"])
+	require.NotEmpty(t, resp.Warnings)
+	issuerIds, err = sc.listIssuers()
+	require.NoError(t, err)
+	require.Empty(t, issuerIds)
+
+	// Migrating creates an issuer and key and makes the issuer the default.
+	resp, err = CBWrite(b, s, "legacy/migrate", map[string]interface{}{"set_default": true})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.True(t, resp.Data["migrated"].(bool))
+	issuerId := resp.Data["issuer_id"].(string)
+	keyId := resp.Data["key_id"].(string)
+	require.NotEmpty(t, issuerId)
+	require.NotEmpty(t, keyId)
+	require.Equal(t, issuerId, resp.Data["default_issuer_id"])
+
+	issuer, err := sc.fetchIssuerById(issuerID(issuerId))
+	require.NoError(t, err)
+	require.Equal(t, keyID(keyId), issuer.KeyID)
+
+	// The ca fetch path serves the migrated legacy certificate and its CRL
+	// was rebuilt.
+	resp, err = CBRead(b, s, "cert/ca")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, strings.TrimSpace(bundle.Certificate), strings.TrimSpace(resp.Data["certificate"].(string)))
+	resp, err = CBRead(b, s, "issuer/"+issuerId+"/crl")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.NotEmpty(t, resp.Data["crl"])
+
+	// Migrating again is a no-op reporting the same entries.
+	resp, err = CBWrite(b, s, "legacy/migrate", nil)
+	requireSuccessNonNilResponse(t, resp, err)
+	require.False(t, resp.Data["migrated"].(bool))
+	require.Equal(t, issuerId, resp.Data["issuer_id"])
+	require.Equal(t, keyId, resp.Data["key_id"])
+	issuerIds, err = sc.listIssuers()
+	require.NoError(t, err)
+	require.Len(t, issuerIds, 1)
+	requireFileExists(t, sc, legacyCertBundlePath, nil)
+}
+
 // requireFailInMigration validate that we fail the operation with the appropriate error message to the end-user
 func requireFailInMigration(t *testing.T, b *backend, s logical.Storage, operation logical.Operation, path string) {
 	resp, err := b.HandleRequest(context.Background(), &logical.Request{
@@ -298,6 +298,16 @@ func requireSuccessNilResponse(t *testing.T, resp *logical.Response, err error,
 	}
 }
 
+// requireCertNotFound asserts that an API read of cert/:serial got the 404
+// response for a missing certificate.
+func requireCertNotFound(t *testing.T, resp *api.Secret, err error) {
+	t.Helper()
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Contains(t, resp.Data["error"], "not found")
+}
+
 func getCRLNumber(t *testing.T, crl pkix.TBSCertificateList) int {
 	t.Helper()
 
@@ -4,11 +4,16 @@
 package pki
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +30,16 @@ const (
 	// Constants for If-Modified-Since operation
 	headerIfModifiedSince = "If-Modified-Since"
 	headerLastModified    = "Last-Modified"
+
+	// Constants for If-None-Match operation
+	headerIfNoneMatch = "If-None-Match"
+	headerETag        = "ETag"
+
+	// Constants for content negotiation of CA and CRL fetches
+	headerAccept          = "Accept"
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerVary            = "Vary"
 )
 
 var (
@@ -54,6 +69,79 @@ func denormalizeSerial(serial string) string {
 	return strings.ReplaceAll(strings.ToLower(serial), "-", ":")
 }
 
+// contiguousSerialRegex matches serial numbers given as a contiguous hex
+// string without separators, as printed by openssl x509 -serial.
+var contiguousSerialRegex = regexp.MustCompile(`^[0-9a-fA-F]{3,}$`)
+
+// splitContiguousSerial converts a contiguous hex serial number into its
+// colon-separated form, leaving any other value unchanged. It applies to
+// serial numbers given by users, not to the storage keys derived from them.
+func splitContiguousSerial(serial string) string {
+	if !contiguousSerialRegex.MatchString(serial) {
+		return serial
+	}
+	if len(serial)%2 == 1 {
+		serial = "0" + serial
+	}
+
+	octets := make([]string, 0, len(serial)/2)
+	for i := 0; i < len(serial); i += 2 {
+		octets = append(octets, serial[i:i+2])
+	}
+	return strings.Join(octets, ":")
+}
+
+// serialCursorRegex matches certificate serial numbers, and prefixes of
+// them, in either their colon- or hyphen-separated form.
+var serialCursorRegex = regexp.MustCompile(`^[0-9a-fA-F]{1,2}([:-][0-9a-fA-F]{1,2})*[:-]?$`)
+
+// normalizeSerialCursor validates an after cursor given to a certificate
+// listing and converts it to the form certificates are stored under, so
+// that it sorts consistently against the stored entries.
+func normalizeSerialCursor(after string) (string, error) {
+	if len(after) == 0 {
+		return "", nil
+	}
+	if !serialCursorRegex.MatchString(after) {
+		return "", fmt.Errorf("invalid after cursor %q: expected a certificate serial number such as 1a:2b:3c", after)
+	}
+	return normalizeSerial(after), nil
+}
+
+// serialPrefixRegex matches hex prefixes of certificate serial numbers,
+// either contiguous or with colon- or hyphen-separated octets.
+var serialPrefixRegex = regexp.MustCompile(`^[0-9a-fA-F]+([:-][0-9a-fA-F]+)*[:-]?$`)
+
+// normalizeSerialPrefix validates a prefix given to a certificate listing
+// and converts it to the prefix of the keys certificates are stored under,
+// grouping its hex digits into hyphen-separated octets.
+func normalizeSerialPrefix(prefix string) (string, error) {
+	if len(prefix) == 0 {
+		return "", nil
+	}
+	if !serialPrefixRegex.MatchString(prefix) {
+		return "", fmt.Errorf("invalid prefix %q: expected hex digits of a certificate serial number such as 1a:2b or 1a2b", prefix)
+	}
+
+	digits := strings.ToLower(strings.NewReplacer(":", "", "-", "").Replace(prefix))
+	var octets []string
+	for len(digits) > 2 {
+		octets = append(octets, digits[:2])
+		digits = digits[2:]
+	}
+	return strings.Join(append(octets, digits), "-"), nil
+}
+
+// parseKeyIdentifier decodes a key identifier given as hex, either
+// contiguous or with colon-separated octets as OpenSSL prints them.
+func parseKeyIdentifier(value string) ([]byte, error) {
+	keyId, err := hex.DecodeString(strings.ReplaceAll(value, ":", ""))
+	if err != nil || len(keyId) == 0 {
+		return nil, fmt.Errorf("invalid key identifier %q: expected hex such as 1a:2b:3c or 1a2b3c", value)
+	}
+	return keyId, nil
+}
+
 func serialToBigInt(serial string) (*big.Int, bool) {
 	norm := normalizeSerial(serial)
 	hex := strings.ReplaceAll(norm, "-", "")
@@ -192,12 +280,15 @@ const (
 	ifModifiedCA                         = iota
 	ifModifiedCRL                        = iota
 	ifModifiedDeltaCRL                   = iota
+	ifModifiedCert                       = iota
+	ifModifiedCAChain                    = iota
 )
 
 type IfModifiedSinceHelper struct {
 	req       *logical.Request
 	reqType   ifModifiedReqType
 	issuerRef issuerID
+	serial    string
 }
 
 func sendNotModifiedResponseIfNecessary(helper *IfModifiedSinceHelper, sc *storageContext, resp *logical.Response) (bool, error) {
@@ -206,6 +297,12 @@ func sendNotModifiedResponseIfNecessary(helper *IfModifiedSinceHelper, sc *stora
 		return false, nil
 	}
 
+	// Per RFC 9110, If-Modified-Since is ignored in favor of If-None-Match
+	// on the requests which support entity tags.
+	if helper.reqType != ifModifiedCert && hasHeader(headerIfNoneMatch, helper.req) {
+		return false, nil
+	}
+
 	before, err := sc.isIfModifiedSinceBeforeLastModified(helper, responseHeaders)
 	if err != nil {
 		return false, err
@@ -225,6 +322,95 @@ func sendNotModifiedResponseIfNecessary(helper *IfModifiedSinceHelper, sc *stora
 	return true, nil
 }
 
+// derETag returns the entity tag of a fetched CA certificate, chain or CRL:
+// the quoted hex SHA-256 digest of its DER encoding.
+func derETag(der []byte) string {
+	digest := sha256.Sum256(der)
+	return `"` + hex.EncodeToString(digest[:]) + `"`
+}
+
+// ifNoneMatchMatches reports whether the request's If-None-Match header
+// matches the given entity tag, using the weak comparison RFC 9110 requires
+// for this header.
+func ifNoneMatchMatches(req *logical.Request, etag string) bool {
+	for _, value := range req.Headers[headerIfNoneMatch] {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptsPEM reports whether the request's Accept header prefers a PEM
+// encoding over derType, the media type of the DER encoding. The first
+// media range naming either encoding wins; ranges with a zero quality are
+// skipped, and wildcards or an absent header select DER.
+func acceptsPEM(req *logical.Request, derType string) bool {
+	for _, mediaType := range acceptedHeaderValues(req, headerAccept) {
+		switch mediaType {
+		case "application/x-pem-file", "application/pem-certificate-chain":
+			return true
+		case derType:
+			return false
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header allows a
+// gzip-compressed response. Wildcards are not honored, leaving responses
+// uncompressed unless gzip is named explicitly.
+func acceptsGzip(req *logical.Request) bool {
+	for _, coding := range acceptedHeaderValues(req, headerAcceptEncoding) {
+		if coding == "gzip" || coding == "x-gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptedHeaderValues returns the lower-cased values of a comma-separated
+// negotiation header such as Accept, in order, without their parameters.
+// Values with a zero quality are skipped.
+func acceptedHeaderValues(req *logical.Request, header string) []string {
+	var accepted []string
+	for _, value := range req.Headers[header] {
+		for _, element := range strings.Split(value, ",") {
+			params := strings.Split(element, ";")
+
+			rejected := false
+			for _, param := range params[1:] {
+				name, quality, found := strings.Cut(strings.TrimSpace(param), "=")
+				if found && strings.EqualFold(name, "q") {
+					if q, err := strconv.ParseFloat(strings.TrimSpace(quality), 64); err == nil && q == 0 {
+						rejected = true
+					}
+				}
+			}
+			if !rejected {
+				accepted = append(accepted, strings.ToLower(strings.TrimSpace(params[0])))
+			}
+		}
+	}
+	return accepted
+}
+
+// gzipBytes compresses data with gzip.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (sc *storageContext) isIfModifiedSinceBeforeLastModified(helper *IfModifiedSinceHelper, responseHeaders map[string][]string) (bool, error) {
 	// False return --> we were last modified _before_ the requester's
 	// time --> keep using the cached copy and return 304.
@@ -268,6 +454,58 @@ func (sc *storageContext) isIfModifiedSinceBeforeLastModified(helper *IfModified
 		}
 
 		lastModified = issuer.LastModified
+	case ifModifiedCAChain:
+		issuerId, err := sc.resolveIssuerReference(string(helper.issuerRef))
+		if err != nil {
+			return false, err
+		}
+
+		issuer, err := sc.fetchIssuerById(issuerId)
+		if err != nil {
+			return false, err
+		}
+
+		lastModified, err = sc.caChainLastModified(issuer)
+		if err != nil {
+			return false, err
+		}
+	case ifModifiedCert:
+		// Revocation metadata can change at any time, so revoked
+		// certificates are never considered unmodified.
+		revokedEntry, err := fetchCertBySerial(sc, "revoked/", helper.serial)
+		if err != nil {
+			return false, err
+		}
+		if revokedEntry != nil {
+			return false, nil
+		}
+
+		// Nor are certificates recently released from hold, whose response
+		// changed long after they became valid.
+		releasedEntry, err := sc.Storage.Get(sc.Context, releasedPath+normalizeSerial(helper.serial))
+		if err != nil {
+			return false, err
+		}
+		if releasedEntry != nil {
+			return false, nil
+		}
+
+		certEntry, err := fetchCertBySerial(sc, "certs/", helper.serial)
+		if err != nil {
+			return false, err
+		}
+		if certEntry == nil {
+			return false, nil
+		}
+
+		// Certificates are immutable once issued, so they were last
+		// modified when they became valid. Parse failures are left for the
+		// fetch itself to report.
+		cert, err := x509.ParseCertificate(certEntry.Value)
+		if err != nil {
+			return false, nil
+		}
+		lastModified = cert.NotBefore
 	default:
 		return false, fmt.Errorf("unknown if-modified-since request type: %v", helper.reqType)
 	}
@@ -280,10 +518,139 @@ func (sc *storageContext) isIfModifiedSinceBeforeLastModified(helper *IfModified
 	return false, nil
 }
 
-func addWarnings(resp *logical.Response, warnings []string) *logical.Response {
+// caChainLastModified returns when the CA chain of the issuer was last
+// modified: the latest modification time of the issuers of the mount in it.
+// Chains are only rebuilt when issuers change, so this changes whenever the
+// chain does. A zero time is returned when any of them lacks one.
+func (sc *storageContext) caChainLastModified(issuer *issuerEntry) (time.Time, error) {
+	inChain := make(map[string]bool, len(issuer.CAChain))
+	for _, pemCert := range issuer.CAChain {
+		inChain[strings.TrimSpace(pemCert)] = true
+	}
+
+	issuers, err := sc.listIssuers()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	lastModified := issuer.LastModified
+	for _, issuerId := range issuers {
+		if issuerId == issuer.ID {
+			continue
+		}
+
+		candidate, err := sc.fetchIssuerById(issuerId)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !inChain[strings.TrimSpace(candidate.Certificate)] {
+			continue
+		}
+		if candidate.LastModified.IsZero() {
+			return time.Time{}, nil
+		}
+		if candidate.LastModified.After(lastModified) {
+			lastModified = candidate.LastModified
+		}
+	}
+
+	return lastModified, nil
+}
+
+const (
+	structuredWarningsParam = "structured_warnings"
+
+	warningCodeGeneric                    = "warning"
+	warningCodeTTLTruncated               = "ttl_truncated"
+	warningCodeKeyParamsIgnored           = "key_params_ignored"
+	warningCodeCSRCommonNameIgnored       = "csr_common_name_ignored"
+	warningCodeCSRSANsIgnored             = "csr_sans_ignored"
+	warningCodeCSRBasicConstraintsIgnored = "csr_basic_constraints_ignored"
+	warningCodeAIAMissing                 = "aia_missing"
+	warningCodeAIATemplateIgnored         = "aia_template_ignored"
+	warningCodeAIATemplateInvalid         = "aia_template_invalid"
+	warningCodeEmptySubject               = "empty_subject"
+	warningCodeZeroMaxPathLength          = "zero_max_path_length"
+	warningCodeNotAfterBeyondCA           = "not_after_beyond_ca"
+	warningCodeCRLRebuild                 = "crl_rebuild"
+	warningCodeDeprecatedSignature        = "deprecated_signature"
+	warningCodeWeakKey                    = "weak_key"
+)
+
+// structuredWarning is a response warning along with its machine-readable
+// code and, where applicable, the request field which triggered it. Codes
+// are attached where the warning is emitted, never derived from its text.
+type structuredWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+func newWarning(code string, field string, message string) structuredWarning {
+	return structuredWarning{Code: code, Message: message, Field: field}
+}
+
+func addWarnings(resp *logical.Response, warnings []structuredWarning) *logical.Response {
 	for _, warning := range warnings {
-		resp.AddWarning(warning)
+		addCodedWarning(resp, warning)
+	}
+	return resp
+}
+
+// addCodedWarning adds the warning's message to the response's plain-text
+// warnings and records its code under structured_warnings. Handlers using
+// this must pass the response through addStructuredWarnings, which drops the
+// recorded codes unless the caller requested them.
+func addCodedWarning(resp *logical.Response, warning structuredWarning) {
+	resp.AddWarning(warning.Message)
+
+	if resp.Data == nil {
+		resp.Data = map[string]interface{}{}
+	}
+	recorded, _ := resp.Data[structuredWarningsParam].([]structuredWarning)
+	resp.Data[structuredWarningsParam] = append(recorded, warning)
+}
+
+// addStructuredWarnings returns the response's plain-text warnings in the
+// structured_warnings response field when requested by the caller, with the
+// codes recorded by addCodedWarning; other warnings get the generic code. The
+// plain warnings are left in place for compatibility. This is safe to call
+// more than once on the same response as the structured list is rebuilt on
+// each call.
+func addStructuredWarnings(resp *logical.Response, data *framework.FieldData) *logical.Response {
+	if resp == nil {
+		return resp
+	}
+
+	recorded, _ := resp.Data[structuredWarningsParam].([]structuredWarning)
+	delete(resp.Data, structuredWarningsParam)
+
+	if resp.IsError() {
+		return resp
+	}
+
+	requested, ok := data.GetOk(structuredWarningsParam)
+	if !ok || !requested.(bool) {
+		return resp
+	}
+
+	structured := make([]structuredWarning, 0, len(resp.Warnings))
+	for _, message := range resp.Warnings {
+		warning := newWarning(warningCodeGeneric, "", message)
+		for index, candidate := range recorded {
+			if candidate.Message == message {
+				warning = candidate
+				recorded = append(recorded[:index], recorded[index+1:]...)
+				break
+			}
+		}
+		structured = append(structured, warning)
+	}
+
+	if resp.Data == nil {
+		resp.Data = map[string]interface{}{}
 	}
+	resp.Data[structuredWarningsParam] = structured
 	return resp
 }
 
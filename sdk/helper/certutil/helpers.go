@@ -140,6 +140,9 @@ func getSubjectKeyIDFromBundle(data *CreationBundle) ([]byte, error) {
 		return data.Params.SKID, nil
 	}
 
+	if data.CSR == nil {
+		return GetSubjectKeyID(data.PublicKey)
+	}
 	return GetSubjectKeyID(data.CSR.PublicKey)
 }
 
@@ -539,13 +542,19 @@ func AddExtKeyUsageOids(data *CreationBundle, certTemplate *x509.Certificate) {
 }
 
 func HandleOtherCSRSANs(in *x509.CertificateRequest, sans map[string][]string) error {
+	return HandleExtraCSRSANs(in, sans, nil)
+}
+
+// HandleExtraCSRSANs is like HandleOtherCSRSANs, but additionally encodes
+// the given directoryName SANs.
+func HandleExtraCSRSANs(in *x509.CertificateRequest, sans map[string][]string, directoryNames []pkix.RDNSequence) error {
 	certTemplate := &x509.Certificate{
 		DNSNames:       in.DNSNames,
 		IPAddresses:    in.IPAddresses,
 		EmailAddresses: in.EmailAddresses,
 		URIs:           in.URIs,
 	}
-	if err := HandleOtherSANs(certTemplate, sans); err != nil {
+	if err := HandleExtraSANs(certTemplate, sans, directoryNames); err != nil {
 		return err
 	}
 	if len(certTemplate.ExtraExtensions) > 0 {
@@ -557,9 +566,41 @@ func HandleOtherCSRSANs(in *x509.CertificateRequest, sans map[string][]string) e
 }
 
 func HandleOtherSANs(in *x509.Certificate, sans map[string][]string) error {
+	return HandleExtraSANs(in, sans, nil)
+}
+
+// OrderSubjectRDNs returns the DER-encoded subject with its RDNs reordered so
+// that those of the given attribute types come first, in the given order.
+// RDNs of any other type follow in their original order.
+func OrderSubjectRDNs(subject pkix.Name, order []asn1.ObjectIdentifier) ([]byte, error) {
+	rdns := subject.ToRDNSequence()
+	used := make([]bool, len(rdns))
+
+	ordered := make(pkix.RDNSequence, 0, len(rdns))
+	for _, oid := range order {
+		for index, rdn := range rdns {
+			if !used[index] && len(rdn) > 0 && rdn[0].Type.Equal(oid) {
+				ordered = append(ordered, rdn)
+				used[index] = true
+			}
+		}
+	}
+	for index, rdn := range rdns {
+		if !used[index] {
+			ordered = append(ordered, rdn)
+		}
+	}
+
+	return asn1.Marshal(ordered)
+}
+
+// HandleExtraSANs encodes the SAN types which the Go stdlib does not support
+// (other names and directory names) into a subjectAltName extension on the
+// certificate template, alongside any of the stdlib-supported SANs.
+func HandleExtraSANs(in *x509.Certificate, sans map[string][]string, directoryNames []pkix.RDNSequence) error {
 	// If other SANs is empty we return which causes normal Go stdlib parsing
 	// of the other SAN types
-	if len(sans) == 0 {
+	if len(sans) == 0 && len(directoryNames) == 0 {
 		return nil
 	}
 
@@ -593,6 +634,15 @@ func HandleOtherSANs(in *x509.Certificate, sans map[string][]string) error {
 		}
 	}
 
+	// Name is a CHOICE, so the directoryName tag is always EXPLICIT.
+	for _, name := range directoryNames {
+		m, err := asn1.Marshal(name)
+		if err != nil {
+			return err
+		}
+		rawValues = append(rawValues, asn1.RawValue{Tag: nameTypeDirectory, Class: 2, IsCompound: true, Bytes: m})
+	}
+
 	// If other SANs is empty we return which causes normal Go stdlib parsing
 	// of the other SAN types
 	if len(rawValues) == 0 {
@@ -620,10 +670,11 @@ func HandleOtherSANs(in *x509.Certificate, sans map[string][]string) error {
 // Note: Taken from the Go source code since it's not public, and used in the
 // modified function below (which also uses these consts upstream)
 const (
-	nameTypeEmail = 1
-	nameTypeDNS   = 2
-	nameTypeURI   = 6
-	nameTypeIP    = 7
+	nameTypeEmail     = 1
+	nameTypeDNS       = 2
+	nameTypeDirectory = 4
+	nameTypeURI       = 6
+	nameTypeIP        = 7
 )
 
 // Note: Taken from the Go source code since it's not public, plus changed to not marshal
@@ -908,10 +959,17 @@ func createCertificate(data *CreationBundle, randReader io.Reader, privateKeyGen
 		URIs:           data.Params.URIs,
 	}
 
-	if err := HandleOtherSANs(certTemplate, data.Params.OtherSANs); err != nil {
+	if err := HandleExtraSANs(certTemplate, data.Params.OtherSANs, data.Params.DirectoryNames); err != nil {
 		return nil, errutil.InternalError{Err: errwrap.Wrapf("error marshaling other SANs: {{err}}", err).Error()}
 	}
 
+	if len(data.Params.SubjectDNOrder) > 0 {
+		certTemplate.RawSubject, err = OrderSubjectRDNs(certTemplate.Subject, data.Params.SubjectDNOrder)
+		if err != nil {
+			return nil, errutil.InternalError{Err: errwrap.Wrapf("error marshaling subject: {{err}}", err).Error()}
+		}
+	}
+
 	// Add this before calling addKeyUsages
 	if data.SigningBundle == nil {
 		certTemplate.IsCA = true
@@ -932,6 +990,8 @@ func createCertificate(data *CreationBundle, randReader io.Reader, privateKeyGen
 
 	AddExtKeyUsageOids(data, certTemplate)
 
+	certTemplate.ExtraExtensions = append(certTemplate.ExtraExtensions, data.Params.ExtraExtensions...)
+
 	certTemplate.IssuingCertificateURL = data.Params.URLs.IssuingCertificates
 	certTemplate.CRLDistributionPoints = data.Params.URLs.CRLDistributionPoints
 	certTemplate.OCSPServer = data.Params.URLs.OCSPServers
@@ -1098,7 +1158,7 @@ func createCSR(data *CreationBundle, addBasicConstraints bool, randReader io.Rea
 		URIs:           data.Params.URIs,
 	}
 
-	if err := HandleOtherCSRSANs(csrTemplate, data.Params.OtherSANs); err != nil {
+	if err := HandleExtraCSRSANs(csrTemplate, data.Params.OtherSANs, data.Params.DirectoryNames); err != nil {
 		return nil, errutil.InternalError{Err: errwrap.Wrapf("error marshaling other SANs: {{err}}", err).Error()}
 	}
 
@@ -1169,13 +1229,19 @@ func signCertificate(data *CreationBundle, randReader io.Reader) (*ParsedCertBun
 		return nil, errutil.UserError{Err: "nil parameters given to signCertificate"}
 	case data.SigningBundle == nil:
 		return nil, errutil.UserError{Err: "nil signing bundle given to signCertificate"}
-	case data.CSR == nil:
+	case data.CSR == nil && data.PublicKey == nil:
 		return nil, errutil.UserError{Err: "nil csr given to signCertificate"}
+	case data.CSR == nil && data.Params.UseCSRValues:
+		return nil, errutil.UserError{Err: "csr values requested without a csr"}
 	}
 
-	err := data.CSR.CheckSignature()
-	if err != nil {
-		return nil, errutil.UserError{Err: "request signature invalid"}
+	publicKey := data.PublicKey
+	if data.CSR != nil {
+		err := data.CSR.CheckSignature()
+		if err != nil {
+			return nil, errutil.UserError{Err: "request signature invalid"}
+		}
+		publicKey = data.CSR.PublicKey
 	}
 
 	result := &ParsedCertBundle{}
@@ -1229,7 +1295,7 @@ func signCertificate(data *CreationBundle, randReader io.Reader) (*ParsedCertBun
 		certTemplate.URIs = data.CSR.URIs
 
 		for _, name := range data.CSR.Extensions {
-			if !name.Id.Equal(ExtensionBasicConstraintsOID) && !(len(data.Params.OtherSANs) > 0 && name.Id.Equal(ExtensionSubjectAltNameOID)) {
+			if !name.Id.Equal(ExtensionBasicConstraintsOID) && !((len(data.Params.OtherSANs) > 0 || len(data.Params.DirectoryNames) > 0) && name.Id.Equal(ExtensionSubjectAltNameOID)) {
 				certTemplate.ExtraExtensions = append(certTemplate.ExtraExtensions, name)
 			}
 		}
@@ -1241,16 +1307,25 @@ func signCertificate(data *CreationBundle, randReader io.Reader) (*ParsedCertBun
 		certTemplate.URIs = data.Params.URIs
 	}
 
-	if err := HandleOtherSANs(certTemplate, data.Params.OtherSANs); err != nil {
+	if err := HandleExtraSANs(certTemplate, data.Params.OtherSANs, data.Params.DirectoryNames); err != nil {
 		return nil, errutil.InternalError{Err: errwrap.Wrapf("error marshaling other SANs: {{err}}", err).Error()}
 	}
 
+	if len(data.Params.SubjectDNOrder) > 0 {
+		certTemplate.RawSubject, err = OrderSubjectRDNs(certTemplate.Subject, data.Params.SubjectDNOrder)
+		if err != nil {
+			return nil, errutil.InternalError{Err: errwrap.Wrapf("error marshaling subject: {{err}}", err).Error()}
+		}
+	}
+
 	AddPolicyIdentifiers(data, certTemplate)
 
 	AddKeyUsages(data, certTemplate)
 
 	AddExtKeyUsageOids(data, certTemplate)
 
+	certTemplate.ExtraExtensions = append(certTemplate.ExtraExtensions, data.Params.ExtraExtensions...)
+
 	var certBytes []byte
 
 	certTemplate.IssuingCertificateURL = data.Params.URLs.IssuingCertificates
@@ -1288,7 +1363,7 @@ func signCertificate(data *CreationBundle, randReader io.Reader) (*ParsedCertBun
 		certTemplate.PermittedDNSDomainsCritical = true
 	}
 
-	certBytes, err = x509.CreateCertificate(randReader, certTemplate, caCert, data.CSR.PublicKey, data.SigningBundle.PrivateKey)
+	certBytes, err = x509.CreateCertificate(randReader, certTemplate, caCert, publicKey, data.SigningBundle.PrivateKey)
 	if err != nil {
 		return nil, errutil.InternalError{Err: fmt.Sprintf("unable to create certificate: %s", err)}
 	}
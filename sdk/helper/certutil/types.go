@@ -771,6 +771,8 @@ type CreationParameters struct {
 	IPAddresses                   []net.IP
 	URIs                          []*url.URL
 	OtherSANs                     map[string][]string
+	DirectoryNames                []pkix.RDNSequence
+	SubjectDNOrder                []asn1.ObjectIdentifier
 	IsCA                          bool
 	KeyType                       string
 	KeyBits                       int
@@ -801,12 +803,20 @@ type CreationParameters struct {
 
 	// The explicit SKID to use; especially useful for cross-signing.
 	SKID []byte
+
+	// Additional extensions to add to the certificate verbatim
+	ExtraExtensions []pkix.Extension
 }
 
 type CreationBundle struct {
 	Params        *CreationParameters
 	SigningBundle *CAInfoBundle
 	CSR           *x509.CertificateRequest
+
+	// The public key to certify when signing without a CSR; as no proof
+	// of possession is verified, callers are responsible for authorizing
+	// such requests.
+	PublicKey crypto.PublicKey
 }
 
 // addKeyUsages adds appropriate key usages to the template given the creation